@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"article/internal/auth"
+	"article/internal/models"
+)
+
+// runKeys runs keys subcommand managing API keys of store
+func runKeys(store models.APIKeyStore, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: keys create|list|revoke|rotate")
+	}
+
+	flags := flag.NewFlagSet("keys "+args[0], flag.ExitOnError)
+	name := flags.String("name", "", "name of the client using the key")
+	scopes := flags.String("scopes", auth.ScopeRead, "comma separated scopes granted to the key")
+	expiresIn := flags.Duration("expires-in", 0, "lifetime of the key, zero never expires")
+	overlap := flags.Duration("overlap", 24*time.Hour, "how long a rotated key keeps working")
+
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	keys := auth.NewAPIKeys(store)
+
+	switch args[0] {
+	case "create":
+		if strings.TrimSpace(*name) == "" {
+			return errors.New("usage: keys create -name name [-scopes scopes] [-expires-in duration]")
+		}
+
+		granted, err := auth.ParseScopes(*scopes)
+		if err != nil {
+			return err
+		}
+
+		token, key, err := keys.Create(ctx, strings.TrimSpace(*name), granted, *expiresIn)
+		if err != nil {
+			return err
+		}
+
+		printIssued(token, key)
+
+		return nil
+	case "list":
+		list, err := keys.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tCREATED AT\tEXPIRES AT\tLAST USED AT\tREVOKED AT")
+
+		for _, k := range list {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", k.ID, k.Name, k.Prefix, strings.Join(k.Scopes, ","),
+				formatTime(&k.CreatedAt), formatTime(k.ExpiresAt), formatTime(k.LastUsedAt), formatTime(k.RevokedAt))
+		}
+
+		return w.Flush()
+	case "revoke":
+		id, err := keyID(flags, "revoke")
+		if err != nil {
+			return err
+		}
+
+		err = keys.Revoke(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("revoked key", id)
+
+		return nil
+	case "rotate":
+		id, err := keyID(flags, "rotate [-overlap duration]")
+		if err != nil {
+			return err
+		}
+
+		token, key, err := keys.Rotate(ctx, id, *overlap)
+		if err != nil {
+			return err
+		}
+
+		printIssued(token, key)
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown keys command %s", args[0])
+}
+
+// keyID returns the key id argument of command
+func keyID(flags *flag.FlagSet, command string) (int, error) {
+	if flags.NArg() != 1 {
+		return 0, fmt.Errorf("usage: keys %s id", command)
+	}
+
+	id, err := strconv.Atoi(flags.Arg(0))
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("invalid key id %s", flags.Arg(0))
+	}
+
+	return id, nil
+}
+
+// printIssued prints an issued key, it cannot be shown again
+func printIssued(token string, key *models.APIKey) {
+	fmt.Printf("created key %d %s expiring %s\n", key.ID, key.Name, formatTime(key.ExpiresAt))
+	fmt.Println(token)
+	fmt.Println("store the key now, it cannot be shown again")
+}
+
+// formatTime formats t for listings, nil is printed as -
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
@@ -1,44 +1,241 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
-	"net/http"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"article/internal/auth"
+	"article/internal/config"
 	"article/internal/database"
 	"article/internal/handler"
+	"article/internal/health"
+	"article/internal/logging"
+	"article/internal/metrics"
 	"article/internal/models"
 	"article/internal/routes"
-
-	_ "github.com/go-sql-driver/mysql"
+	"article/internal/server"
+	"article/internal/tracing"
 )
 
-const port = 8080
+func main() {
+	// flags come before the subcommand e.g. -config article.yaml migrate up
+	cfg, args, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 
-var app *handler.Application
+	if err != nil {
+		log.Fatalln("error loading config : ", err)
+	}
 
-// init
-func init() {
-	// initialize database
-	db, err := database.InitDB()
+	logger, err := logging.New(os.Stderr, cfg.Log.Config)
 	if err != nil {
-		panic(err)
+		log.Fatalln("error creating logger : ", err)
 	}
 
-	models := models.NewModels(db)
+	// packages without a logger of their own log through the default one
+	slog.SetDefault(logger)
 
-	app = handler.New(models)
+	err = run(cfg, args, logger)
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
 }
 
-func main() {
-	logger := log.New(log.Default().Writer(), "logger: ", 1)
+// run runs subcommand of args or the server when there is none
+func run(cfg config.Config, args []string, logger *slog.Logger) error {
+	// printing config needs no database
+	if len(args) > 0 && args[0] == "config" {
+		if len(args) != 2 || args[1] != "print" {
+			return errors.New("usage: config print")
+		}
+
+		return config.Print(os.Stdout, cfg)
+	}
+
+	// creating migration files needs no database either
+	if len(args) > 1 && args[0] == "migrate" && args[1] == "create" {
+		if cfg.Database.Driver == config.Memory {
+			return errors.New("migrations need a database, database driver is set to memory")
+		}
+
+		err := runMigrateCreate(cfg.Database.Driver, args[2:])
+		if err != nil {
+			return fmt.Errorf("error creating migration : %w", err)
+		}
+
+		return nil
+	}
+
+	// SIGINT or SIGTERM starts shutdown, a second one kills the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// the database layer logs through the logger of ctx
+	ctx = logging.WithLogger(ctx, logger)
+
+	models, db, err := openStore(ctx, cfg.Database)
+	if err != nil {
+		return fmt.Errorf("error opening store : %w", err)
+	}
+
+	if db != nil {
+		defer func() {
+			err := db.Close()
+			if err != nil {
+				logger.Error("error closing database", logging.Err(err))
+			}
+		}()
+	}
+
+	// run subcommand
+	if len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			if db == nil {
+				return errors.New("migrations need a database, database driver is set to memory")
+			}
+
+			err = runMigrate(db, cfg.Database.Driver, args[1:])
+			if err != nil {
+				return fmt.Errorf("error running migrations : %w", err)
+			}
+		case "keys":
+			// keys of the memory store do not outlive the command
+			if db == nil {
+				return errors.New("api keys need a database, database driver is set to memory")
+			}
+
+			err = checkSchema(logger, db, cfg.Database.Driver, cfg.Features.AutoMigrate)
+			if err != nil {
+				return fmt.Errorf("error checking database schema : %w", err)
+			}
+
+			err = runKeys(models.APIKey, args[1:])
+			if err != nil {
+				return fmt.Errorf("error managing api keys : %w", err)
+			}
+		default:
+			return fmt.Errorf("unknown command %s", args[0])
+		}
+
+		return nil
+	}
+
+	// verify schema is up to date
+	if db != nil {
+		err = checkSchema(logger, db, cfg.Database.Driver, cfg.Features.AutoMigrate)
+		if err != nil {
+			return fmt.Errorf("error checking database schema : %w", err)
+		}
+	}
+
+	// spans are exported as configured, pending spans are flushed on exit
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("error setting up tracing : %w", err)
+	}
+
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+
+		err := shutdownTracing(ctx)
+		if err != nil {
+			logger.Error("error flushing traces", logging.Err(err))
+		}
+	}()
+
+	models.Article = tracing.InstrumentArticleStore(models.Article)
+	models.Author = tracing.InstrumentAuthorStore(models.Author)
+	models.APIKey = tracing.InstrumentAPIKeyStore(models.APIKey)
+
+	// store operations and the connection pool are instrumented along with requests
+	var reg *metrics.Registry
+	if cfg.Features.Metrics {
+		reg = metrics.NewRegistry()
+		models.Article = metrics.InstrumentArticleStore(reg, models.Article)
+		models.Author = metrics.InstrumentAuthorStore(reg, models.Author)
+		models.APIKey = metrics.InstrumentAPIKeyStore(reg, models.APIKey)
+
+		if db != nil {
+			metrics.RegisterDBStats(reg, db)
+		}
+	}
+
+	app := handler.NewWithConfig(models, cfg)
+
+	if db != nil {
+		app.RegisterCheck(health.Check{Name: "database", Critical: true, Func: health.Ping(db)})
+		app.RegisterCheck(health.Check{Name: "migrations", Critical: true, Func: health.Migrations(newMigrator(db, cfg.Database.Driver))})
+	}
+
+	// writes need a bearer token verified by the configured keys or an API key
+	verifier, err := auth.NewVerifier(cfg.Auth.Config)
+	if err != nil {
+		return fmt.Errorf("error setting up authentication : %w", err)
+	}
+
+	if !verifier.Enabled() {
+		logger.Warn("no token verification keys configured, bearer tokens are refused")
+	}
+
+	schemes := auth.Schemes{
+		auth.SchemeBearer: verifier,
+		auth.SchemeAPIKey: auth.NewAPIKeysWithTimeout(models.APIKey, cfg.Database.QueryTimeout),
+	}
 
 	// register routes
-	r := routes.InitRoutes(app)
+	srv := server.New(cfg.Server, routes.InitRoutes(app, cfg.CORS, reg, logger, schemes), logger)
 
-	logger.Println("server listening on port :", port)
-	err := http.ListenAndServe(fmt.Sprintf(":%v", port), r)
+	ln, err := net.Listen("tcp", cfg.Server.Addr)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("error listening : %w", err)
+	}
+
+	logger.Info("server listening", slog.String("addr", ln.Addr().String()))
+
+	// stop restores default signal handling so a second signal during shutdown kills the process
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return server.Serve(ctx, srv, ln, cfg.Server, app.Drain, logger)
+}
+
+// openStore returns models of the configured driver, db is nil for the in-memory store
+func openStore(ctx context.Context, cfg config.Database) (*models.Models, *sql.DB, error) {
+	// in-memory store needs no database, it is optionally persisted to a snapshot file
+	if cfg.Driver == config.Memory {
+		models, err := models.NewMemoryModels(cfg.MemorySnapshot)
+
+		return models, nil, err
+	}
+
+	// initialize database
+	db, err := database.InitDB(ctx, cfg.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// queries differ between databases
+	switch cfg.Driver {
+	case database.Postgres:
+		return models.NewPostgresModels(db), db, nil
+	case database.SQLite:
+		return models.NewSQLiteModels(db), db, nil
+	default:
+		return models.NewModels(db), db, nil
 	}
 }
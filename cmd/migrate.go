@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"article/internal/database"
+	"article/internal/migrations"
+)
+
+// runMigrate runs migrate subcommand against db of driver, migrate create is run by runMigrateCreate
+func runMigrate(db *sql.DB, driver string, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down|status|create")
+	}
+
+	flags := flag.NewFlagSet("migrate "+args[0], flag.ExitOnError)
+	steps := flags.Int("steps", 1, "number of migrations to roll back")
+
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	migrator := newMigrator(db, driver)
+
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up(ctx)
+		for _, m := range done {
+			fmt.Printf("applied %06d_%s\n", m.Version, m.Name)
+		}
+
+		return err
+	case "down":
+		done, err := migrator.Down(ctx, *steps)
+		for _, m := range done {
+			fmt.Printf("rolled back %06d_%s\n", m.Version, m.Name)
+		}
+
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+			}
+
+			fmt.Fprintf(w, "%06d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+
+		return w.Flush()
+	}
+
+	return fmt.Errorf("unknown migrate command %s", args[0])
+}
+
+// runMigrateCreate writes up and down files of a new migration of driver named by args, it needs no database
+func runMigrateCreate(driver string, args []string) error {
+	flags := flag.NewFlagSet("migrate create", flag.ExitOnError)
+	dir := flags.String("dir", "internal/migrations/"+driver, "directory to create migration files in")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("usage: migrate create [-dir dir] name")
+	}
+
+	paths, err := migrations.Create(*dir, flags.Arg(0))
+	for _, path := range paths {
+		fmt.Println("created", path)
+	}
+
+	return err
+}
+
+// newMigrator returns migrator using migrations of driver
+func newMigrator(db *sql.DB, driver string) *migrations.Migrator {
+	switch driver {
+	case database.Postgres:
+		return migrations.NewPostgres(db)
+	case database.SQLite:
+		return migrations.NewSQLite(db)
+	default:
+		return migrations.New(db)
+	}
+}
+
+// checkSchema refuses to start on a schema behind migrations unless autoMigrate is set,
+// migrations it applies are logged to logger
+func checkSchema(logger *slog.Logger, db *sql.DB, driver string, autoMigrate bool) error {
+	ctx := context.Background()
+	migrator := newMigrator(db, driver)
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if !autoMigrate {
+		return fmt.Errorf("database schema is behind by %d migrations, run `migrate up` or enable features.auto_migrate", len(pending))
+	}
+
+	done, err := migrator.Up(ctx)
+	for _, m := range done {
+		logger.Info("applied migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+	}
+
+	return err
+}
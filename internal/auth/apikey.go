@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"article/internal/logging"
+	"article/internal/models"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiKeyTag starts every API key so leaked keys are easy to recognise
+const apiKeyTag = "ak_"
+
+// touchInterval limits how often last use of a key is written
+const touchInterval = time.Minute
+
+// ErrKeyInactive is returned when rotating a revoked or expired key
+var ErrKeyInactive = errors.New("api key is revoked or expired")
+
+// APIKeys issues and authenticates API keys. A key reads ak_<prefix>.<secret>, the prefix
+// finds the stored key and only the sha256 of the secret is stored. Keys get the role their scopes imply
+type APIKeys struct {
+	store models.APIKeyStore
+	now   func() time.Time
+
+	// timeout bounds store calls authenticating a key, zero leaves them bound by the request only
+	timeout time.Duration
+}
+
+// NewAPIKeys returns API keys kept in store
+func NewAPIKeys(store models.APIKeyStore) *APIKeys {
+	return NewAPIKeysWithTimeout(store, 0)
+}
+
+// NewAPIKeysWithTimeout returns API keys kept in store, store calls authenticating a key stop after timeout
+func NewAPIKeysWithTimeout(store models.APIKeyStore, timeout time.Duration) *APIKeys {
+	return &APIKeys{
+		store: store,
+		now: func() time.Time {
+			return time.Now().UTC().Truncate(time.Second)
+		},
+		timeout: timeout,
+	}
+}
+
+// Create issues a key named name granting scopes, the key expires after ttl unless ttl is zero.
+// The returned key is shown once, it cannot be recovered from the store
+func (k *APIKeys) Create(ctx context.Context, name string, scopes []string, ttl time.Duration) (string, *models.APIKey, error) {
+	var expiresAt *time.Time
+
+	if ttl > 0 {
+		at := k.now().Add(ttl)
+		expiresAt = &at
+	}
+
+	return k.create(ctx, name, scopes, expiresAt)
+}
+
+// create issues a key expiring at expiresAt
+func (k *APIKeys) create(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (string, *models.APIKey, error) {
+	prefix, err := random(8, hex.EncodeToString)
+	if err != nil {
+		return "", nil, err
+	}
+
+	secret, err := random(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := &models.APIKey{
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      hash(secret),
+		Scopes:    scopes,
+		CreatedAt: k.now(),
+		ExpiresAt: expiresAt,
+	}
+
+	_, err = k.store.Create(ctx, key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return apiKeyTag + prefix + "." + secret, key, nil
+}
+
+// Rotate issues a key replacing key of keyID with the same name and scopes, the old key keeps working
+// for overlap so clients can switch over. The new key lives as long as the old one was issued for
+func (k *APIKeys) Rotate(ctx context.Context, keyID int, overlap time.Duration) (string, *models.APIKey, error) {
+	old, err := k.store.GetByID(ctx, keyID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := k.now()
+	if !active(old, now) {
+		return "", nil, ErrKeyInactive
+	}
+
+	var expiresAt *time.Time
+
+	if old.ExpiresAt != nil {
+		at := now.Add(old.ExpiresAt.Sub(old.CreatedAt))
+		expiresAt = &at
+	}
+
+	token, key, err := k.create(ctx, old.Name, old.Scopes, expiresAt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// the old key never lives longer than it was issued for
+	end := now.Add(overlap)
+	if old.ExpiresAt == nil || end.Before(*old.ExpiresAt) {
+		err = k.store.SetExpiry(ctx, old.ID, end)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	return token, key, nil
+}
+
+// Revoke revokes key of keyID immediately
+func (k *APIKeys) Revoke(ctx context.Context, keyID int) error {
+	return k.store.Revoke(ctx, keyID, k.now())
+}
+
+// List returns all keys
+func (k *APIKeys) List(ctx context.Context) ([]*models.APIKey, error) {
+	return k.store.List(ctx)
+}
+
+// Authenticate returns principal of key credentials, errors of invalid credentials wrap ErrInvalidToken
+// and store errors are returned as they are
+func (k *APIKeys) Authenticate(ctx context.Context, credentials string) (*Principal, error) {
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(credentials, apiKeyTag), ".")
+	if !ok || !strings.HasPrefix(credentials, apiKeyTag) || prefix == "" || secret == "" {
+		return nil, fmt.Errorf("%w : malformed api key", ErrInvalidToken)
+	}
+
+	if k.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, k.timeout)
+		defer cancel()
+	}
+
+	key, err := k.store.GetByPrefix(ctx, prefix)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, fmt.Errorf("%w : unknown api key", ErrInvalidToken)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hash(secret)), []byte(key.Hash)) != 1 {
+		return nil, fmt.Errorf("%w : api key does not match", ErrInvalidToken)
+	}
+
+	now := k.now()
+	if !active(key, now) {
+		return nil, fmt.Errorf("%w : api key is revoked or expired", ErrInvalidToken)
+	}
+
+	// failing to record use does not fail the request
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= touchInterval {
+		err = k.store.Touch(ctx, key.ID, now)
+		if err != nil {
+			logging.FromContext(ctx).Warn("error recording api key use", logging.Err(err), "key_id", key.ID)
+		}
+	}
+
+	return &Principal{Subject: "apikey:" + strconv.Itoa(key.ID), Name: key.Name, Scopes: key.Scopes, Role: roleOf(key.Scopes)}, nil
+}
+
+// active reports whether key is neither revoked nor expired at now
+func active(key *models.APIKey, now time.Time) bool {
+	return key.RevokedAt == nil && (key.ExpiresAt == nil || now.Before(*key.ExpiresAt))
+}
+
+// hash returns the hex sha256 of secret, keys are random so they need no salt or slow hash
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// random returns n random bytes encoded by encode
+func random(n int, encode func([]byte) string) (string, error) {
+	b := make([]byte, n)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return encode(b), nil
+}
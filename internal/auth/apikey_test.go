@@ -0,0 +1,135 @@
+package auth_test
+
+import (
+	"article/internal/auth"
+	"article/internal/models"
+	"article/mocks"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_APIKeys(t *testing.T) {
+	ctx := context.Background()
+
+	m, err := models.NewMemoryModels("")
+	require.NoError(t, err)
+
+	keys := auth.NewAPIKeys(m.APIKey)
+
+	token, key, err := keys.Create(ctx, "importer", []string{auth.ScopeRead, auth.ScopeWrite}, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, "ak_"+key.Prefix+"."))
+	require.NotNil(t, key.ExpiresAt)
+
+	// only the hash of the secret is stored
+	stored, err := m.APIKey.GetByID(ctx, key.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, token, stored.Hash)
+
+	p, err := keys.Authenticate(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, p, &auth.Principal{Subject: "apikey:1", Name: "importer", Scopes: []string{auth.ScopeRead, auth.ScopeWrite}, Role: auth.RoleAuthor})
+
+	// use is recorded
+	stored, err = m.APIKey.GetByID(ctx, key.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.LastUsedAt)
+
+	for name, credentials := range map[string]string{
+		"wrong secret":   token[:len(token)-2] + "xx",
+		"unknown key":    "ak_0000000000000000.secret",
+		"missing tag":    strings.TrimPrefix(token, "ak_"),
+		"missing dot":    strings.Replace(token, ".", "", 1),
+		"empty secret":   "ak_" + key.Prefix + ".",
+		"a bearer token": "eyJhbGciOiJIUzI1NiJ9.e30.sig",
+	} {
+		_, err := keys.Authenticate(ctx, credentials)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken, name)
+	}
+
+	// rotation issues a key with the same name and scopes, the old one works during the overlap
+	rotated, newKey, err := keys.Rotate(ctx, key.ID, time.Minute)
+	require.NoError(t, err)
+	assert.NotEqual(t, rotated, token)
+	assert.Equal(t, newKey.Name, "importer")
+	assert.Equal(t, newKey.Scopes, key.Scopes)
+	require.NotNil(t, newKey.ExpiresAt)
+
+	_, err = keys.Authenticate(ctx, token)
+	require.NoError(t, err)
+
+	_, err = keys.Authenticate(ctx, rotated)
+	require.NoError(t, err)
+
+	stored, err = m.APIKey.GetByID(ctx, key.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.ExpiresAt.Before(*key.ExpiresAt))
+
+	// expired keys are refused and cannot be rotated
+	require.NoError(t, m.APIKey.SetExpiry(ctx, key.ID, time.Now().Add(-time.Second)))
+
+	_, err = keys.Authenticate(ctx, token)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	_, _, err = keys.Rotate(ctx, key.ID, time.Minute)
+	assert.ErrorIs(t, err, auth.ErrKeyInactive)
+
+	// revoked keys are refused at once
+	require.NoError(t, keys.Revoke(ctx, newKey.ID))
+
+	_, err = keys.Authenticate(ctx, rotated)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	_, _, err = keys.Rotate(ctx, 42, time.Minute)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+}
+
+func Test_APIKeysStoreError(t *testing.T) {
+	store := mocks.NewAPIKeyStore(t)
+	store.EXPECT().GetByPrefix(mock.Anything, "0000000000000000").RunAndReturn(func(ctx context.Context, _ string) (*models.APIKey, error) {
+		// the lookup is bound by the timeout
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+
+		return nil, errors.New("db down")
+	})
+
+	// store errors are not invalid credentials
+	_, err := auth.NewAPIKeysWithTimeout(store, time.Second).Authenticate(context.Background(), "ak_0000000000000000.secret")
+	assert.EqualError(t, err, "db down")
+	assert.NotErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func Test_ParseScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  string
+		want    []string
+		wantErr string
+	}{
+		{name: "success - space and comma separated", scopes: "articles:read, admin articles:read", want: []string{auth.ScopeRead, auth.ScopeAdmin}},
+		{name: "error - unknown scope", scopes: "articles:read articles:delete", wantErr: "unknown scope articles:delete"},
+		{name: "error - empty", scopes: " ", wantErr: "at least one scope is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := auth.ParseScopes(tt.scopes)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
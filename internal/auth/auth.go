@@ -0,0 +1,150 @@
+// Package auth authenticates requests and carries the authenticated principal in request contexts
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned for credentials that cannot be verified
+var ErrInvalidToken = errors.New("invalid token")
+
+// scopes granted to principals
+const (
+	ScopeRead  = "articles:read"
+	ScopeWrite = "articles:write"
+	ScopeAdmin = "admin"
+)
+
+// roles of principals, from least to most privileged
+const (
+	RoleReader = "reader"
+	RoleAuthor = "author"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// knownRole reports whether role is assigned by this service
+func knownRole(role string) bool {
+	return role == RoleReader || role == RoleAuthor || role == RoleEditor || role == RoleAdmin
+}
+
+// roleOf returns the role implied by scopes of credentials which carry no role
+func roleOf(scopes []string) string {
+	p := Principal{Scopes: scopes}
+
+	switch {
+	case p.HasScope(ScopeAdmin):
+		return RoleAdmin
+	case p.HasScope(ScopeWrite):
+		return RoleAuthor
+	default:
+		return RoleReader
+	}
+}
+
+// knownScope reports whether scope is granted by this service
+func knownScope(scope string) bool {
+	return scope == ScopeRead || scope == ScopeWrite || scope == ScopeAdmin
+}
+
+// ParseScopes splits space or comma separated scopes and rejects unknown ones
+func ParseScopes(s string) ([]string, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+
+	var scopes []string
+
+	seen := map[string]bool{}
+
+	for _, scope := range fields {
+		if !knownScope(scope) {
+			return nil, fmt.Errorf("unknown scope %s", scope)
+		}
+
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes, nil
+}
+
+// Principal is an authenticated caller
+type Principal struct {
+	// Subject identifies the caller, it is the sub claim of tokens
+	Subject string
+
+	// Name is stored as author of articles the caller creates, it is the name claim of tokens or the subject
+	Name string
+
+	// Scopes are the operations the caller is allowed
+	Scopes []string
+
+	// Role decides which articles the caller may change, it is one of the Role constants
+	Role string
+}
+
+// HasScope reports whether p was granted scope
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// principalKey is the context key of principals
+type principalKey struct{}
+
+// WithPrincipal returns ctx carrying p
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns principal of ctx, ok is false for anonymous requests
+func FromContext(ctx context.Context) (p *Principal, ok bool) {
+	p, ok = ctx.Value(principalKey{}).(*Principal)
+
+	return p, ok
+}
+
+// Config holds token verification settings, tokens cannot be verified when neither a secret nor a JWKS file is set
+type Config struct {
+	// HS256Secret verifies HS256 signed tokens
+	HS256Secret string
+
+	// JWKSFile is a local JSON Web Key Set verifying RS256 and ES256 signed tokens
+	JWKSFile string
+
+	// Issuer and Audience are required in iss and aud claims when set
+	Issuer   string
+	Audience string
+
+	// ClockSkew is tolerated when checking exp, nbf and iat claims
+	ClockSkew time.Duration
+}
+
+// DefaultConfig returns settings used for values not configured
+func DefaultConfig() Config {
+	return Config{
+		ClockSkew: time.Minute,
+	}
+}
+
+// Validate reports unsupported settings
+func (c Config) Validate() error {
+	if c.ClockSkew < 0 {
+		return errors.New("clock skew must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"article/internal/logging"
+	"article/internal/response"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// realm is the protection space named in challenges
+const realm = "article"
+
+// Authorization schemes, challenges name them in this order
+const (
+	SchemeBearer = "Bearer"
+	SchemeAPIKey = "ApiKey"
+)
+
+// ErrNoCredentials is reported to anonymous requests of protected routes
+var ErrNoCredentials = errors.New("authentication required")
+
+// Authenticator returns principal of credentials sent in an Authorization header, errors of
+// credentials which cannot be verified wrap ErrInvalidToken and other errors are failures to verify them
+type Authenticator interface {
+	Authenticate(ctx context.Context, credentials string) (*Principal, error)
+}
+
+// Schemes maps Authorization schemes to their authenticator, schemes are matched case insensitively
+type Schemes map[string]Authenticator
+
+// lookup returns authenticator of scheme
+func (s Schemes) lookup(scheme string) (Authenticator, bool) {
+	for name, a := range s {
+		if strings.EqualFold(name, scheme) {
+			return a, true
+		}
+	}
+
+	return nil, false
+}
+
+// Middleware authenticates requests sending an Authorization header of one of schemes and passes handlers
+// the principal, requests without the header pass anonymously and requests with invalid credentials get 401.
+// Credentials which could not be verified, such as when the store is down, get a server error
+func Middleware(schemes Schemes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			scheme, credentials, _ := strings.Cut(header, " ")
+
+			a, ok := schemes.lookup(scheme)
+			if !ok || strings.TrimSpace(credentials) == "" {
+				Unauthorized(w, r, fmt.Errorf("%w : unsupported authorization scheme", ErrInvalidToken))
+
+				return
+			}
+
+			p, err := a.Authenticate(r.Context(), strings.TrimSpace(credentials))
+			if errors.Is(err, ErrInvalidToken) {
+				Unauthorized(w, r, err)
+
+				return
+			}
+
+			if err != nil {
+				logging.FromContext(r.Context()).Error("error authenticating request", logging.Err(err))
+				response.New().ServerError(w, err, "error authenticating request")
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+// Require answers anonymous requests with 401 and requests of principals not granted scope with 403
+func Require(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := FromContext(r.Context())
+			if !ok {
+				Unauthorized(w, r, ErrNoCredentials)
+
+				return
+			}
+
+			if !p.HasScope(scope) {
+				Forbidden(w, r, scope)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Restrict answers requests of principals not granted scope with 403, anonymous requests pass
+func Restrict(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if p, ok := FromContext(r.Context()); ok && !p.HasScope(scope) {
+				Forbidden(w, r, scope)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Unauthorized responds 401 challenging every scheme, invalid credentials are flagged as invalid_token (RFC 6750)
+func Unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	logging.FromContext(r.Context()).Info("authentication failed", logging.Err(err))
+
+	params, msg := fmt.Sprintf("realm=%q", realm), ErrNoCredentials.Error()
+	if errors.Is(err, ErrInvalidToken) {
+		params, msg = params+`, error="invalid_token"`, ErrInvalidToken.Error()
+	}
+
+	for _, scheme := range []string{SchemeBearer, SchemeAPIKey} {
+		w.Header().Add("WWW-Authenticate", scheme+" "+params)
+	}
+
+	response.New().Unauthorized(w, msg)
+}
+
+// Forbidden responds 403 to a principal not granted scope
+func Forbidden(w http.ResponseWriter, r *http.Request, scope string) {
+	logging.FromContext(r.Context()).Info("scope not granted", "scope", scope)
+
+	response.New().Forbidden(w, fmt.Sprintf("%s scope required", scope))
+}
@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// jsonWebKey is a public key of a JSON Web Key Set, RFC 7517
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+
+	// RSA modulus and exponent
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC curve and coordinates
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey is a verification key and the signing algorithm it is used with
+type publicKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// loadJWKS reads RSA and P-256 EC keys of the JWKS file named path, keys of other types or meant for encryption are skipped
+func loadJWKS(path string) ([]publicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	err = json.Unmarshal(data, &set)
+	if err != nil {
+		return nil, fmt.Errorf("decoding JWKS : %w", err)
+	}
+
+	var keys []publicKey
+
+	for i, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		var key publicKey
+
+		switch jwk.Kty {
+		case "RSA":
+			key, err = rsaKey(jwk)
+		case "EC":
+			key, err = ecKey(jwk)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("JWKS key %d : %w", i, err)
+		}
+
+		if jwk.Alg != "" && jwk.Alg != key.alg {
+			return nil, fmt.Errorf("JWKS key %d : unsupported alg %s", i, jwk.Alg)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS holds no signing keys")
+	}
+
+	return keys, nil
+}
+
+// rsaKey decodes RSA key of jwk
+func rsaKey(jwk jsonWebKey) (publicKey, error) {
+	n, err := decodeInt(jwk.N)
+	if err != nil {
+		return publicKey{}, fmt.Errorf("modulus : %w", err)
+	}
+
+	e, err := decodeInt(jwk.E)
+	if err != nil || !e.IsInt64() {
+		return publicKey{}, errors.New("invalid exponent")
+	}
+
+	return publicKey{kid: jwk.Kid, alg: "RS256", key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+}
+
+// ecKey decodes P-256 key of jwk
+func ecKey(jwk jsonWebKey) (publicKey, error) {
+	if jwk.Crv != "P-256" {
+		return publicKey{}, fmt.Errorf("unsupported curve %s", jwk.Crv)
+	}
+
+	x, err := decodeInt(jwk.X)
+	if err != nil {
+		return publicKey{}, fmt.Errorf("x : %w", err)
+	}
+
+	y, err := decodeInt(jwk.Y)
+	if err != nil {
+		return publicKey{}, fmt.Errorf("y : %w", err)
+	}
+
+	curve := elliptic.P256()
+	if !curve.IsOnCurve(x, y) {
+		return publicKey{}, errors.New("point is not on curve")
+	}
+
+	return publicKey{kid: jwk.Kid, alg: "ES256", key: &ecdsa.PublicKey{Curve: curve, X: x, Y: y}}, nil
+}
+
+// decodeInt decodes base64url encoded big endian integer
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
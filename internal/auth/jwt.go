@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// claims are the claims read from tokens
+type claims struct {
+	jwt.RegisteredClaims
+
+	Name string `json:"name"`
+
+	// Scope holds space separated scopes, tokens without it may only read articles
+	Scope string `json:"scope"`
+
+	// Role is one of the Role constants, tokens without it get the role their scopes imply
+	// and unknown roles are read as reader
+	Role string `json:"role"`
+}
+
+// Verifier verifies JWT bearer tokens signed with HS256 by a shared secret or with RS256 or ES256 by a key of a JWKS
+type Verifier struct {
+	secret []byte
+	keys   []publicKey
+	parser *jwt.Parser
+}
+
+// NewVerifier returns verifier of cfg, the JWKS file is read once
+func NewVerifier(cfg Config) (*Verifier, error) {
+	v := &Verifier{}
+
+	var methods []string
+
+	if cfg.HS256Secret != "" {
+		v.secret = []byte(cfg.HS256Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading %s : %w", cfg.JWKSFile, err)
+		}
+
+		v.keys = keys
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(cfg.ClockSkew),
+	}
+
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+// Enabled reports whether any token can be verified
+func (v *Verifier) Enabled() bool {
+	return v.secret != nil || len(v.keys) > 0
+}
+
+// Verify returns principal of token, errors wrap ErrInvalidToken
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	if !v.Enabled() {
+		return nil, fmt.Errorf("%w : no verification keys configured", ErrInvalidToken)
+	}
+
+	var c claims
+
+	_, err := v.parser.ParseWithClaims(token, &c, v.key)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidToken, err)
+	}
+
+	if c.Subject == "" {
+		return nil, fmt.Errorf("%w : sub claim is missing", ErrInvalidToken)
+	}
+
+	// tokens grant writes only when asking for them
+	p := &Principal{Subject: c.Subject, Name: c.Name, Scopes: []string{ScopeRead}}
+	if p.Name == "" {
+		p.Name = c.Subject
+	}
+
+	// scopes of other services sharing the issuer are ignored
+	if c.Scope != "" {
+		p.Scopes = nil
+
+		for _, scope := range strings.Fields(c.Scope) {
+			if knownScope(scope) {
+				p.Scopes = append(p.Scopes, scope)
+			}
+		}
+	}
+
+	// roles of other services sharing the issuer grant nothing beyond reading
+	switch {
+	case c.Role == "":
+		p.Role = roleOf(p.Scopes)
+	case knownRole(c.Role):
+		p.Role = c.Role
+	default:
+		p.Role = RoleReader
+	}
+
+	return p, nil
+}
+
+// Authenticate verifies bearer token credentials
+func (v *Verifier) Authenticate(_ context.Context, credentials string) (*Principal, error) {
+	return v.Verify(credentials)
+}
+
+// key returns key verifying token, JWKS keys are matched by kid and algorithm.
+// Tokens without kid are verified by the only key of their algorithm
+func (v *Verifier) key(token *jwt.Token) (interface{}, error) {
+	alg := token.Method.Alg()
+	if alg == jwt.SigningMethodHS256.Alg() {
+		return v.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	var found []publicKey
+
+	for _, k := range v.keys {
+		if k.alg == alg && (kid == "" || k.kid == kid) {
+			found = append(found, k)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no %s key with kid %q", alg, kid)
+	case 1:
+		return found[0].key, nil
+	default:
+		return nil, errors.New("token has no kid and several keys match")
+	}
+}
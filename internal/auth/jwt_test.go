@@ -0,0 +1,272 @@
+package auth_test
+
+import (
+	"article/internal/auth"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "test-secret"
+
+// readOnly are the scopes of tokens without scope claim
+var readOnly = []string{auth.ScopeRead}
+
+// readWrite are the scopes of tokens asking for writes
+var readWrite = []string{auth.ScopeRead, auth.ScopeWrite}
+
+// b64 encodes n as JWK integer
+func b64(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+// writeJWKS writes JWKS of public keys of rsaKey and ecKey and returns its path
+func writeJWKS(t *testing.T, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) string {
+	set := map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256", "n": b64(rsaKey.N), "e": b64(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(ecKey.X), "y": b64(ecKey.Y)},
+			{"kty": "oct", "kid": "ignored", "k": "c2VjcmV0"},
+		},
+	}
+
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+// sign returns token of claims signed by key with method, kid is set in the header when not empty
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func Test_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	cfg := auth.Config{
+		HS256Secret: secret,
+		JWKSFile:    writeJWKS(t, rsaKey, ecKey),
+		Issuer:      "https://issuer.example.com",
+		Audience:    "article",
+		ClockSkew:   time.Minute,
+	}
+
+	// claims returns valid claims with fields of extra replacing them
+	claims := func(extra jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub": "u-1",
+			"iss": cfg.Issuer,
+			"aud": "article",
+			"exp": time.Now().Add(time.Hour).Unix(),
+			"iat": time.Now().Unix(),
+		}
+
+		for k, v := range extra {
+			if v == nil {
+				delete(c, k)
+
+				continue
+			}
+
+			c[k] = v
+		}
+
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		want    *auth.Principal
+		wantErr bool
+	}{
+		{
+			name:  "success - HS256",
+			token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"name": "Jane"})),
+			want:  &auth.Principal{Subject: "u-1", Name: "Jane", Scopes: readOnly, Role: auth.RoleReader},
+		},
+		{
+			name:  "success - RS256 key of JWKS",
+			token: sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", claims(nil)),
+			want:  &auth.Principal{Subject: "u-1", Name: "u-1", Scopes: readOnly, Role: auth.RoleReader},
+		},
+		{
+			name:  "success - ES256 key of JWKS without kid",
+			token: sign(t, jwt.SigningMethodES256, ecKey, "", claims(nil)),
+			want:  &auth.Principal{Subject: "u-1", Name: "u-1", Scopes: readOnly, Role: auth.RoleReader},
+		},
+		{
+			name:  "success - audience list",
+			token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"aud": []string{"other", "article"}})),
+			want:  &auth.Principal{Subject: "u-1", Name: "u-1", Scopes: readOnly, Role: auth.RoleReader},
+		},
+		{
+			name:  "success - expired within clock skew",
+			token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"exp": time.Now().Add(-30 * time.Second).Unix()})),
+			want:  &auth.Principal{Subject: "u-1", Name: "u-1", Scopes: readOnly, Role: auth.RoleReader},
+		},
+		{
+			name:  "success - scopes of other services are ignored",
+			token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"scope": "openid articles:read admin"})),
+			want:  &auth.Principal{Subject: "u-1", Name: "u-1", Scopes: []string{auth.ScopeRead, auth.ScopeAdmin}, Role: auth.RoleAdmin},
+		},
+		{
+			name:  "success - role claim",
+			token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"role": "editor"})),
+			want:  &auth.Principal{Subject: "u-1", Name: "u-1", Scopes: readOnly, Role: auth.RoleEditor},
+		},
+		{
+			name:  "success - write scope is an author",
+			token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"scope": "articles:read articles:write"})),
+			want:  &auth.Principal{Subject: "u-1", Name: "u-1", Scopes: readWrite, Role: auth.RoleAuthor},
+		},
+		{
+			name:  "success - read scope is a reader",
+			token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"scope": "articles:read"})),
+			want:  &auth.Principal{Subject: "u-1", Name: "u-1", Scopes: []string{auth.ScopeRead}, Role: auth.RoleReader},
+		},
+		{
+			name:  "success - unknown role is a reader",
+			token: sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"role": "owner", "scope": "articles:read articles:write"})),
+			want:  &auth.Principal{Subject: "u-1", Name: "u-1", Scopes: readWrite, Role: auth.RoleReader},
+		},
+		{
+			name:    "error - expired beyond clock skew",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"exp": time.Now().Add(-2 * time.Minute).Unix()})),
+			wantErr: true,
+		},
+		{
+			name:    "error - not valid yet",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"nbf": time.Now().Add(5 * time.Minute).Unix()})),
+			wantErr: true,
+		},
+		{
+			name:    "error - missing exp",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"exp": nil})),
+			wantErr: true,
+		},
+		{
+			name:    "error - missing sub",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"sub": nil})),
+			wantErr: true,
+		},
+		{
+			name:    "error - wrong issuer",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"iss": "https://evil.example.com"})),
+			wantErr: true,
+		},
+		{
+			name:    "error - wrong audience",
+			token:   sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims(jwt.MapClaims{"aud": "other"})),
+			wantErr: true,
+		},
+		{
+			name:    "error - wrong secret",
+			token:   sign(t, jwt.SigningMethodHS256, []byte("other"), "", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "error - key not in JWKS",
+			token:   sign(t, jwt.SigningMethodRS256, otherKey, "rsa-1", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "error - unknown kid",
+			token:   sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-2", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "error - unsigned token",
+			token:   sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "error - malformed token",
+			token:   "not.a.token",
+			wantErr: true,
+		},
+	}
+
+	v, err := auth.NewVerifier(cfg)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.Verify(tt.token)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func Test_NewVerifier(t *testing.T) {
+	// HS256 tokens are refused when only a JWKS is configured
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	v, err := auth.NewVerifier(auth.Config{JWKSFile: writeJWKS(t, rsaKey, ecKey)})
+	require.NoError(t, err)
+	assert.True(t, v.Enabled())
+
+	_, err = v.Verify(sign(t, jwt.SigningMethodHS256, []byte(""), "", jwt.MapClaims{"sub": "u-1", "exp": time.Now().Add(time.Hour).Unix()}))
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	// nothing is verified without keys
+	v, err = auth.NewVerifier(auth.DefaultConfig())
+	require.NoError(t, err)
+	assert.False(t, v.Enabled())
+
+	_, err = v.Verify(sign(t, jwt.SigningMethodHS256, []byte(secret), "", jwt.MapClaims{"sub": "u-1", "exp": time.Now().Add(time.Hour).Unix()}))
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	// invalid key sets are reported on start
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"keys":[{"kty":"EC","crv":"P-384","x":"AQ","y":"AQ"}]}`), 0o600))
+
+	_, err = auth.NewVerifier(auth.Config{JWKSFile: path})
+	assert.ErrorContains(t, err, "unsupported curve P-384")
+
+	_, err = auth.NewVerifier(auth.Config{JWKSFile: filepath.Join(t.TempDir(), "missing.json")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,280 @@
+// Package config loads application settings from defaults, a YAML or TOML file, env and flags
+package config
+
+import (
+	"article/internal/auth"
+	"article/internal/database"
+	"article/internal/logging"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Memory is the DB_DRIVER value selecting the in-memory store, it needs no database
+const Memory = "memory"
+
+// Config holds all application settings
+type Config struct {
+	Server   Server
+	Auth     Auth
+	Database Database
+	Log      Log
+	CORS     CORS
+	Limits   Limits
+	Health   Health
+	Tracing  Tracing
+	Features Features
+}
+
+// Server holds HTTP server settings
+type Server struct {
+	// Addr is the address the server listens on e.g. :8080
+	Addr string
+
+	// AdminToken grants key management when passed in X-Admin-Token header without other credentials, empty disables it
+	AdminToken string
+
+	// timeouts of http.Server, zero means no timeout
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// MaxHeaderBytes limits size of request headers
+	MaxHeaderBytes int
+
+	// DrainPeriod is how long readiness fails before shutdown starts so load balancers stop routing requests,
+	// ShutdownTimeout is how long in-flight requests may take to finish after that
+	DrainPeriod     time.Duration
+	ShutdownTimeout time.Duration
+}
+
+// Auth holds settings of JWT bearer tokens authenticating writes
+type Auth struct {
+	auth.Config
+}
+
+// Database holds store settings, Driver may also be Memory
+type Database struct {
+	database.Config
+
+	// QueryTimeout is the deadline for database calls of a request
+	QueryTimeout time.Duration
+
+	// MemorySnapshot is the file the in-memory store is loaded from and saved to, empty keeps articles in memory only
+	MemorySnapshot string
+}
+
+// Log holds logger settings
+type Log struct {
+	logging.Config
+}
+
+// CORS holds cross origin settings, requests from other origins are not answered with CORS headers when AllowedOrigins is empty
+type CORS struct {
+	// AllowedOrigins lists origins e.g. https://example.com, * allows any origin
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+
+	// MaxAge is how long browsers may cache preflight responses
+	MaxAge time.Duration
+}
+
+// Limits holds request limits
+type Limits struct {
+	// DefaultPageSize is the page size of lists without limit, MaxPageSize is the largest limit allowed
+	DefaultPageSize int
+	MaxPageSize     int
+}
+
+// Health holds readiness check settings
+type Health struct {
+	// CheckTimeout bounds each readiness check
+	CheckTimeout time.Duration
+}
+
+// trace exporters
+const (
+	// ExporterNone keeps trace ids for correlation but exports no spans
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+// Tracing holds OpenTelemetry tracing settings
+type Tracing struct {
+	// Exporter is one of the trace exporters
+	Exporter    string
+	ServiceName string
+
+	// Endpoint is host:port of the OTLP/HTTP collector, OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318 is used when empty,
+	// Insecure sends spans over plain HTTP
+	Endpoint string
+	Insecure bool
+
+	// File receives spans as JSON lines with the file exporter
+	File string
+
+	// SampleRatio is the share of traces started here that are sampled, sampling decisions of callers are kept
+	SampleRatio float64
+}
+
+// Features holds feature toggles
+type Features struct {
+	// AutoMigrate applies pending migrations on start instead of refusing to start
+	AutoMigrate bool
+
+	// Metrics exposes Prometheus metrics on /metrics
+	Metrics bool
+}
+
+// Default returns settings used when no source sets them
+func Default() Config {
+	return Config{
+		Server: Server{
+			Addr:              ":8080",
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       time.Minute,
+			MaxHeaderBytes:    1 << 20,
+			DrainPeriod:       5 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+		},
+		Auth: Auth{
+			Config: auth.DefaultConfig(),
+		},
+		Database: Database{
+			Config:       database.DefaultConfig(),
+			QueryTimeout: 5 * time.Second,
+		},
+		Log: Log{
+			Config: logging.DefaultConfig(),
+		},
+		CORS: CORS{
+			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-Admin-Token"},
+			MaxAge:         10 * time.Minute,
+		},
+		Limits: Limits{
+			DefaultPageSize: 20,
+			MaxPageSize:     100,
+		},
+		Health: Health{
+			CheckTimeout: 2 * time.Second,
+		},
+		Tracing: Tracing{
+			Exporter:    ExporterNone,
+			ServiceName: "article",
+			SampleRatio: 1,
+		},
+		Features: Features{
+			Metrics: true,
+		},
+	}
+}
+
+// Validate reports all invalid settings at once
+func (c Config) Validate() error {
+	var problems []string
+
+	if _, _, err := net.SplitHostPort(c.Server.Addr); err != nil {
+		problems = append(problems, fmt.Sprintf("server.addr : %v", err))
+	}
+
+	for _, d := range []time.Duration{c.Server.ReadHeaderTimeout, c.Server.ReadTimeout, c.Server.WriteTimeout, c.Server.IdleTimeout, c.Server.DrainPeriod} {
+		if d < 0 {
+			problems = append(problems, "server : timeouts must not be negative")
+
+			break
+		}
+	}
+
+	if c.Server.MaxHeaderBytes < 0 {
+		problems = append(problems, "server.max_header_bytes : must not be negative")
+	}
+
+	if c.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, "server.shutdown_timeout : must be positive")
+	}
+
+	// responses of slow queries would be cut off before the query times out
+	if c.Server.WriteTimeout > 0 && c.Server.WriteTimeout <= c.Database.QueryTimeout {
+		problems = append(problems, fmt.Sprintf("server.write_timeout : %s must exceed database.query_timeout %s", c.Server.WriteTimeout, c.Database.QueryTimeout))
+	}
+
+	if err := c.Auth.Config.Validate(); err != nil {
+		problems = append(problems, fmt.Sprintf("auth : %v", err))
+	}
+
+	if c.Database.Driver != Memory {
+		if err := c.Database.Config.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("database : %v", err))
+		}
+	}
+
+	if err := c.Log.Config.Validate(); err != nil {
+		problems = append(problems, fmt.Sprintf("log : %v", err))
+	}
+
+	if c.Database.QueryTimeout <= 0 {
+		problems = append(problems, "database.query_timeout : must be positive")
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			problems = append(problems, fmt.Sprintf("cors.allowed_origins : %q is not an origin such as https://example.com", origin))
+		}
+	}
+
+	for _, method := range c.CORS.AllowedMethods {
+		if method == "" || strings.ToUpper(method) != method {
+			problems = append(problems, fmt.Sprintf("cors.allowed_methods : %q must be an upper case method", method))
+		}
+	}
+
+	if c.CORS.MaxAge < 0 {
+		problems = append(problems, "cors.max_age : must not be negative")
+	}
+
+	if c.Limits.DefaultPageSize < 1 || c.Limits.MaxPageSize < 1 {
+		problems = append(problems, "limits : page sizes must be at least 1")
+	}
+
+	if c.Limits.DefaultPageSize > c.Limits.MaxPageSize {
+		problems = append(problems, fmt.Sprintf("limits.default_page_size : %d exceeds limits.max_page_size %d", c.Limits.DefaultPageSize, c.Limits.MaxPageSize))
+	}
+
+	switch c.Tracing.Exporter {
+	case ExporterNone, ExporterStdout, ExporterOTLP:
+	case ExporterFile:
+		if c.Tracing.File == "" {
+			problems = append(problems, "tracing.file : required by the file exporter")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("tracing.exporter : unsupported exporter %s", c.Tracing.Exporter))
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problems = append(problems, "tracing.sample_ratio : must be between 0 and 1")
+	}
+
+	if c.Health.CheckTimeout <= 0 {
+		problems = append(problems, "health.check_timeout : must be positive")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config : %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
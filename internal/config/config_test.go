@@ -0,0 +1,300 @@
+package config_test
+
+import (
+	"article/internal/config"
+	"article/internal/database"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// env returns lookup func reading vars only
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		val, ok := vars[key]
+
+		return val, ok
+	}
+}
+
+// writeFile writes content to name in a temp dir and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf("error writing config file : %v", err)
+	}
+
+	return path
+}
+
+func Test_Load(t *testing.T) {
+	yamlFile := `
+server:
+  addr: ":9090"
+database:
+  driver: postgres
+  port: 5433
+  query_timeout: 2s
+cors:
+  allowed_origins:
+    - https://example.com
+    - https://admin.example.com
+features:
+  auto_migrate: true
+`
+
+	tomlFile := `
+[server]
+addr = ":9090"
+
+[database]
+driver = "postgres"
+port = 5433
+query_timeout = "2s"
+
+[cors]
+allowed_origins = ["https://example.com", "https://admin.example.com"]
+
+[features]
+auto_migrate = true
+`
+
+	fromFile := func(cfg *config.Config) {
+		cfg.Server.Addr = ":9090"
+		cfg.Database.Driver = database.Postgres
+		cfg.Database.Port = 5433
+		cfg.Database.QueryTimeout = 2 * time.Second
+		cfg.CORS.AllowedOrigins = []string{"https://example.com", "https://admin.example.com"}
+		cfg.Features.AutoMigrate = true
+	}
+
+	tests := []struct {
+		name     string
+		args     func(t *testing.T) []string
+		env      map[string]string
+		want     func(cfg *config.Config)
+		wantArgs []string
+		wantErr  bool
+	}{
+		{
+			name: "success - with defaults",
+			args: func(t *testing.T) []string { return nil },
+			want: func(cfg *config.Config) {},
+		},
+		{
+			name: "success - with yaml file",
+			args: func(t *testing.T) []string {
+				return []string{"-config", writeFile(t, "article.yaml", yamlFile)}
+			},
+			want: fromFile,
+		},
+		{
+			name: "success - with toml file from env",
+			args: func(t *testing.T) []string { return nil },
+			env:  map[string]string{"CONFIG_FILE": writeFile(t, "article.toml", tomlFile)},
+			want: fromFile,
+		},
+		{
+			name: "success - env overrides file",
+			args: func(t *testing.T) []string {
+				return []string{"-config", writeFile(t, "article.yaml", yamlFile)}
+			},
+			env: map[string]string{"SERVER_ADDR": ":7070", "CORS_ALLOWED_ORIGINS": "*"},
+			want: func(cfg *config.Config) {
+				fromFile(cfg)
+				cfg.Server.Addr = ":7070"
+				cfg.CORS.AllowedOrigins = []string{"*"}
+			},
+		},
+		{
+			name: "success - empty env clears file value",
+			args: func(t *testing.T) []string {
+				return []string{"-config", writeFile(t, "article.yaml", "server:\n  admin_token: file-secret\n")}
+			},
+			env:  map[string]string{"ADMIN_TOKEN": ""},
+			want: func(cfg *config.Config) {},
+		},
+		{
+			name: "success - flags override env and file",
+			args: func(t *testing.T) []string {
+				return []string{"-config", writeFile(t, "article.yaml", yamlFile), "-server.addr", ":6060", "-features.auto_migrate=false", "migrate", "up"}
+			},
+			env: map[string]string{"SERVER_ADDR": ":7070", "ADMIN_TOKEN": "secret"},
+			want: func(cfg *config.Config) {
+				fromFile(cfg)
+				cfg.Server.Addr = ":6060"
+				cfg.Server.AdminToken = "secret"
+				cfg.Features.AutoMigrate = false
+			},
+			wantArgs: []string{"migrate", "up"},
+		},
+		{
+			name: "error - unknown file setting",
+			args: func(t *testing.T) []string {
+				return []string{"-config", writeFile(t, "article.yaml", "server:\n  port: 8080\n")}
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - unsupported file format",
+			args: func(t *testing.T) []string {
+				return []string{"-config", writeFile(t, "article.json", "{}")}
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - missing file",
+			args: func(t *testing.T) []string {
+				return []string{"-config", filepath.Join(t.TempDir(), "article.yaml")}
+			},
+			wantErr: true,
+		},
+		{
+			name:    "error - invalid env",
+			args:    func(t *testing.T) []string { return nil },
+			env:     map[string]string{"DB_QUERY_TIMEOUT": "5"},
+			wantErr: true,
+		},
+		{
+			name:    "error - empty env of number",
+			args:    func(t *testing.T) []string { return nil },
+			env:     map[string]string{"DB_PORT": ""},
+			wantErr: true,
+		},
+		{
+			name:    "error - unknown flag",
+			args:    func(t *testing.T) []string { return []string{"-server.port", "8080"} },
+			wantErr: true,
+		},
+		{
+			name:    "error - unknown flag with config file env",
+			args:    func(t *testing.T) []string { return []string{"-server.port", "8080"} },
+			env:     map[string]string{"CONFIG_FILE": filepath.Join(t.TempDir(), "article.yaml")},
+			wantErr: true,
+		},
+		{
+			name:    "error - invalid setting",
+			args:    func(t *testing.T) []string { return []string{"-limits.default_page_size", "200"} },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args(t)
+
+			got, gotArgs, err := config.Load(args, env(tt.env))
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+
+				return
+			}
+
+			want := config.Default()
+			tt.want(&want)
+
+			assert.Nil(t, err)
+			assert.Equal(t, got, want)
+			assert.Equal(t, strings.Join(gotArgs, " "), strings.Join(tt.wantArgs, " "))
+		})
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  func(cfg *config.Config)
+		wantErr string
+	}{
+		{
+			name:   "success - memory driver skips database settings",
+			config: func(cfg *config.Config) { cfg.Database.Driver = config.Memory; cfg.Database.TLSMode = "prefer" },
+		},
+		{
+			name: "error - all problems are reported",
+			config: func(cfg *config.Config) {
+				cfg.Server.Addr = "8080"
+				cfg.Database.Driver = "oracle"
+				cfg.CORS.AllowedOrigins = []string{"example.com"}
+				cfg.CORS.AllowedMethods = []string{"get"}
+			},
+			wantErr: `invalid config : server.addr : address 8080: missing port in address; ` +
+				`database : unsupported driver oracle; ` +
+				`cors.allowed_origins : "example.com" is not an origin such as https://example.com; ` +
+				`cors.allowed_methods : "get" must be an upper case method`,
+		},
+		{
+			name: "error - write timeout shorter than query timeout",
+			config: func(cfg *config.Config) {
+				cfg.Server.WriteTimeout = 5 * time.Second
+				cfg.Database.QueryTimeout = 10 * time.Second
+			},
+			wantErr: "invalid config : server.write_timeout : 5s must exceed database.query_timeout 10s",
+		},
+		{
+			name:    "error - unsupported log level",
+			config:  func(cfg *config.Config) { cfg.Log.Level = "verbose" },
+			wantErr: "invalid config : log : unsupported level verbose",
+		},
+		{
+			name:    "error - default page size exceeds max",
+			config:  func(cfg *config.Config) { cfg.Limits.MaxPageSize = 10 },
+			wantErr: "invalid config : limits.default_page_size : 20 exceeds limits.max_page_size 10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			tt.config(&cfg)
+
+			err := cfg.Validate()
+
+			if tt.wantErr == "" {
+				assert.Nil(t, err)
+
+				return
+			}
+
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_Print(t *testing.T) {
+	cfg := config.Default()
+	cfg.Server.AdminToken = "admin-secret"
+	cfg.Database.DSN = ""
+	cfg.CORS.AllowedOrigins = []string{"https://example.com"}
+
+	var out bytes.Buffer
+
+	err := config.Print(&out, cfg)
+	assert.Nil(t, err)
+
+	printed := out.String()
+	assert.NotContains(t, printed, "admin-secret")
+	assert.NotContains(t, printed, "admin_token")
+	assert.NotContains(t, printed, "password")
+	assert.NotContains(t, printed, "dsn")
+	assert.Contains(t, printed, "query_timeout: 5s")
+
+	// printed config loads back to the same settings, secrets keep their defaults
+	path := writeFile(t, "article.yaml", printed)
+
+	got, _, err := config.Load([]string{"-config", path}, env(nil))
+	assert.Nil(t, err)
+
+	want := cfg
+	want.Server.AdminToken = ""
+
+	assert.Equal(t, got, want)
+}
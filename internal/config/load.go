@@ -0,0 +1,286 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// setting binds a config field to its file key, which is also its flag name, and env variable
+type setting struct {
+	key    string
+	env    string
+	secret bool
+}
+
+// registry registers config fields as flags, file and env values are applied through the flags as well
+// so every source is parsed the same way
+type registry struct {
+	fs       *flag.FlagSet
+	settings []setting
+
+	// file is set by the -config flag
+	file string
+}
+
+// newRegistry registers fields of cfg, flags default to the current values of cfg
+func newRegistry(cfg *Config) *registry {
+	r := &registry{fs: flag.NewFlagSet("article", flag.ContinueOnError)}
+
+	r.fs.StringVar(&r.file, "config", "", "YAML or TOML config file, also read from CONFIG_FILE env")
+
+	r.fs.StringVar(&cfg.Server.Addr, r.add("server.addr", "SERVER_ADDR", false), cfg.Server.Addr, "address the server listens on")
+	r.fs.StringVar(&cfg.Server.AdminToken, r.add("server.admin_token", "ADMIN_TOKEN", true), cfg.Server.AdminToken, "token granting key management to requests without other credentials, empty disables it")
+	r.fs.DurationVar(&cfg.Server.ReadHeaderTimeout, r.add("server.read_header_timeout", "SERVER_READ_HEADER_TIMEOUT", false), cfg.Server.ReadHeaderTimeout, "time to read request headers")
+	r.fs.DurationVar(&cfg.Server.ReadTimeout, r.add("server.read_timeout", "SERVER_READ_TIMEOUT", false), cfg.Server.ReadTimeout, "time to read the whole request")
+	r.fs.DurationVar(&cfg.Server.WriteTimeout, r.add("server.write_timeout", "SERVER_WRITE_TIMEOUT", false), cfg.Server.WriteTimeout, "time to write the response, must exceed database.query_timeout")
+	r.fs.DurationVar(&cfg.Server.IdleTimeout, r.add("server.idle_timeout", "SERVER_IDLE_TIMEOUT", false), cfg.Server.IdleTimeout, "time keep-alive connections wait for the next request")
+	r.fs.IntVar(&cfg.Server.MaxHeaderBytes, r.add("server.max_header_bytes", "SERVER_MAX_HEADER_BYTES", false), cfg.Server.MaxHeaderBytes, "size limit of request headers")
+	r.fs.DurationVar(&cfg.Server.DrainPeriod, r.add("server.drain_period", "SERVER_DRAIN_PERIOD", false), cfg.Server.DrainPeriod, "time readiness fails before shutdown starts")
+	r.fs.DurationVar(&cfg.Server.ShutdownTimeout, r.add("server.shutdown_timeout", "SERVER_SHUTDOWN_TIMEOUT", false), cfg.Server.ShutdownTimeout, "time in-flight requests may take to finish on shutdown")
+
+	r.fs.StringVar(&cfg.Auth.HS256Secret, r.add("auth.hs256_secret", "AUTH_HS256_SECRET", true), cfg.Auth.HS256Secret, "secret verifying HS256 signed tokens")
+	r.fs.StringVar(&cfg.Auth.JWKSFile, r.add("auth.jwks_file", "AUTH_JWKS_FILE", false), cfg.Auth.JWKSFile, "JWKS file verifying RS256 and ES256 signed tokens")
+	r.fs.StringVar(&cfg.Auth.Issuer, r.add("auth.issuer", "AUTH_ISSUER", false), cfg.Auth.Issuer, "required iss claim, empty skips the check")
+	r.fs.StringVar(&cfg.Auth.Audience, r.add("auth.audience", "AUTH_AUDIENCE", false), cfg.Auth.Audience, "required aud claim, empty skips the check")
+	r.fs.DurationVar(&cfg.Auth.ClockSkew, r.add("auth.clock_skew", "AUTH_CLOCK_SKEW", false), cfg.Auth.ClockSkew, "clock skew tolerated when checking token times")
+
+	db := &cfg.Database
+	r.fs.StringVar(&db.Driver, r.add("database.driver", "DB_DRIVER", false), db.Driver, "mysql, postgres, sqlite or memory")
+	r.fs.StringVar(&db.DSN, r.add("database.dsn", "DB_DSN", true), db.DSN, "data source name overriding the connection settings")
+	r.fs.StringVar(&db.Host, r.add("database.host", "DB_HOST", false), db.Host, "database host")
+	r.fs.IntVar(&db.Port, r.add("database.port", "DB_PORT", false), db.Port, "database port, 0 uses the driver default")
+	r.fs.StringVar(&db.Username, r.add("database.username", "DB_USERNAME", false), db.Username, "database user")
+	r.fs.StringVar(&db.Password, r.add("database.password", "DB_PASSWORD", true), db.Password, "database password")
+	r.fs.StringVar(&db.Name, r.add("database.name", "DB_NAME", false), db.Name, "database name")
+	r.fs.StringVar(&db.Path, r.add("database.path", "DB_PATH", false), db.Path, "sqlite database file")
+	r.fs.StringVar(&db.TLSMode, r.add("database.tls_mode", "DB_TLS_MODE", false), db.TLSMode, "disable, require, verify-ca or verify-full")
+	r.fs.StringVar(&db.CAFile, r.add("database.tls_ca", "DB_TLS_CA", false), db.CAFile, "PEM bundle of trusted CAs")
+	r.fs.DurationVar(&db.ConnectTimeout, r.add("database.connect_timeout", "DB_CONNECT_TIMEOUT", false), db.ConnectTimeout, "database dial timeout")
+	r.fs.DurationVar(&db.ReadTimeout, r.add("database.read_timeout", "DB_READ_TIMEOUT", false), db.ReadTimeout, "mysql read timeout")
+	r.fs.DurationVar(&db.WriteTimeout, r.add("database.write_timeout", "DB_WRITE_TIMEOUT", false), db.WriteTimeout, "mysql write timeout")
+	r.fs.DurationVar(&db.QueryTimeout, r.add("database.query_timeout", "DB_QUERY_TIMEOUT", false), db.QueryTimeout, "deadline of database calls of a request")
+	r.fs.IntVar(&db.MaxOpenConns, r.add("database.max_open_conns", "DB_MAX_OPEN_CONNS", false), db.MaxOpenConns, "open connections limit, 0 is unlimited")
+	r.fs.IntVar(&db.MaxIdleConns, r.add("database.max_idle_conns", "DB_MAX_IDLE_CONNS", false), db.MaxIdleConns, "idle connections limit")
+	r.fs.DurationVar(&db.ConnMaxLifetime, r.add("database.conn_max_lifetime", "DB_CONN_MAX_LIFETIME", false), db.ConnMaxLifetime, "connections are closed after this long, 0 keeps them")
+	r.fs.DurationVar(&db.ConnMaxIdleTime, r.add("database.conn_max_idle_time", "DB_CONN_MAX_IDLE_TIME", false), db.ConnMaxIdleTime, "idle connections are closed after this long, 0 keeps them")
+	r.fs.IntVar(&db.PingRetries, r.add("database.ping_retries", "DB_PING_RETRIES", false), db.PingRetries, "pings retried on start before giving up")
+	r.fs.DurationVar(&db.PingBackoff, r.add("database.ping_backoff", "DB_PING_BACKOFF", false), db.PingBackoff, "first wait between pings, doubled after every retry")
+	r.fs.StringVar(&db.MemorySnapshot, r.add("database.memory_snapshot", "MEMORY_SNAPSHOT", false), db.MemorySnapshot, "snapshot file of the memory store")
+
+	r.fs.StringVar(&cfg.Log.Level, r.add("log.level", "LOG_LEVEL", false), cfg.Log.Level, "lowest level logged, debug, info, warn or error")
+	r.fs.StringVar(&cfg.Log.Format, r.add("log.format", "LOG_FORMAT", false), cfg.Log.Format, "json or text")
+	r.fs.BoolVar(&cfg.Log.Redact, r.add("log.redact", "LOG_REDACT", false), cfg.Log.Redact, "replace article content and credentials in logs")
+
+	r.fs.Var((*listValue)(&cfg.CORS.AllowedOrigins), r.add("cors.allowed_origins", "CORS_ALLOWED_ORIGINS", false), "comma separated origins allowed to call the API, * allows any")
+	r.fs.Var((*listValue)(&cfg.CORS.AllowedMethods), r.add("cors.allowed_methods", "CORS_ALLOWED_METHODS", false), "comma separated methods allowed in cross origin requests")
+	r.fs.Var((*listValue)(&cfg.CORS.AllowedHeaders), r.add("cors.allowed_headers", "CORS_ALLOWED_HEADERS", false), "comma separated headers allowed in cross origin requests")
+	r.fs.DurationVar(&cfg.CORS.MaxAge, r.add("cors.max_age", "CORS_MAX_AGE", false), cfg.CORS.MaxAge, "how long browsers cache preflight responses")
+
+	r.fs.IntVar(&cfg.Limits.DefaultPageSize, r.add("limits.default_page_size", "DEFAULT_PAGE_SIZE", false), cfg.Limits.DefaultPageSize, "page size of lists without limit")
+	r.fs.IntVar(&cfg.Limits.MaxPageSize, r.add("limits.max_page_size", "MAX_PAGE_SIZE", false), cfg.Limits.MaxPageSize, "largest page size allowed")
+
+	r.fs.DurationVar(&cfg.Health.CheckTimeout, r.add("health.check_timeout", "HEALTH_CHECK_TIMEOUT", false), cfg.Health.CheckTimeout, "time each readiness check may take")
+
+	r.fs.StringVar(&cfg.Tracing.Exporter, r.add("tracing.exporter", "TRACING_EXPORTER", false), cfg.Tracing.Exporter, "none, stdout, file or otlp")
+	r.fs.StringVar(&cfg.Tracing.ServiceName, r.add("tracing.service_name", "TRACING_SERVICE_NAME", false), cfg.Tracing.ServiceName, "service name of spans")
+	r.fs.StringVar(&cfg.Tracing.Endpoint, r.add("tracing.endpoint", "TRACING_ENDPOINT", false), cfg.Tracing.Endpoint, "host:port of the OTLP/HTTP collector")
+	r.fs.BoolVar(&cfg.Tracing.Insecure, r.add("tracing.insecure", "TRACING_INSECURE", false), cfg.Tracing.Insecure, "send spans to the collector over plain HTTP")
+	r.fs.StringVar(&cfg.Tracing.File, r.add("tracing.file", "TRACING_FILE", false), cfg.Tracing.File, "file the file exporter appends spans to")
+	r.fs.Float64Var(&cfg.Tracing.SampleRatio, r.add("tracing.sample_ratio", "TRACING_SAMPLE_RATIO", false), cfg.Tracing.SampleRatio, "share of new traces sampled")
+
+	r.fs.BoolVar(&cfg.Features.AutoMigrate, r.add("features.auto_migrate", "AUTO_MIGRATE", false), cfg.Features.AutoMigrate, "apply pending migrations on start")
+	r.fs.BoolVar(&cfg.Features.Metrics, r.add("features.metrics", "METRICS_ENABLED", false), cfg.Features.Metrics, "expose Prometheus metrics on /metrics")
+
+	return r
+}
+
+// add records a setting and returns its flag name
+func (r *registry) add(key, env string, secret bool) string {
+	r.settings = append(r.settings, setting{key: key, env: env, secret: secret})
+
+	return key
+}
+
+// Load builds config from defaults, the YAML or TOML file named by -config flag or CONFIG_FILE env, env and flags,
+// each source overrides the previous ones. args are command line arguments without the program name,
+// arguments left after flags such as a subcommand are returned. lookupEnv is os.LookupEnv outside of tests
+func Load(args []string, lookupEnv func(key string) (string, bool)) (Config, []string, error) {
+	cfg := Default()
+	r := newRegistry(&cfg)
+
+	// flags are applied last, this pass only finds the config file
+	scratch := Default()
+	scan := newRegistry(&scratch)
+	scan.fs.SetOutput(io.Discard)
+
+	// invalid flags fail before the file is read, the reported flag set parses them again to print the usage
+	err := scan.fs.Parse(args)
+	if err != nil {
+		return cfg, nil, r.fs.Parse(args)
+	}
+
+	file := scan.file
+	if file == "" {
+		file, _ = lookupEnv("CONFIG_FILE")
+	}
+
+	if file != "" {
+		err := r.applyFile(file)
+		if err != nil {
+			return cfg, nil, err
+		}
+	}
+
+	for _, s := range r.settings {
+		// set but empty vars clear values of the file
+		val, ok := lookupEnv(s.env)
+		if !ok {
+			continue
+		}
+
+		err := r.fs.Set(s.key, val)
+		if err != nil {
+			return cfg, nil, fmt.Errorf("invalid %s : %w", s.env, err)
+		}
+	}
+
+	err = r.fs.Parse(args)
+	if err != nil {
+		return cfg, nil, err
+	}
+
+	return cfg, r.fs.Args(), cfg.Validate()
+}
+
+// applyFile sets values of a YAML or TOML file, the format is chosen by extension
+func (r *registry) applyFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file : %w", err)
+	}
+
+	values := map[string]interface{}{}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(raw, &values)
+	case ".toml":
+		err = toml.Unmarshal(raw, &values)
+	default:
+		return fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("parsing config file %s : %w", path, err)
+	}
+
+	flat := map[string]string{}
+	flatten("", values, flat)
+
+	for key, val := range flat {
+		if key == "config" || r.fs.Lookup(key) == nil {
+			return fmt.Errorf("unknown setting %s in %s", key, path)
+		}
+
+		err = r.fs.Set(key, val)
+		if err != nil {
+			return fmt.Errorf("invalid %s in %s : %w", key, path, err)
+		}
+	}
+
+	return nil
+}
+
+// flatten joins nested keys with dots and lists with commas so file values read like flags
+func flatten(prefix string, values map[string]interface{}, flat map[string]string) {
+	for key, val := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch v := val.(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			flatten(key, v, flat)
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+
+			flat[key] = strings.Join(items, ",")
+		default:
+			flat[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// Print writes cfg as YAML which Load accepts as config file, secrets which are set are redacted
+func Print(w io.Writer, cfg Config) error {
+	r := newRegistry(&cfg)
+	sections := map[string]map[string]interface{}{}
+
+	for _, s := range r.settings {
+		// secrets are left out so the printed config stays loadable, they come from env or flags
+		if s.secret {
+			continue
+		}
+
+		section, name, _ := strings.Cut(s.key, ".")
+		if sections[section] == nil {
+			sections[section] = map[string]interface{}{}
+		}
+
+		value := r.fs.Lookup(s.key).Value.(flag.Getter).Get()
+
+		switch v := value.(type) {
+		case time.Duration:
+			value = v.String()
+		case []string:
+			if v == nil {
+				value = []string{}
+			}
+		}
+
+		sections[section][name] = value
+	}
+
+	return yaml.NewEncoder(w).Encode(sections)
+}
+
+// listValue is a flag holding comma separated values
+type listValue []string
+
+// String returns values joined by commas
+func (l *listValue) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set replaces values with the comma separated values of s
+func (l *listValue) Set(s string) error {
+	var values []string
+
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	*l = values
+
+	return nil
+}
+
+// Get returns values
+func (l *listValue) Get() interface{} {
+	return []string(*l)
+}
@@ -0,0 +1,282 @@
+package database
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// TLS modes, named after postgres sslmode values
+const (
+	// TLSDisable connects without TLS
+	TLSDisable = "disable"
+
+	// TLSRequire encrypts the connection without verifying the server certificate
+	TLSRequire = "require"
+
+	// TLSVerifyCA verifies the server certificate is signed by a trusted CA
+	TLSVerifyCA = "verify-ca"
+
+	// TLSVerifyFull verifies the CA and that the certificate matches the host
+	TLSVerifyFull = "verify-full"
+)
+
+// mysqlTLSConfig is the name the CA verifying tls config is registered with in the mysql driver
+const mysqlTLSConfig = "article"
+
+// Config holds database connection and pool settings
+type Config struct {
+	// Driver is one of MySQL, Postgres or SQLite
+	Driver string
+
+	// DSN is passed to the driver as is, connection fields below are ignored when it is set
+	DSN string
+
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Name     string
+
+	// Path is the database file of SQLite
+	Path string
+
+	// TLSMode is one of the TLS modes, CAFile is a PEM bundle of trusted CAs, system roots are used when empty
+	TLSMode string
+	CAFile  string
+
+	// ConnectTimeout bounds dialing, ReadTimeout and WriteTimeout bound network I/O of MySQL connections
+	ConnectTimeout time.Duration
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+
+	// pool settings of sql.DB, zero values mean no limit
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// PingRetries is the number of pings retried after the first one fails,
+	// waits start at PingBackoff and double up to maxPingBackoff
+	PingRetries int
+	PingBackoff time.Duration
+}
+
+// DefaultConfig returns settings used for values not configured
+func DefaultConfig() Config {
+	return Config{
+		Driver:          MySQL,
+		Host:            "localhost",
+		Username:        "root",
+		Password:        "root",
+		Name:            "article",
+		Path:            "article.db",
+		TLSMode:         TLSDisable,
+		ConnectTimeout:  10 * time.Second,
+		ReadTimeout:     30 * time.Second,
+		WriteTimeout:    30 * time.Second,
+		MaxOpenConns:    25,
+		MaxIdleConns:    25,
+		ConnMaxLifetime: 5 * time.Minute,
+		ConnMaxIdleTime: time.Minute,
+		PingRetries:     8,
+		PingBackoff:     500 * time.Millisecond,
+	}
+}
+
+// Validate reports the first invalid setting
+func (c Config) Validate() error {
+	switch c.Driver {
+	case MySQL, Postgres, SQLite:
+	default:
+		return fmt.Errorf("unsupported driver %s", c.Driver)
+	}
+
+	switch c.TLSMode {
+	case TLSDisable, TLSRequire, TLSVerifyCA, TLSVerifyFull:
+	default:
+		return fmt.Errorf("unsupported TLS mode %s", c.TLSMode)
+	}
+
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("port %d is out of range", c.Port)
+	}
+
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 || c.PingRetries < 0 {
+		return errors.New("pool sizes and ping retries must not be negative")
+	}
+
+	for _, d := range []time.Duration{c.ConnectTimeout, c.ReadTimeout, c.WriteTimeout, c.ConnMaxLifetime, c.ConnMaxIdleTime, c.PingBackoff} {
+		if d < 0 {
+			return errors.New("timeouts must not be negative")
+		}
+	}
+
+	return nil
+}
+
+// DataSourceName returns DSN passed to the driver, DSN field is returned when set
+func (c Config) DataSourceName() (string, error) {
+	if c.DSN != "" {
+		return c.DSN, nil
+	}
+
+	switch c.Driver {
+	case MySQL:
+		return c.mysqlDSN(), nil
+	case Postgres:
+		return c.postgresDSN(), nil
+	case SQLite:
+		// writers wait for each other instead of failing with SQLITE_BUSY,
+		// transactions take the write lock upfront so they cannot deadlock upgrading a read lock
+		return fmt.Sprintf("%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_txlock=immediate", c.Path), nil
+	}
+
+	return "", fmt.Errorf("unsupported driver %s", c.Driver)
+}
+
+// address returns host and port, port falls back to the driver default
+func (c Config) address(defaultPort int) string {
+	port := c.Port
+	if port == 0 {
+		port = defaultPort
+	}
+
+	return net.JoinHostPort(c.Host, strconv.Itoa(port))
+}
+
+// mysqlDSN returns mysql DSN, verifying TLS modes refer to the config added by registerTLS
+func (c Config) mysqlDSN() string {
+	cfg := mysql.NewConfig()
+	cfg.User = c.Username
+	cfg.Passwd = c.Password
+	cfg.Net = "tcp"
+	cfg.Addr = c.address(3306)
+	cfg.DBName = c.Name
+	cfg.Timeout = c.ConnectTimeout
+	cfg.ReadTimeout = c.ReadTimeout
+	cfg.WriteTimeout = c.WriteTimeout
+
+	// timestamps are scanned into time.Time, session and driver both use UTC.
+	// clientFoundRows makes updates report matched rather than changed rows
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	cfg.Params = map[string]string{"time_zone": "'+00:00'"}
+	cfg.ClientFoundRows = true
+
+	switch c.TLSMode {
+	case TLSRequire:
+		cfg.TLSConfig = "skip-verify"
+	case TLSVerifyCA, TLSVerifyFull:
+		cfg.TLSConfig = mysqlTLSConfig
+	}
+
+	return cfg.FormatDSN()
+}
+
+// registerTLS adds the CA verifying tls config to the mysql driver, other drivers read CAFile themselves
+func (c Config) registerTLS() error {
+	if c.Driver != MySQL || (c.TLSMode != TLSVerifyCA && c.TLSMode != TLSVerifyFull) {
+		return nil
+	}
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	return mysql.RegisterTLSConfig(mysqlTLSConfig, tlsConfig)
+}
+
+// postgresDSN returns postgres URL, the driver handles TLS modes natively
+func (c Config) postgresDSN() string {
+	query := url.Values{}
+	query.Set("sslmode", c.TLSMode)
+
+	if c.CAFile != "" {
+		query.Set("sslrootcert", c.CAFile)
+	}
+
+	if c.ConnectTimeout > 0 {
+		// whole seconds, a shorter timeout still waits one second
+		seconds := int((c.ConnectTimeout + time.Second - 1) / time.Second)
+		query.Set("connect_timeout", strconv.Itoa(seconds))
+	}
+
+	// session uses UTC so CURRENT_TIMESTAMP matches timestamps written by other drivers
+	query.Set("timezone", "UTC")
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.Username, c.Password),
+		Host:     c.address(5432),
+		Path:     c.Name,
+		RawQuery: query.Encode(),
+	}
+
+	return dsn.String()
+}
+
+// tlsConfig returns tls config verifying the server certificate against CAFile or system roots,
+// verify-ca skips matching the certificate against the host
+func (c Config) tlsConfig() (*tls.Config, error) {
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle : %w", err)
+		}
+
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", c.CAFile)
+		}
+	}
+
+	if c.TLSMode == TLSVerifyFull {
+		return &tls.Config{RootCAs: roots, ServerName: c.Host, MinVersion: tls.VersionTLS12}, nil
+	}
+
+	// verification of the chain is done by hand as the default one checks the host as well
+	return &tls.Config{
+		RootCAs:            roots,
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("server sent no certificate")
+			}
+
+			certs := make([]*x509.Certificate, len(rawCerts))
+			for i, raw := range rawCerts {
+				cert, err := x509.ParseCertificate(raw)
+				if err != nil {
+					return err
+				}
+
+				certs[i] = cert
+			}
+
+			opts := x509.VerifyOptions{Roots: roots, Intermediates: x509.NewCertPool()}
+			for _, cert := range certs[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+
+			_, err := certs[0].Verify(opts)
+
+			return err
+		},
+	}, nil
+}
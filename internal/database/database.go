@@ -1,47 +1,139 @@
 package database
 
 import (
+	"article/internal/logging"
+	"context"
 	"database/sql"
-	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
 )
 
-var (
-	username = "root"
-	password = "root"
-	database = "article"
-	host     = "localhost"
+// database drivers selected by DB_DRIVER
+const (
+	MySQL    = "mysql"
+	Postgres = "postgres"
+	SQLite   = "sqlite"
 )
 
-// InitDB used to connect mysql database
-func InitDB() (*sql.DB, error) {
-	logger := log.New(log.Default().Writer(), "", 1)
+// maxPingBackoff caps the wait between pings
+const maxPingBackoff = 10 * time.Second
 
-	// get username from env
-	if envUsername := os.Getenv("DB_USERNAME"); envUsername != "" {
-		username = envUsername
+// driverNames maps drivers to names they are registered with in database/sql
+var driverNames = map[string]string{
+	MySQL:    "mysql",
+	Postgres: "pgx",
+	SQLite:   "sqlite",
+}
+
+// InitDB used to connect database described by cfg, it pings the database
+// until it answers so the application may start before the database does.
+// It logs through the logger of ctx
+func InitDB(ctx context.Context, cfg Config) (*sql.DB, error) {
+	logger := logging.FromContext(ctx).With(slog.String("driver", cfg.Driver))
+
+	err := cfg.Validate()
+	if err != nil {
+		logger.Error("error connecting database", logging.Err(err))
+
+		return nil, err
 	}
 
-	// get password from env
-	if envPassword := os.Getenv("DB_PASSWORD"); envPassword != "" {
-		password = envPassword
+	err = cfg.registerTLS()
+	if err != nil {
+		logger.Error("error connecting database", logging.Err(err))
+
+		return nil, err
 	}
 
-	// get host from env
-	if envHost := os.Getenv("DB_HOST"); envHost != "" {
-		host = envHost
+	dsn, err := cfg.DataSourceName()
+	if err != nil {
+		logger.Error("error connecting database", logging.Err(err))
+
+		return nil, err
 	}
 
 	// open database connection
-	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:3306)/%s", username, password, host, database))
+	db, err := sql.Open(driverNames[cfg.Driver], dsn)
+	if err != nil {
+		logger.Error("error connecting database", logging.Err(err))
+
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	err = ping(ctx, logger, db, cfg)
 	if err != nil {
-		logger.Println("error connecting database : ", err)
+		logger.Error("error connecting database", logging.Err(err))
+		db.Close()
 
 		return nil, err
 	}
 
+	logger.Info("database connected")
+
 	return db, nil
 }
+
+// ping pings db retrying with exponential backoff, the last error is returned once retries run out
+func ping(ctx context.Context, logger *slog.Logger, db *sql.DB, cfg Config) error {
+	backoff := cfg.PingBackoff
+
+	for attempt := 0; ; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+		if cfg.ConnectTimeout == 0 {
+			pingCtx, cancel = context.WithCancel(ctx)
+		}
+
+		err := db.PingContext(pingCtx)
+		cancel()
+
+		if err == nil || attempt >= cfg.PingRetries {
+			return err
+		}
+
+		logger.Warn("error pinging database, retrying", slog.Int("attempt", attempt+1), slog.Duration("backoff", backoff), logging.Err(err))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxPingBackoff {
+			backoff = maxPingBackoff
+		}
+	}
+}
+
+// Rebind replaces ? placeholders of query with the numbered $1, $2... placeholders of postgres,
+// query must not hold ? in literals or identifiers
+func Rebind(query string) string {
+	var (
+		b strings.Builder
+		n int
+	)
+
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+
+			continue
+		}
+
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+
+	return b.String()
+}
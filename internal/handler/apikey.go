@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"article/internal/auth"
+	"article/internal/logging"
+	"article/internal/models"
+	"article/internal/policy"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+)
+
+// defaultOverlap is how long a rotated key keeps working when no overlap is requested
+const defaultOverlap = 24 * time.Hour
+
+// APIKeyRequest used in create API key request
+type APIKeyRequest struct {
+	Name   string   `json:"name" validate:"required,max=255"`
+	Scopes []string `json:"scopes" validate:"required,min=1"`
+
+	// ExpiresIn is a Go duration such as 720h, keys without it do not expire
+	ExpiresIn string `json:"expires_in"`
+}
+
+// APIKeyResponse used in API key responses, Key is only set when a key is issued
+type APIKeyResponse struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name"`
+	Prefix string   `json:"prefix"`
+	Scopes []string `json:"scopes"`
+	Key    string   `json:"key,omitempty"`
+
+	// timestamps are RFC 3339 in UTC, unset ones are left out
+	CreatedAt  string `json:"created_at"`
+	ExpiresAt  string `json:"expires_at,omitempty"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+	RevokedAt  string `json:"revoked_at,omitempty"`
+}
+
+// CreateAPIKey issues an API key, admin only. The key is only returned in this response
+func (app *Application) CreateAPIKey() http.HandlerFunc {
+	return app.traced("CreateAPIKey", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.ManageAPIKeys, policy.Resource{}) {
+			return
+		}
+
+		var req APIKeyRequest
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			app.log(r).Info("error decoding request body", logging.Err(err))
+			app.response.BadRequest(w, "invalid request")
+
+			return
+		}
+
+		req.Name = strings.TrimSpace(req.Name)
+
+		err = app.validate.Struct(&req)
+		if err != nil {
+			app.log(r).Info("error validating request", logging.Err(err))
+
+			msg, errs := validationErrors(&req, err.(validator.ValidationErrors))
+			app.response.ValidationFailed(w, msg, errs)
+
+			return
+		}
+
+		scopes, err := auth.ParseScopes(strings.Join(req.Scopes, " "))
+		if err != nil {
+			app.log(r).Info("invalid scopes", logging.Err(err))
+			app.response.UnprocessableEntity(w, err.Error())
+
+			return
+		}
+
+		ttl, err := duration(req.ExpiresIn, 0)
+		if err != nil {
+			app.log(r).Info("invalid expires_in", logging.Err(err))
+			app.response.BadRequest(w, "invalid expires_in value")
+
+			return
+		}
+
+		token, key, err := app.apiKeys.Create(ctx, req.Name, scopes, ttl)
+		if err != nil {
+			app.storeError(ctx, w, err, "error creating api key")
+
+			return
+		}
+
+		app.log(r).Info("api key created", slog.Int("key_id", key.ID))
+
+		resp := newAPIKeyResponse(key)
+		resp.Key = token
+
+		app.response.Created(w, resp)
+	})
+}
+
+// GetAPIKeys lists API keys without their secrets, admin only
+func (app *Application) GetAPIKeys() http.HandlerFunc {
+	return app.traced("GetAPIKeys", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.ManageAPIKeys, policy.Resource{}) {
+			return
+		}
+
+		keys, err := app.apiKeys.List(ctx)
+		if err != nil {
+			app.storeError(ctx, w, err, "error listing api keys")
+
+			return
+		}
+
+		resp := []APIKeyResponse{}
+		for _, key := range keys {
+			resp = append(resp, newAPIKeyResponse(key))
+		}
+
+		app.response.Success(w, resp)
+	})
+}
+
+// RevokeAPIKey revokes an API key immediately, admin only
+func (app *Application) RevokeAPIKey() http.HandlerFunc {
+	return app.traced("RevokeAPIKey", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.ManageAPIKeys, policy.Resource{}) {
+			return
+		}
+
+		id, err := app.keyID(w, r)
+		if err != nil {
+			return
+		}
+
+		err = app.apiKeys.Revoke(ctx, id)
+		if err != nil {
+			app.storeError(ctx, w, err, "error revoking api key")
+
+			return
+		}
+
+		app.log(r).Info("api key revoked", slog.Int("key_id", id))
+
+		app.response.Success(w, nil)
+	})
+}
+
+// RotateAPIKey issues a key replacing an API key, admin only. The old key keeps working
+// for the overlap query param, a Go duration defaulting to 24h
+func (app *Application) RotateAPIKey() http.HandlerFunc {
+	return app.traced("RotateAPIKey", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.ManageAPIKeys, policy.Resource{}) {
+			return
+		}
+
+		id, err := app.keyID(w, r)
+		if err != nil {
+			return
+		}
+
+		overlap, err := duration(r.URL.Query().Get("overlap"), defaultOverlap)
+		if err != nil {
+			app.log(r).Info("invalid overlap", logging.Err(err))
+			app.response.BadRequest(w, "invalid overlap value")
+
+			return
+		}
+
+		token, key, err := app.apiKeys.Rotate(ctx, id, overlap)
+		if errors.Is(err, auth.ErrKeyInactive) {
+			app.log(r).Info("rotating inactive api key", slog.Int("key_id", id))
+			app.response.Conflict(w, err.Error())
+
+			return
+		}
+
+		if err != nil {
+			app.storeError(ctx, w, err, "error rotating api key")
+
+			return
+		}
+
+		app.log(r).Info("api key rotated", slog.Int("key_id", id), slog.Int("new_key_id", key.ID))
+
+		resp := newAPIKeyResponse(key)
+		resp.Key = token
+
+		app.response.Created(w, resp)
+	})
+}
+
+// keyID fetches keyID from url params
+func (app *Application) keyID(w http.ResponseWriter, r *http.Request) (int, error) {
+	keyID := chi.URLParam(r, "key_id")
+
+	id, err := strconv.Atoi(keyID)
+	if err != nil || id < 1 {
+		app.log(r).Info("invalid key id", slog.String("key_id", keyID))
+		app.response.BadRequest(w, "invalid key id")
+
+		return 0, errors.New("invalid key id")
+	}
+
+	return id, nil
+}
+
+// duration parses a non negative Go duration, empty s returns def
+func duration(s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if d < 0 {
+		return 0, errors.New("duration must not be negative")
+	}
+
+	return d, nil
+}
+
+// newAPIKeyResponse prepares response from API key model
+func newAPIKeyResponse(key *models.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedAt:  formatTime(key.CreatedAt),
+		ExpiresAt:  formatTimePtr(key.ExpiresAt),
+		LastUsedAt: formatTimePtr(key.LastUsedAt),
+		RevokedAt:  formatTimePtr(key.RevokedAt),
+	}
+}
+
+// formatTimePtr formats t like formatTime, nil is left empty
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return formatTime(*t)
+}
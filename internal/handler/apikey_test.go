@@ -0,0 +1,200 @@
+package handler_test
+
+import (
+	"article/internal/auth"
+	"article/internal/handler"
+	"article/internal/models"
+	"article/internal/response"
+	"article/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		adminToken string
+
+		// principal authenticated by other credentials, it takes precedence over the admin token
+		principal    *auth.Principal
+		body         string
+		mockDB       func() *handler.Application
+		wantRespBody response.Body
+	}{
+		{
+			name:       "success",
+			adminToken: "secret",
+			body:       `{"name":"importer","scopes":["articles:read","articles:write"],"expires_in":"720h"}`,
+			mockDB: func() *handler.Application {
+				keyMock := mocks.NewAPIKeyStore(t)
+				keyMock.EXPECT().Create(mock.Anything, mock.MatchedBy(func(key *models.APIKey) bool {
+					return key.Name == "importer" && len(key.Hash) == 64 && key.ExpiresAt != nil &&
+						key.ExpiresAt.Sub(key.CreatedAt) == 720*time.Hour
+				})).RunAndReturn(func(_ context.Context, key *models.APIKey) (int64, error) {
+					key.ID = 1
+
+					return 1, nil
+				})
+
+				return handler.NewWithConfig(&models.Models{APIKey: keyMock}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusCreated, Message: response.StatusSuccess},
+		},
+		{
+			name:       "error : invalid admin token",
+			adminToken: "invalid",
+			body:       `{"name":"importer","scopes":["articles:read"]}`,
+			mockDB: func() *handler.Application {
+				return handler.NewWithConfig(&models.Models{APIKey: mocks.NewAPIKeyStore(t)}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "admin access required"},
+		},
+		{
+			name:       "error : admin token does not override other credentials",
+			adminToken: "secret",
+			principal:  &auth.Principal{Subject: "u-1", Role: auth.RoleAuthor},
+			body:       `{"name":"importer","scopes":["articles:read"]}`,
+			mockDB: func() *handler.Application {
+				return handler.NewWithConfig(&models.Models{APIKey: mocks.NewAPIKeyStore(t)}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "admin access required"},
+		},
+		{
+			name:       "error : unknown scope",
+			adminToken: "secret",
+			body:       `{"name":"importer","scopes":["articles:delete"]}`,
+			mockDB: func() *handler.Application {
+				return handler.NewWithConfig(&models.Models{APIKey: mocks.NewAPIKeyStore(t)}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusUnprocessableEntity, Message: "unknown scope articles:delete"},
+		},
+		{
+			name:       "error : invalid expires_in",
+			adminToken: "secret",
+			body:       `{"name":"importer","scopes":["articles:read"],"expires_in":"-1h"}`,
+			mockDB: func() *handler.Application {
+				return handler.NewWithConfig(&models.Models{APIKey: mocks.NewAPIKeyStore(t)}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid expires_in value"},
+		},
+		{
+			name:       "error : database error",
+			adminToken: "secret",
+			body:       `{"name":"importer","scopes":["articles:read"]}`,
+			mockDB: func() *handler.Application {
+				keyMock := mocks.NewAPIKeyStore(t)
+				keyMock.EXPECT().Create(mock.Anything, mock.Anything).Return(0, errors.New("db error"))
+
+				return handler.NewWithConfig(&models.Models{APIKey: keyMock}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error creating api key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			r := httptest.NewRequest(http.MethodPost, "/admin/keys", strings.NewReader(tt.body))
+			r.Header.Set("X-Admin-Token", tt.adminToken)
+
+			if tt.principal != nil {
+				r = r.WithContext(auth.WithPrincipal(r.Context(), tt.principal))
+			}
+
+			resp := serveRequest(t, r, app.AdminToken(app.CreateAPIKey()).ServeHTTP, nil)
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+
+			// the key is returned once, on creation
+			if resp.Status == http.StatusCreated {
+				data, err := json.Marshal(resp.Data)
+				require.NoError(t, err)
+
+				var key handler.APIKeyResponse
+				require.NoError(t, json.Unmarshal(data, &key))
+				assert.True(t, strings.HasPrefix(key.Key, "ak_"+key.Prefix+"."))
+			}
+		})
+	}
+}
+
+func Test_RevokeAPIKey(t *testing.T) {
+	admin := &auth.Principal{Subject: "u-1", Scopes: []string{auth.ScopeAdmin}, Role: auth.RoleAdmin}
+
+	tests := []struct {
+		name         string
+		principal    *auth.Principal
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody response.Body
+	}{
+		{
+			name:      "success - admin scope",
+			principal: admin,
+			urlParams: map[string]string{"key_id": "1"},
+			mockDB: func() *handler.Application {
+				keyMock := mocks.NewAPIKeyStore(t)
+				keyMock.EXPECT().Revoke(mock.Anything, 1, mock.Anything).Return(nil)
+
+				return handler.New(&models.Models{APIKey: keyMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error : write scope",
+			principal: &auth.Principal{Subject: "u-1", Scopes: []string{auth.ScopeWrite}, Role: auth.RoleAuthor},
+			urlParams: map[string]string{"key_id": "1"},
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{APIKey: mocks.NewAPIKeyStore(t)})
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "admin access required"},
+		},
+		{
+			name:      "error : invalid key id",
+			principal: admin,
+			urlParams: map[string]string{"key_id": "abc"},
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{APIKey: mocks.NewAPIKeyStore(t)})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid key id"},
+		},
+		{
+			name:      "error : key not found",
+			principal: admin,
+			urlParams: map[string]string{"key_id": "2"},
+			mockDB: func() *handler.Application {
+				keyMock := mocks.NewAPIKeyStore(t)
+				keyMock.EXPECT().Revoke(mock.Anything, 2, mock.Anything).Return(models.ErrAPIKeyNotFound)
+
+				return handler.New(&models.Models{APIKey: keyMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusNotFound, Message: "api key not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			r := httptest.NewRequest(http.MethodDelete, "/admin/keys/1", nil)
+			r = r.WithContext(auth.WithPrincipal(r.Context(), tt.principal))
+
+			resp := serveRequest(t, r, app.RevokeAPIKey(), tt.urlParams)
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+		})
+	}
+}
@@ -1,26 +1,187 @@
 package handler
 
 import (
+	"article/internal/auth"
+	"article/internal/config"
+	"article/internal/health"
+	"article/internal/logging"
 	"article/internal/models"
+	"article/internal/policy"
 	"article/internal/response"
-	"log"
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
 
+	"github.com/go-chi/chi"
 	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel"
 )
 
+// tracerName names spans started by handlers
+const tracerName = "article/internal/handler"
+
 // Application used to hold objects
 type Application struct {
 	models   *models.Models
 	response response.Response
 	validate *validator.Validate
-	logger   *log.Logger
+
+	// adminToken grants key management when passed in X-Admin-Token header without other credentials
+	adminToken string
+
+	// apiKeys manages API keys of the API key store
+	apiKeys *auth.APIKeys
+
+	// policy decides which callers may perform which actions
+	policy policy.Policy
+
+	// pageSize is default and maxPageSize is the largest allowed list page size
+	pageSize    int
+	maxPageSize int
+
+	// queryTimeout is the deadline for database calls of a request
+	queryTimeout time.Duration
+
+	// draining is set on shutdown so readiness fails while in-flight requests finish
+	draining atomic.Bool
+
+	// health holds readiness checks
+	health *health.Registry
 }
 
+// New returns application configured with config.Default settings
 func New(models *models.Models) *Application {
-	return &Application{
+	return NewWithConfig(models, config.Default())
+}
+
+// NewWithConfig returns application configured with cfg, cfg is expected to be validated
+func NewWithConfig(models *models.Models, cfg config.Config) *Application {
+	app := &Application{
 		models:   models,
 		response: *response.New(),
 		validate: validator.New(),
-		logger:   log.New(log.Default().Writer(), "logger: ", 1),
+
+		policy: policy.Default,
+
+		adminToken:  cfg.Server.AdminToken,
+		pageSize:    cfg.Limits.DefaultPageSize,
+		maxPageSize: cfg.Limits.MaxPageSize,
+
+		queryTimeout: cfg.Database.QueryTimeout,
+
+		health: health.NewRegistry(cfg.Health.CheckTimeout),
+	}
+
+	if models != nil && models.APIKey != nil {
+		app.apiKeys = auth.NewAPIKeys(models.APIKey)
+	}
+
+	app.health.Register(health.Check{Name: "drain", Critical: true, Func: health.Draining(app.draining.Load)})
+
+	// default page size cannot exceed the max page size
+	if app.pageSize > app.maxPageSize {
+		app.pageSize = app.maxPageSize
+	}
+
+	return app
+}
+
+// queryContext returns request context bounded by the query timeout,
+// database calls stop when the deadline passes or the client goes away
+func (app *Application) queryContext(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(r.Context(), app.queryTimeout)
+}
+
+// traced serves requests of handler h in a span named after it, store calls of h are children of the span.
+// The request logger gets the route and the user
+func (app *Application) traced(name string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := otel.Tracer(tracerName).Start(r.Context(), "handler."+name)
+		defer span.End()
+
+		logger := logging.FromContext(ctx)
+		if rctx := chi.RouteContext(ctx); rctx != nil {
+			logger = logger.With(slog.String("route", rctx.RoutePattern()))
+		}
+
+		if p, ok := auth.FromContext(ctx); ok {
+			logger = logger.With(slog.String("user", p.Subject))
+		}
+
+		h(w, r.WithContext(logging.WithLogger(ctx, logger)))
+	}
+}
+
+// log returns logger of request r
+func (app *Application) log(r *http.Request) *slog.Logger {
+	return logging.FromContext(r.Context())
+}
+
+// caller returns principal of request r, nil is an anonymous caller
+func (app *Application) caller(r *http.Request) *auth.Principal {
+	if p, ok := auth.FromContext(r.Context()); ok {
+		return p
+	}
+
+	return nil
+}
+
+// AdminToken lets requests without other credentials act as the admin token principal when they send the
+// admin token in the X-Admin-Token header. It guards key management only so the first API key can be issued,
+// the token is disabled when not configured
+func (app *Application) AdminToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Admin-Token")
+		if _, ok := auth.FromContext(r.Context()); ok || app.adminToken == "" || token == "" {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(token), []byte(app.adminToken)) != 1 {
+			app.log(r).Info("invalid admin token")
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		app.log(r).Info("admin token accepted", slog.String("user", adminTokenPrincipal.Subject))
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), adminTokenPrincipal)))
+	})
+}
+
+// adminTokenPrincipal is the caller sending the admin token, it is logged apart from admins of tokens and keys
+var adminTokenPrincipal = &auth.Principal{Subject: "admin-token", Name: "admin-token", Scopes: []string{auth.ScopeAdmin}, Role: auth.RoleAdmin}
+
+// authorize responds 403 unless caller of r may perform action on resource
+func (app *Application) authorize(w http.ResponseWriter, r *http.Request, action policy.Action, resource policy.Resource) bool {
+	err := app.policy.Check(app.caller(r), action, resource)
+	if err == nil {
+		return true
 	}
+
+	app.log(r).Info("action denied by policy", slog.String("action", string(action)), logging.Err(err))
+	app.response.Forbidden(w, err.Error())
+
+	return false
+}
+
+// authorizeArticle responds 403 unless caller of r may perform action on article of id,
+// the article is only fetched when the caller may act on own articles only
+func (app *Application) authorizeArticle(ctx context.Context, w http.ResponseWriter, r *http.Request, action policy.Action, id int) bool {
+	var resource policy.Resource
+
+	if app.policy.OwnOnly(app.caller(r), action) {
+		article, err := app.findArticle(ctx, w, id, false)
+		if err != nil {
+			return false
+		}
+
+		resource.OwnerID = article.OwnerID
+	}
+
+	return app.authorize(w, r, action, resource)
 }
@@ -1,12 +1,21 @@
 package handler
 
 import (
+	"article/internal/auth"
+	"article/internal/logging"
 	"article/internal/models"
+	"article/internal/policy"
+	"article/internal/response"
+	"article/internal/search"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-playground/validator/v10"
@@ -19,37 +28,77 @@ type ArticleRequest struct {
 	Author  string `json:"author" validate:"required"`
 }
 
+// ArticlePatchRequest used in partial update request, nil fields are left unchanged
+type ArticlePatchRequest struct {
+	Title   *string `json:"title" validate:"omitempty,min=1"`
+	Content *string `json:"content" validate:"omitempty,min=1"`
+	Author  *string `json:"author" validate:"omitempty,min=1"`
+}
+
 // ArticleResponse used in response
 type ArticleResponse struct {
 	ID      int64  `json:"id"`
 	Title   string `json:"title,omitempty"`
 	Content string `json:"content,omitempty"`
-	Author  string `json:"author,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+
+	// Author holds id and name of the author, all its fields with expand=author
+	Author *AuthorResponse `json:"author,omitempty"`
+
+	// Status is the editorial status, PublishedAt is set once the article is published
+	Status      string `json:"status,omitempty"`
+	PublishedAt string `json:"published_at,omitempty"`
+
+	// CreatedAt and UpdatedAt are RFC 3339 timestamps in UTC
+	CreatedAt string `json:"created_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+
+	// Score and Snippet are set in search results
+	Score   float64 `json:"score,omitempty"`
+	Snippet string  `json:"snippet,omitempty"`
 }
 
 // CreateArticle stores an article with given details
 func (app *Application) CreateArticle() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+	return app.traced("CreateArticle", func(w http.ResponseWriter, r *http.Request) {
+		// database calls are bound to the request and its query timeout
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		// the author is the authenticated caller unless the caller may manage authors
+		principal, ok := auth.FromContext(r.Context())
+		if !ok {
+			auth.Unauthorized(w, r, auth.ErrNoCredentials)
+
+			return
+		}
+
+		if !app.authorize(w, r, policy.CreateArticle, policy.Resource{}) {
+			return
+		}
+
 		var req ArticleRequest
 
-		// validate request body
-		err := app.validateRequest(w, r, &req)
+		// validate request body, the author is the caller unless the caller may manage authors
+		err := app.validateRequest(w, r, &req, app.authorName(r))
 		if err != nil {
 			return
 		}
 
-		// prepare article model
+		// prepare article model, the caller owns the article
 		article := models.Article{
-			Title:   req.Title,
-			Content: req.Content,
-			Author:  req.Author,
+			Title:         req.Title,
+			Content:       req.Content,
+			Author:        req.Author,
+			OwnerID:       principal.Subject,
+			CreateAuthor:  app.mayManageAuthors(r),
+			AuthorSubject: app.authorSubject(r),
 		}
 
 		// store article
-		insertedID, err := app.models.Article.Store(&article)
+		insertedID, err := app.models.Article.Store(ctx, &article)
 		if err != nil {
-			app.logger.Println("error storing article : ", err)
-			app.response.InternalServerError(w, "error storing article")
+			app.storeError(ctx, w, err, "error storing article")
 
 			return
 		}
@@ -60,100 +109,594 @@ func (app *Application) CreateArticle() http.HandlerFunc {
 		}
 
 		app.response.Created(w, resp)
-	}
+	})
 }
 
 // GetArticle fetch an article using articleID
 func (app *Application) GetArticle() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// fetch articleID from url params
-		articleID := chi.URLParam(r, "article_id")
-		if articleID == "" {
-			app.logger.Println("article id not passed")
-			app.response.BadRequest(w, "please provide article id")
+	return app.traced("GetArticle", func(w http.ResponseWriter, r *http.Request) {
+		// database calls are bound to the request and its query timeout
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
 
+		if !app.authorize(w, r, policy.ReadArticle, policy.Resource{}) {
 			return
 		}
 
-		// convert articleID from string to integer
-		id, err := strconv.Atoi(articleID)
+		id, err := app.articleID(w, r)
 		if err != nil {
-			app.logger.Println("error converting articleID from string to integer")
-			app.response.InternalServerError(w, "error converting article id")
+			return
+		}
 
+		// admins may fetch soft deleted article
+		includeDeleted, err := app.includeDeleted(w, r)
+		if err != nil {
 			return
 		}
 
-		// get article by id
-		article, err := app.models.Article.GetByID(id)
+		expand, err := app.expandAuthor(w, r)
 		if err != nil {
-			app.logger.Println("error fetching article by articleID : ", err)
-			app.response.InternalServerError(w, "error fetching article by articleID")
+			return
+		}
 
+		// get article by id
+		article, err := app.findArticle(ctx, w, id, includeDeleted)
+		if err != nil {
 			return
 		}
 
-		if article.ID == 0 {
-			app.logger.Println("invalid article id")
-			app.response.BadRequest(w, "invalid article id")
+		// unpublished articles are hidden from callers who may not read them
+		if article.Status != models.StatusPublished &&
+			app.policy.Check(app.caller(r), policy.ReadUnpublished, policy.Resource{OwnerID: article.OwnerID}) != nil {
+			app.log(r).Info("unpublished article hidden", slog.Int("article_id", id), slog.String("status", string(article.Status)))
+			app.response.NotFound(w, models.ErrArticleNotFound.Error())
 
 			return
 		}
 
 		// prepare response
-		resp := []ArticleResponse{
-			{
-				ID:      int64(article.ID),
-				Title:   article.Title,
-				Content: article.Content,
-				Author:  article.Author,
-			},
+		resp := []ArticleResponse{newArticleResponse(article)}
+
+		if expand && app.expandAuthors(ctx, w, resp) != nil {
+			return
 		}
 
 		app.response.Success(w, resp)
-	}
+	})
 }
 
-// GetArticles fetchs all article
+// GetArticles fetchs a page of articles
 func (app *Application) GetArticles() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// get article by id
-		articles, err := app.models.Article.GetAll()
+	return app.traced("GetArticles", func(w http.ResponseWriter, r *http.Request) {
+		// database calls are bound to the request and its query timeout
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.ReadArticle, policy.Resource{}) {
+			return
+		}
+
+		opts, err := app.listOptions(w, r)
+		if err != nil {
+			return
+		}
+
+		expand, err := app.expandAuthor(w, r)
+		if err != nil {
+			return
+		}
+
+		// fetch one extra article to find out whether another page exists
+		limit := opts.Limit
+		opts.Limit++
+
+		// get page of articles
+		articles, err := app.models.Article.List(ctx, *opts)
+		if err != nil {
+			app.storeError(ctx, w, err, "error fetching all articles")
+
+			return
+		}
+
+		// count all articles
+		total, err := app.models.Article.Count(ctx, *opts)
 		if err != nil {
-			app.logger.Println("error fetching all article : ", err)
-			app.response.InternalServerError(w, "error fetching all articles")
+			app.storeError(ctx, w, err, "error counting articles")
 
 			return
 		}
 
+		articles, page := paginate(opts.Page, articles, limit, total, models.NewCursor)
+
 		// prepare response
 		resp := []ArticleResponse{}
 
 		for _, val := range articles {
-			a := ArticleResponse{
-				ID:      int64(val.ID),
-				Title:   val.Title,
-				Content: val.Content,
-				Author:  val.Author,
-			}
+			resp = append(resp, newArticleResponse(val))
+		}
+
+		if expand && app.expandAuthors(ctx, w, resp) != nil {
+			return
+		}
+
+		w.Header().Set("Link", pageLinks(r, page))
+
+		app.response.Paginated(w, resp, page)
+	})
+}
+
+// SearchArticles searches articles by title and content, ordered by relevance
+func (app *Application) SearchArticles() http.HandlerFunc {
+	return app.traced("SearchArticles", func(w http.ResponseWriter, r *http.Request) {
+		// database calls are bound to the request and its query timeout
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.ReadArticle, policy.Resource{}) {
+			return
+		}
+
+		query := search.Parse(r.URL.Query().Get("q"))
+		if query.Empty() {
+			app.log(r).Info("search query not passed")
+			app.response.BadRequest(w, "please provide search query")
+
+			return
+		}
+
+		page, err := app.page(w, r)
+		if err != nil {
+			return
+		}
+
+		statuses, ownerID, err := app.statusFilter(w, r)
+		if err != nil {
+			return
+		}
+
+		expand, err := app.expandAuthor(w, r)
+		if err != nil {
+			return
+		}
+
+		// fetch one extra result to find out whether another page exists
+		limit := page.Limit
+		page.Limit++
+
+		opts := models.SearchOptions{Query: query, Statuses: statuses, OwnerID: ownerID, Page: page}
+
+		// search articles
+		results, err := app.models.Article.Search(ctx, opts)
+		if err != nil {
+			app.storeError(ctx, w, err, "error searching articles")
+
+			return
+		}
+
+		// count all matching articles
+		total, err := app.models.Article.CountSearch(ctx, opts)
+		if err != nil {
+			app.storeError(ctx, w, err, "error counting search results")
+
+			return
+		}
+
+		results, pagination := paginate(page, results, limit, total, models.NewSearchCursor)
+
+		// prepare response
+		resp := []ArticleResponse{}
+
+		for _, val := range results {
+			a := newArticleResponse(val.Article)
+			a.Score = val.Score
+			a.Snippet = search.Snippet(val.Article.Content, query)
 
 			resp = append(resp, a)
 		}
 
-		app.response.Success(w, resp)
+		if expand && app.expandAuthors(ctx, w, resp) != nil {
+			return
+		}
+
+		w.Header().Set("Link", pageLinks(r, pagination))
+
+		app.response.Paginated(w, resp, pagination)
+	})
+}
+
+// UpdateArticle replaces an article with given details
+func (app *Application) UpdateArticle() http.HandlerFunc {
+	return app.traced("UpdateArticle", func(w http.ResponseWriter, r *http.Request) {
+		// database calls are bound to the request and its query timeout
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		id, err := app.articleID(w, r)
+		if err != nil {
+			return
+		}
+
+		// authors may only change articles they own
+		if !app.authorizeArticle(ctx, w, r, policy.UpdateArticle, id) {
+			return
+		}
+
+		var req ArticleRequest
+
+		// validate request body, the author is the caller unless the caller may manage authors
+		err = app.validateRequest(w, r, &req, app.authorName(r))
+		if err != nil {
+			return
+		}
+
+		article := &models.Article{
+			ID:            id,
+			Title:         req.Title,
+			Content:       req.Content,
+			Author:        req.Author,
+			CreateAuthor:  app.mayManageAuthors(r),
+			AuthorSubject: app.authorSubject(r),
+		}
+
+		// update article
+		err = app.models.Article.Update(ctx, article)
+		if err != nil {
+			app.storeError(ctx, w, err, "error updating article")
+
+			return
+		}
+
+		// fetch updated article with its new updated_at
+		article, err = app.findArticle(ctx, w, id, false)
+		if err != nil {
+			return
+		}
+
+		app.response.Success(w, newArticleResponse(article))
+	})
+}
+
+// PatchArticle partially updates an article using a JSON merge patch (RFC 7396)
+func (app *Application) PatchArticle() http.HandlerFunc {
+	return app.traced("PatchArticle", func(w http.ResponseWriter, r *http.Request) {
+		// database calls are bound to the request and its query timeout
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		id, err := app.articleID(w, r)
+		if err != nil {
+			return
+		}
+
+		// authors may only change articles they own
+		if !app.authorizeArticle(ctx, w, r, policy.UpdateArticle, id) {
+			return
+		}
+
+		var req ArticlePatchRequest
+
+		// validate request body
+		err = app.validatePatchRequest(w, r, &req)
+		if err != nil {
+			return
+		}
+
+		// only callers who may manage authors attribute an article to another author
+		if req.Author != nil && !app.authorize(w, r, policy.ManageAuthors, policy.Resource{}) {
+			return
+		}
+
+		patch := models.ArticlePatch{
+			Title:        req.Title,
+			Content:      req.Content,
+			Author:       req.Author,
+			CreateAuthor: app.mayManageAuthors(r),
+		}
+
+		// update article
+		err = app.models.Article.Patch(ctx, id, &patch)
+		if err != nil {
+			app.storeError(ctx, w, err, "error updating article")
+
+			return
+		}
+
+		// fetch patched article with its new updated_at
+		article, err := app.findArticle(ctx, w, id, false)
+		if err != nil {
+			return
+		}
+
+		app.response.Success(w, newArticleResponse(article))
+	})
+}
+
+// DeleteArticle soft deletes an article
+func (app *Application) DeleteArticle() http.HandlerFunc {
+	return app.traced("DeleteArticle", func(w http.ResponseWriter, r *http.Request) {
+		// database calls are bound to the request and its query timeout
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		id, err := app.articleID(w, r)
+		if err != nil {
+			return
+		}
+
+		// authors may only change articles they own
+		if !app.authorizeArticle(ctx, w, r, policy.DeleteArticle, id) {
+			return
+		}
+
+		// soft delete article
+		err = app.models.Article.Delete(ctx, id)
+		if err != nil {
+			app.storeError(ctx, w, err, "error deleting article")
+
+			return
+		}
+
+		app.response.Success(w, nil)
+	})
+}
+
+// RestoreArticle restores a soft deleted article
+func (app *Application) RestoreArticle() http.HandlerFunc {
+	return app.traced("RestoreArticle", func(w http.ResponseWriter, r *http.Request) {
+		// database calls are bound to the request and its query timeout
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		id, err := app.articleID(w, r)
+		if err != nil {
+			return
+		}
+
+		if !app.authorize(w, r, policy.RestoreArticle, policy.Resource{}) {
+			return
+		}
+
+		// restore article
+		err = app.models.Article.Restore(ctx, id)
+		if err != nil {
+			app.storeError(ctx, w, err, "error restoring article")
+
+			return
+		}
+
+		// fetch restored article
+		article, err := app.findArticle(ctx, w, id, false)
+		if err != nil {
+			return
+		}
+
+		app.response.Success(w, newArticleResponse(article))
+	})
+}
+
+// PurgeArticle permanently removes an article
+func (app *Application) PurgeArticle() http.HandlerFunc {
+	return app.traced("PurgeArticle", func(w http.ResponseWriter, r *http.Request) {
+		// database calls are bound to the request and its query timeout
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.PurgeArticle, policy.Resource{}) {
+			return
+		}
+
+		id, err := app.articleID(w, r)
+		if err != nil {
+			return
+		}
+
+		// purge article
+		err = app.models.Article.Purge(ctx, id)
+		if err != nil {
+			app.storeError(ctx, w, err, "error purging article")
+
+			return
+		}
+
+		app.response.Success(w, nil)
+	})
+}
+
+// articleID fetches articleID from url params
+func (app *Application) articleID(w http.ResponseWriter, r *http.Request) (int, error) {
+	articleID := chi.URLParam(r, "article_id")
+	if articleID == "" {
+		app.log(r).Info("article id not passed")
+		app.response.BadRequest(w, "please provide article id")
+
+		return 0, errors.New("article id not passed")
+	}
+
+	// convert articleID from string to integer, ids start from 1
+	id, err := strconv.Atoi(articleID)
+	if err != nil || id < 1 {
+		app.log(r).Info("invalid article id", slog.String("article_id", articleID))
+		app.response.BadRequest(w, "invalid article id")
+
+		return 0, errors.New("invalid article id")
 	}
+
+	return id, nil
 }
 
-// validateRequest validates request body
-func (app *Application) validateRequest(w http.ResponseWriter, r *http.Request, req *ArticleRequest) error {
+// findArticle fetches an article and responds 404 when it does not exist
+func (app *Application) findArticle(ctx context.Context, w http.ResponseWriter, id int, includeDeleted bool) (*models.Article, error) {
+	article, err := app.models.Article.GetByID(ctx, id, includeDeleted)
+	if err != nil {
+		app.storeError(ctx, w, err, "error fetching article by articleID")
+
+		return nil, err
+	}
+
+	return article, nil
+}
+
+// includeDeleted reads include_deleted query param, only callers allowed to read deleted articles may set it
+func (app *Application) includeDeleted(w http.ResponseWriter, r *http.Request) (bool, error) {
+	val := r.URL.Query().Get("include_deleted")
+	if val == "" {
+		return false, nil
+	}
+
+	includeDeleted, err := strconv.ParseBool(val)
+	if err != nil {
+		app.log(r).Info("error parsing include_deleted", logging.Err(err))
+		app.response.BadRequest(w, "invalid include_deleted value")
+
+		return false, err
+	}
+
+	if includeDeleted && !app.authorize(w, r, policy.ReadDeleted, policy.Resource{}) {
+		return false, policy.ErrForbidden
+	}
+
+	return includeDeleted, nil
+}
+
+// expandAuthor reads expand query param, author is the only field that can be expanded
+func (app *Application) expandAuthor(w http.ResponseWriter, r *http.Request) (bool, error) {
+	expand := false
+
+	for _, val := range r.URL.Query()["expand"] {
+		for _, field := range strings.Split(val, ",") {
+			field = strings.TrimSpace(field)
+			if field != "author" {
+				app.log(r).Info("invalid expand field", slog.String("field", field))
+				app.response.BadRequest(w, "invalid query parameters",
+					response.FieldError{Field: "expand", Rule: "oneof", Param: "author", Message: fmt.Sprintf("cannot expand '%s'", field)})
+
+				return false, errors.New("invalid expand field")
+			}
+
+			expand = true
+		}
+	}
+
+	return expand, nil
+}
+
+// expandAuthors replaces the compact authors of resp with all their fields, authors are fetched at once
+func (app *Application) expandAuthors(ctx context.Context, w http.ResponseWriter, resp []ArticleResponse) error {
+	var ids []int
+
+	seen := map[int]bool{}
+	for _, a := range resp {
+		if a.Author != nil && !seen[a.Author.ID] {
+			seen[a.Author.ID] = true
+			ids = append(ids, a.Author.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	authors, err := app.models.Author.GetByIDs(ctx, ids)
+	if err != nil {
+		app.storeError(ctx, w, err, "error fetching authors of articles")
+
+		return err
+	}
+
+	byID := map[int]*models.Author{}
+	for _, author := range authors {
+		byID[author.ID] = author
+	}
+
+	for i, a := range resp {
+		if a.Author == nil {
+			continue
+		}
+
+		if author, ok := byID[a.Author.ID]; ok {
+			resp[i].Author = newAuthorResponse(author)
+		}
+	}
+
+	return nil
+}
+
+// newArticleResponse prepares response from article model, the author is in compact form
+func newArticleResponse(article *models.Article) ArticleResponse {
+	resp := ArticleResponse{
+		ID:        int64(article.ID),
+		Title:     article.Title,
+		Content:   article.Content,
+		Deleted:   article.Deleted,
+		Status:    string(article.Status),
+		CreatedAt: formatTime(article.CreatedAt),
+		UpdatedAt: formatTime(article.UpdatedAt),
+	}
+
+	if article.PublishedAt != nil {
+		resp.PublishedAt = formatTime(*article.PublishedAt)
+	}
+
+	if article.AuthorID != 0 || article.Author != "" {
+		resp.Author = &AuthorResponse{ID: article.AuthorID, Name: article.Author}
+	}
+
+	return resp
+}
+
+// formatTime formats t as RFC 3339 in UTC, zero time is left empty
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// authorName returns the name articles written by the caller of r are attributed to,
+// it is empty for callers who may manage authors so they keep the author of the body
+func (app *Application) authorName(r *http.Request) string {
+	caller := app.caller(r)
+	if caller == nil || app.mayManageAuthors(r) {
+		return ""
+	}
+
+	return caller.Name
+}
+
+// authorSubject returns the subject whose own author writes articles of the caller of r, so the author
+// is created on first use and keeps working after editors rename it. It is empty when the caller may manage authors
+func (app *Application) authorSubject(r *http.Request) string {
+	caller := app.caller(r)
+	if caller == nil || app.mayManageAuthors(r) {
+		return ""
+	}
+
+	return caller.Subject
+}
+
+// mayManageAuthors reports whether the caller of r may manage authors, only such callers
+// create the author of an article when there is none
+func (app *Application) mayManageAuthors(r *http.Request) bool {
+	caller := app.caller(r)
+
+	return caller != nil && app.policy.Check(caller, policy.ManageAuthors, policy.Resource{}) == nil
+}
+
+// validateRequest validates request body, author replaces the author of the body when not empty
+func (app *Application) validateRequest(w http.ResponseWriter, r *http.Request, req *ArticleRequest, author string) error {
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		app.logger.Println("error decoding request body : ", err)
+		app.log(r).Info("error decoding request body", logging.Err(err))
 		app.response.BadRequest(w, "invalid request")
 
 		return err
 	}
 
+	if author != "" {
+		req.Author = author
+	}
+
 	// remove white space
 	// trimspace removes all leading and trailing space
 	req.Title = strings.TrimSpace(req.Title)
@@ -163,14 +706,67 @@ func (app *Application) validateRequest(w http.ResponseWriter, r *http.Request,
 	// validate request body
 	err = app.validate.Struct(req)
 	if err != nil {
-		app.logger.Println("error validating request : ", err)
+		app.log(r).Info("error validating request", logging.Err(err))
+
+		msg, errs := validationErrors(req, err.(validator.ValidationErrors))
+		app.response.ValidationFailed(w, msg, errs)
+
+		return err
+	}
+
+	return nil
+}
+
+// validatePatchRequest validates merge patch request body
+func (app *Application) validatePatchRequest(w http.ResponseWriter, r *http.Request, req *ArticlePatchRequest) error {
+	var fields map[string]json.RawMessage
+
+	err := json.NewDecoder(r.Body).Decode(&fields)
+	if err != nil {
+		app.log(r).Info("error decoding request body", logging.Err(err))
+		app.response.BadRequest(w, "invalid request")
+
+		return err
+	}
+
+	// a null member removes the field, which is not allowed for required fields
+	for _, name := range []string{"title", "content", "author"} {
+		if raw, ok := fields[name]; ok && string(raw) == "null" {
+			app.log(r).Info("null field in patch request", slog.String("field", name))
+			app.response.BadRequest(w, fmt.Sprintf("field '%s' cannot be removed", name))
 
-		var errorBag []string
-		for _, v := range err.(validator.ValidationErrors) {
-			errorBag = append(errorBag, strings.Split(v.Error(), "Error:")[1])
+			return fmt.Errorf("field %s cannot be removed", name)
 		}
+	}
+
+	for name, dst := range map[string]**string{"title": &req.Title, "content": &req.Content, "author": &req.Author} {
+		raw, ok := fields[name]
+		if !ok {
+			continue
+		}
+
+		var val string
+
+		err = json.Unmarshal(raw, &val)
+		if err != nil {
+			app.log(r).Info("error decoding request body", logging.Err(err))
+			app.response.BadRequest(w, "invalid request")
+
+			return err
+		}
+
+		// remove white space
+		val = strings.TrimSpace(val)
+		*dst = &val
+	}
+
+	// validate request body
+	err = app.validate.Struct(req)
+	if err != nil {
+		app.log(r).Info("error validating request", logging.Err(err))
 
-		app.response.BadRequest(w, fmt.Sprint(strings.Join(errorBag[:], ", ")))
+		msg, errs := validationErrors(req, err.(validator.ValidationErrors))
+		app.response.ValidationFailed(w, msg, errs)
 
 		return err
 	}
@@ -1,6 +1,8 @@
 package handler_test
 
 import (
+	"article/internal/auth"
+	"article/internal/config"
 	"article/internal/handler"
 	"article/internal/models"
 	"article/internal/response"
@@ -12,6 +14,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/stretchr/testify/assert"
@@ -23,19 +26,25 @@ func Test_CreateArticle(t *testing.T) {
 		req handler.ArticleRequest
 	}
 
+	jane := &auth.Principal{Subject: "u-1", Name: "Jane", Role: auth.RoleAuthor}
+	editor := &auth.Principal{Subject: "u-2", Name: "Ed", Role: auth.RoleEditor}
+
 	tests := []struct {
 		name         string
 		args         args
+		principal    *auth.Principal
 		mockDB       func() *handler.Application
 		wantResp     handler.ArticleResponse
 		wantRespBody response.Body
 	}{
 		{
-			name: "success",
-			args: args{req: handler.ArticleRequest{Title: "Test title", Content: "Test content", Author: "Test Author"}},
+			name:      "success",
+			args:      args{req: handler.ArticleRequest{Title: "Test title", Content: "Test content", Author: "Test Author"}},
+			principal: jane,
 			mockDB: func() *handler.Application {
 				articleMock := mocks.NewArticleStore(t)
-				articleMock.EXPECT().Store(mock.Anything).Return(1, nil)
+				// author is taken from the token and linked to its subject
+				articleMock.EXPECT().Store(mock.Anything, &models.Article{Title: "Test title", Content: "Test content", Author: "Jane", OwnerID: "u-1", AuthorSubject: "u-1"}).Return(1, nil)
 
 				m := models.Models{
 					Article: articleMock,
@@ -47,19 +56,44 @@ func Test_CreateArticle(t *testing.T) {
 			wantRespBody: response.Body{Status: http.StatusCreated, Message: response.StatusSuccess},
 		},
 		{
-			name: "validation error",
-			args: args{req: handler.ArticleRequest{Title: " ", Content: "Test content", Author: "Test Author"}},
+			name:      "success - editor writes for another author",
+			args:      args{req: handler.ArticleRequest{Title: "Test title", Content: "Test content", Author: "Test Author"}},
+			principal: editor,
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				// the editor owns the article, the author is taken from the body
+				articleMock.EXPECT().Store(mock.Anything, &models.Article{Title: "Test title", Content: "Test content", Author: "Test Author", OwnerID: "u-2", CreateAuthor: true}).Return(2, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantResp:     handler.ArticleResponse{ID: 2},
+			wantRespBody: response.Body{Status: http.StatusCreated, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error - editor without author",
+			args:      args{req: handler.ArticleRequest{Title: "Test title", Content: "Test content"}},
+			principal: editor,
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "Field validation for 'Author' failed on the 'required' tag"},
+		},
+		{
+			name:      "validation error",
+			args:      args{req: handler.ArticleRequest{Title: " ", Content: "Test content", Author: "Test Author"}},
+			principal: jane,
 			mockDB: func() *handler.Application {
 				return handler.New(&models.Models{})
 			},
 			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "Field validation for 'Title' failed on the 'required' tag"},
 		},
 		{
-			name: "error",
-			args: args{req: handler.ArticleRequest{Title: "Test title", Content: "Test content", Author: "Test Author"}},
+			name:      "error",
+			args:      args{req: handler.ArticleRequest{Title: "Test title", Content: "Test content", Author: "Test Author"}},
+			principal: jane,
 			mockDB: func() *handler.Application {
 				articleMock := mocks.NewArticleStore(t)
-				articleMock.EXPECT().Store(mock.Anything).Return(0, errors.New("error storing article"))
+				articleMock.EXPECT().Store(mock.Anything, mock.Anything).Return(0, errors.New("error storing article"))
 
 				m := models.Models{
 					Article: articleMock,
@@ -69,6 +103,14 @@ func Test_CreateArticle(t *testing.T) {
 			},
 			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error storing article"},
 		},
+		{
+			name: "unauthenticated",
+			args: args{req: handler.ArticleRequest{Title: "Test title", Content: "Test content", Author: "Test Author"}},
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantRespBody: response.Body{Status: http.StatusUnauthorized, Message: "authentication required"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -76,12 +118,15 @@ func Test_CreateArticle(t *testing.T) {
 			// mock database calls
 			app := tt.mockDB()
 
-			handlerFunc := app.CreateArticle()
-			resp, err := callEndpoint(t, &tt.args.req, handlerFunc, nil)
-			if err != nil {
-				t.Errorf("error in call endpoint : %v", err)
+			rawReq, _ := json.Marshal(&tt.args.req)
+			r := httptest.NewRequest(http.MethodPost, "/articles", bytes.NewBuffer(rawReq))
+
+			if tt.principal != nil {
+				r = r.WithContext(auth.WithPrincipal(r.Context(), tt.principal))
 			}
 
+			resp := serveRequest(t, r, app.CreateArticle(), nil)
+
 			// convert response data into struct
 			var gotResp handler.ArticleResponse
 			aa, err := json.Marshal(resp.Data)
@@ -114,7 +159,7 @@ func Test_GetArticle(t *testing.T) {
 			urlParams: map[string]string{"article_id": "1"},
 			mockDB: func() *handler.Application {
 				articleMock := mocks.NewArticleStore(t)
-				articleMock.EXPECT().GetByID(mock.Anything).Return(&models.Article{
+				articleMock.EXPECT().GetByID(mock.Anything, mock.Anything, false).Return(&models.Article{
 					ID:      1,
 					Title:   "Test title",
 					Content: "Test content",
@@ -127,7 +172,7 @@ func Test_GetArticle(t *testing.T) {
 
 				return handler.New(&m)
 			},
-			wantResp:     handler.ArticleResponse{ID: 1, Title: "Test title", Content: "Test content", Author: "Test author"},
+			wantResp:     handler.ArticleResponse{ID: 1, Title: "Test title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test author"}},
 			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
 		},
 		{
@@ -144,14 +189,14 @@ func Test_GetArticle(t *testing.T) {
 			mockDB: func() *handler.Application {
 				return handler.New(&models.Models{})
 			},
-			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error converting article id"},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid article id"},
 		},
 		{
 			name:      "error : database error",
 			urlParams: map[string]string{"article_id": "1"},
 			mockDB: func() *handler.Application {
 				articleMock := mocks.NewArticleStore(t)
-				articleMock.EXPECT().GetByID(mock.Anything).Return(nil, errors.New("error fetching article by articleID"))
+				articleMock.EXPECT().GetByID(mock.Anything, mock.Anything, false).Return(nil, errors.New("error fetching article by articleID"))
 
 				m := models.Models{
 					Article: articleMock,
@@ -161,12 +206,48 @@ func Test_GetArticle(t *testing.T) {
 			},
 			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error fetching article by articleID"},
 		},
+		{
+			name:      "error : query timeout",
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				// database call must be bound by a deadline
+				hasDeadline := mock.MatchedBy(func(ctx context.Context) bool {
+					_, ok := ctx.Deadline()
+					return ok
+				})
+
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(hasDeadline, 1, false).Return(nil, context.DeadlineExceeded)
+
+				m := models.Models{
+					Article: articleMock,
+				}
+
+				return handler.New(&m)
+			},
+			wantRespBody: response.Body{Status: http.StatusGatewayTimeout, Message: "request timed out"},
+		},
+		{
+			name:      "error : client closed request",
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(nil, context.Canceled)
+
+				m := models.Models{
+					Article: articleMock,
+				}
+
+				return handler.New(&m)
+			},
+			wantRespBody: response.Body{Status: response.StatusClientClosedRequest, Message: "client closed request"},
+		},
 		{
 			name:      "error : article id not found",
 			urlParams: map[string]string{"article_id": "1"},
 			mockDB: func() *handler.Application {
 				articleMock := mocks.NewArticleStore(t)
-				articleMock.EXPECT().GetByID(mock.Anything).Return(&models.Article{}, nil)
+				articleMock.EXPECT().GetByID(mock.Anything, mock.Anything, false).Return(nil, models.ErrArticleNotFound)
 
 				m := models.Models{
 					Article: articleMock,
@@ -174,7 +255,7 @@ func Test_GetArticle(t *testing.T) {
 
 				return handler.New(&m)
 			},
-			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid article id"},
+			wantRespBody: response.Body{Status: http.StatusNotFound, Message: "article not found"},
 		},
 	}
 
@@ -223,7 +304,7 @@ func Test_GetArticles(t *testing.T) {
 			name: "success",
 			mockDB: func() *handler.Application {
 				articleMock := mocks.NewArticleStore(t)
-				articleMock.EXPECT().GetAll().Return([]*models.Article{
+				articleMock.EXPECT().List(mock.Anything, mock.Anything).Return([]*models.Article{
 					{
 						ID:      1,
 						Title:   "Test title",
@@ -231,6 +312,7 @@ func Test_GetArticles(t *testing.T) {
 						Author:  "Test author",
 					},
 				}, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(1, nil)
 
 				m := models.Models{
 					Article: articleMock,
@@ -238,14 +320,14 @@ func Test_GetArticles(t *testing.T) {
 
 				return handler.New(&m)
 			},
-			wantResp:     handler.ArticleResponse{ID: 1, Title: "Test title", Content: "Test content", Author: "Test author"},
+			wantResp:     handler.ArticleResponse{ID: 1, Title: "Test title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test author"}},
 			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
 		},
 		{
 			name: "error : database error",
 			mockDB: func() *handler.Application {
 				articleMock := mocks.NewArticleStore(t)
-				articleMock.EXPECT().GetAll().Return(nil, errors.New("error fetching all articles"))
+				articleMock.EXPECT().List(mock.Anything, mock.Anything).Return(nil, errors.New("error fetching all articles"))
 
 				m := models.Models{
 					Article: articleMock,
@@ -291,43 +373,964 @@ func Test_GetArticles(t *testing.T) {
 	}
 }
 
-// callEndpoint creates a request and make a http call
-func callEndpoint(t *testing.T, req *handler.ArticleRequest, handlerFunc http.HandlerFunc, urlParams map[string]string) (*response.Body, error) {
-	w := httptest.NewRecorder()
+func Test_UpdateArticle(t *testing.T) {
+	createdAt := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2023, 1, 2, 15, 30, 0, 0, time.FixedZone("IST", 19800))
+	tests := []struct {
+		name         string
+		req          handler.ArticleRequest
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantResp     handler.ArticleResponse
+		wantRespBody response.Body
+	}{
+		{
+			name:      "success",
+			req:       handler.ArticleRequest{Title: "New title", Content: "New content", Author: "New author"},
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Update(mock.Anything, &models.Article{ID: 1, Title: "New title", Content: "New content", Author: "New author", CreateAuthor: true}).Return(nil)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, Title: "New title", Content: "New content", Author: "New author", CreatedAt: createdAt, UpdatedAt: updatedAt}, nil)
 
-	rawReq, _ := json.Marshal(req)
+				m := models.Models{
+					Article: articleMock,
+				}
 
-	// create a request
-	r, err := http.NewRequest(mock.Anything, "/articles", bytes.NewBuffer(rawReq))
-	if err != nil {
-		t.Fatal(err)
-	}
+				return handler.New(&m)
+			},
+			wantResp:     handler.ArticleResponse{ID: 1, Title: "New title", Content: "New content", Author: &handler.AuthorResponse{Name: "New author"}, CreatedAt: "2023-01-01T10:00:00Z", UpdatedAt: "2023-01-02T10:00:00Z"},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "validation error",
+			req:       handler.ArticleRequest{Title: "New title", Content: " ", Author: "New author"},
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "Field validation for 'Content' failed on the 'required' tag"},
+		},
+		{
+			name:      "error : article id not found",
+			req:       handler.ArticleRequest{Title: "New title", Content: "New content", Author: "New author"},
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Update(mock.Anything, mock.Anything).Return(models.ErrArticleNotFound)
 
-	// appends a urlParams at the end of route
-	r = setURLParams(r, urlParams)
+				m := models.Models{
+					Article: articleMock,
+				}
 
-	// server http call
-	handlerFunc.ServeHTTP(w, r)
+				return handler.New(&m)
+			},
+			wantRespBody: response.Body{Status: http.StatusNotFound, Message: "article not found"},
+		},
+		{
+			name:      "error : database error",
+			req:       handler.ArticleRequest{Title: "New title", Content: "New content", Author: "New author"},
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Update(mock.Anything, mock.Anything).Return(errors.New("db error"))
 
-	resp := response.Body{}
-	err = json.Unmarshal(w.Body.Bytes(), &resp)
-	if err != nil {
-		t.Errorf("error unmarshalling response : %v", err)
+				m := models.Models{
+					Article: articleMock,
+				}
+
+				return handler.New(&m)
+			},
+			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error updating article"},
+		},
+		{
+			name:      "error : store validation",
+			req:       handler.ArticleRequest{Title: "New title", Content: "New content", Author: "New author"},
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Update(mock.Anything, mock.Anything).Return(&models.Error{Kind: models.ErrValidation, Message: "author must be at most 255 characters"})
+
+				m := models.Models{
+					Article: articleMock,
+				}
+
+				return handler.New(&m)
+			},
+			wantRespBody: response.Body{Status: http.StatusUnprocessableEntity, Message: "author must be at most 255 characters"},
+		},
+		{
+			name:      "error : store conflict",
+			req:       handler.ArticleRequest{Title: "New title", Content: "New content", Author: "New author"},
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Update(mock.Anything, mock.Anything).Return(&models.Error{Kind: models.ErrConflict, Message: "record already exists"})
+
+				m := models.Models{
+					Article: articleMock,
+				}
+
+				return handler.New(&m)
+			},
+			wantRespBody: response.Body{Status: http.StatusConflict, Message: "record already exists"},
+		},
 	}
 
-	return &resp, nil
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// mock database calls
+			app := tt.mockDB()
+
+			handlerFunc := app.UpdateArticle()
+			resp, err := callEndpoint(t, &tt.req, handlerFunc, tt.urlParams)
+			if err != nil {
+				t.Errorf("error in call endpoint : %v", err)
+			}
+
+			// convert response data into struct
+			var gotResp handler.ArticleResponse
+			aa, err := json.Marshal(resp.Data)
+			if err != nil {
+				t.Error("error marshalling response data to bytes", err)
+			}
+
+			err = json.Unmarshal(aa, &gotResp)
+			if err != nil {
+				t.Error("error unmarshalling response data", err)
+			}
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+			if resp.Message == "Success" {
+				assert.Equal(t, gotResp, tt.wantResp)
+			}
+		})
+	}
 }
 
-// setURLParams appends a urlParams at the end of route
-func setURLParams(req *http.Request, urlParams map[string]string) *http.Request {
-	if len(urlParams) > 0 {
-		routeContext := chi.NewRouteContext()
-		for k, v := range urlParams {
-			routeContext.URLParams.Add(k, v)
-		}
+func Test_PatchArticle(t *testing.T) {
+	title := "New title"
+	updatedAt := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
 
-		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+	tests := []struct {
+		name         string
+		req          map[string]interface{}
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantResp     handler.ArticleResponse
+		wantRespBody response.Body
+	}{
+		{
+			name:      "success",
+			req:       map[string]interface{}{"title": " New title "},
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Patch(mock.Anything, 1, &models.ArticlePatch{Title: &title, CreateAuthor: true}).Return(nil)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, Title: "New title", Content: "Test content", Author: "Test author", UpdatedAt: updatedAt}, nil)
+
+				m := models.Models{
+					Article: articleMock,
+				}
+
+				return handler.New(&m)
+			},
+			wantResp:     handler.ArticleResponse{ID: 1, Title: "New title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test author"}, UpdatedAt: "2023-01-02T10:00:00Z"},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "validation error : empty field",
+			req:       map[string]interface{}{"author": " "},
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "Field validation for 'Author' failed on the 'min' tag"},
+		},
+		{
+			name:      "validation error : null field",
+			req:       map[string]interface{}{"content": nil},
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "field 'content' cannot be removed"},
+		},
+		{
+			name:      "error : article id not found",
+			req:       map[string]interface{}{"title": "New title"},
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Patch(mock.Anything, 1, mock.Anything).Return(models.ErrArticleNotFound)
+
+				m := models.Models{
+					Article: articleMock,
+				}
+
+				return handler.New(&m)
+			},
+			wantRespBody: response.Body{Status: http.StatusNotFound, Message: "article not found"},
+		},
+		{
+			name:      "error : database error",
+			req:       map[string]interface{}{"title": "New title"},
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Patch(mock.Anything, 1, mock.Anything).Return(errors.New("db error"))
+
+				m := models.Models{
+					Article: articleMock,
+				}
+
+				return handler.New(&m)
+			},
+			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error updating article"},
+		},
 	}
 
-	return req
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// mock database calls
+			app := tt.mockDB()
+
+			handlerFunc := app.PatchArticle()
+			resp, err := callEndpoint(t, tt.req, handlerFunc, tt.urlParams)
+			if err != nil {
+				t.Errorf("error in call endpoint : %v", err)
+			}
+
+			// convert response data into struct
+			var gotResp handler.ArticleResponse
+			aa, err := json.Marshal(resp.Data)
+			if err != nil {
+				t.Error("error marshalling response data to bytes", err)
+			}
+
+			err = json.Unmarshal(aa, &gotResp)
+			if err != nil {
+				t.Error("error unmarshalling response data", err)
+			}
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+			if resp.Message == "Success" {
+				assert.Equal(t, gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_DeleteArticle(t *testing.T) {
+	tests := []struct {
+		name         string
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody response.Body
+	}{
+		{
+			name:      "success",
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Delete(mock.Anything, 1).Return(nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error : article id not found",
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Delete(mock.Anything, 1).Return(models.ErrArticleNotFound)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusNotFound, Message: "article not found"},
+		},
+		{
+			name:      "error : database error",
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Delete(mock.Anything, 1).Return(errors.New("db error"))
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error deleting article"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// mock database calls
+			app := tt.mockDB()
+
+			resp, err := callEndpoint(t, nil, app.DeleteArticle(), tt.urlParams)
+			if err != nil {
+				t.Errorf("error in call endpoint : %v", err)
+			}
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+		})
+	}
+}
+
+func Test_RestoreArticle(t *testing.T) {
+	tests := []struct {
+		name         string
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantResp     handler.ArticleResponse
+		wantRespBody response.Body
+	}{
+		{
+			name:      "success",
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Restore(mock.Anything, 1).Return(nil)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, Title: "Test title"}, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantResp:     handler.ArticleResponse{ID: 1, Title: "Test title"},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error : article id not found",
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Restore(mock.Anything, 1).Return(models.ErrArticleNotFound)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusNotFound, Message: "article not found"},
+		},
+		{
+			name:      "error : article not deleted",
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Restore(mock.Anything, 1).Return(models.ErrArticleNotDeleted)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusConflict, Message: "article is not deleted"},
+		},
+		{
+			name:      "error : database error",
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Restore(mock.Anything, 1).Return(errors.New("db error"))
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error restoring article"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// mock database calls
+			app := tt.mockDB()
+
+			resp, err := callEndpoint(t, nil, app.RestoreArticle(), tt.urlParams)
+			if err != nil {
+				t.Errorf("error in call endpoint : %v", err)
+			}
+
+			// convert response data into struct
+			var gotResp handler.ArticleResponse
+			aa, err := json.Marshal(resp.Data)
+			if err != nil {
+				t.Error("error marshalling response data to bytes", err)
+			}
+
+			err = json.Unmarshal(aa, &gotResp)
+			if err != nil {
+				t.Error("error unmarshalling response data", err)
+			}
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+			if resp.Message == "Success" {
+				assert.Equal(t, gotResp, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_PurgeArticle(t *testing.T) {
+	admin := &auth.Principal{Subject: "u-1", Role: auth.RoleAdmin}
+
+	tests := []struct {
+		name         string
+		principal    *auth.Principal
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody response.Body
+	}{
+		{
+			name:      "success",
+			principal: admin,
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Purge(mock.Anything, 1).Return(nil)
+
+				return handler.NewWithConfig(&models.Models{Article: articleMock}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error : not admin",
+			principal: &auth.Principal{Subject: "u-9", Role: auth.RoleEditor},
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				return handler.NewWithConfig(&models.Models{}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "admin access required"},
+		},
+		{
+			name:      "error : database error",
+			principal: admin,
+			urlParams: map[string]string{"article_id": "1"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Purge(mock.Anything, 1).Return(errors.New("db error"))
+
+				return handler.NewWithConfig(&models.Models{Article: articleMock}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error purging article"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// mock database calls
+			app := tt.mockDB()
+
+			r := httptest.NewRequest(http.MethodPost, "/articles/1/purge", nil)
+			r = r.WithContext(auth.WithPrincipal(r.Context(), tt.principal))
+
+			resp := serveRequest(t, r, app.PurgeArticle(), tt.urlParams)
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+		})
+	}
+}
+
+func Test_GetArticles_IncludeDeleted(t *testing.T) {
+	admin := &auth.Principal{Subject: "u-1", Role: auth.RoleAdmin}
+
+	tests := []struct {
+		name         string
+		principal    *auth.Principal
+		adminToken   string
+		query        string
+		mockDB       func() *handler.Application
+		wantRespBody response.Body
+	}{
+		{
+			name:      "success",
+			principal: admin,
+			query:     "include_deleted=true",
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, models.ListOptions{IncludeDeleted: true, Filter: publishedOnly, Page: models.Page{Limit: 21}}).Return([]*models.Article{{ID: 1, Deleted: true}}, nil)
+				articleMock.EXPECT().Count(mock.Anything, models.ListOptions{IncludeDeleted: true, Filter: publishedOnly, Page: models.Page{Limit: 21}}).Return(1, nil)
+
+				return handler.NewWithConfig(&models.Models{Article: articleMock}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:  "error : not admin",
+			query: "include_deleted=true",
+			mockDB: func() *handler.Application {
+				return handler.NewWithConfig(&models.Models{}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "admin access required"},
+		},
+		{
+			name:       "error : admin token outside key management",
+			adminToken: "secret",
+			query:      "include_deleted=true",
+			mockDB: func() *handler.Application {
+				return handler.NewWithConfig(&models.Models{}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "admin access required"},
+		},
+		{
+			name:      "error : invalid value",
+			principal: admin,
+			query:     "include_deleted=maybe",
+			mockDB: func() *handler.Application {
+				return handler.NewWithConfig(&models.Models{}, adminConfig())
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid include_deleted value"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// mock database calls
+			app := tt.mockDB()
+
+			r := httptest.NewRequest(http.MethodGet, "/articles?"+tt.query, nil)
+			r.Header.Set("X-Admin-Token", tt.adminToken)
+
+			if tt.principal != nil {
+				r = r.WithContext(auth.WithPrincipal(r.Context(), tt.principal))
+			}
+
+			resp := serveRequest(t, r, app.GetArticles(), nil)
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+		})
+	}
+}
+
+func Test_GetArticles_ExpandAuthor(t *testing.T) {
+	articles := []*models.Article{
+		{ID: 1, Title: "First title", Author: "Jane", AuthorID: 1},
+		{ID: 2, Title: "Second title", Author: "John", AuthorID: 2},
+		{ID: 3, Title: "Third title", Author: "Jane", AuthorID: 1},
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		mockDB       func() *handler.Application
+		wantAuthors  []*handler.AuthorResponse
+		wantRespBody response.Body
+	}{
+		{
+			name: "success - compact authors",
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, mock.Anything).Return(articles, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(3, nil)
+
+				return handler.New(&models.Models{Article: articleMock, Author: mocks.NewAuthorStore(t)})
+			},
+			wantAuthors: []*handler.AuthorResponse{
+				{ID: 1, Name: "Jane"},
+				{ID: 2, Name: "John"},
+				{ID: 1, Name: "Jane"},
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:  "success - authors are fetched once",
+			query: "expand=author",
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, mock.Anything).Return(articles, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(3, nil)
+
+				authorMock := mocks.NewAuthorStore(t)
+				authorMock.EXPECT().GetByIDs(mock.Anything, []int{1, 2}).Return([]*models.Author{
+					{ID: 1, Name: "Jane", Bio: "Writes about Go"},
+					{ID: 2, Name: "John", AvatarURL: "https://example.com/john.png"},
+				}, nil)
+
+				return handler.New(&models.Models{Article: articleMock, Author: authorMock})
+			},
+			wantAuthors: []*handler.AuthorResponse{
+				{ID: 1, Name: "Jane", Bio: "Writes about Go"},
+				{ID: 2, Name: "John", AvatarURL: "https://example.com/john.png"},
+				{ID: 1, Name: "Jane", Bio: "Writes about Go"},
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:  "error : unknown expand field",
+			query: "expand=author,owner",
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{Article: mocks.NewArticleStore(t)})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid query parameters"},
+		},
+		{
+			name:  "error : database error",
+			query: "expand=author",
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, mock.Anything).Return(articles, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(3, nil)
+
+				authorMock := mocks.NewAuthorStore(t)
+				authorMock.EXPECT().GetByIDs(mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+				return handler.New(&models.Models{Article: articleMock, Author: authorMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error fetching authors of articles"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			r := httptest.NewRequest(http.MethodGet, "/articles?"+tt.query, nil)
+
+			resp := serveRequest(t, r, app.GetArticles(), nil)
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+
+			if tt.wantAuthors != nil {
+				data, err := json.Marshal(resp.Data)
+				assert.NoError(t, err)
+
+				var got []handler.ArticleResponse
+				assert.NoError(t, json.Unmarshal(data, &got))
+
+				var authors []*handler.AuthorResponse
+				for _, a := range got {
+					authors = append(authors, a.Author)
+				}
+
+				assert.Equal(t, authors, tt.wantAuthors)
+			}
+		})
+	}
+}
+
+func Test_ArticleOwnership(t *testing.T) {
+	author := &auth.Principal{Subject: "u-1", Name: "Jane", Role: auth.RoleAuthor}
+	editor := &auth.Principal{Subject: "u-9", Name: "Ed", Role: auth.RoleEditor}
+	reader := &auth.Principal{Subject: "u-5", Name: "Ray", Role: auth.RoleReader}
+
+	body := handler.ArticleRequest{Title: "Test title", Content: "Test content", Author: "Jane"}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		handler   func(app *handler.Application) http.HandlerFunc
+
+		// body replaces the default body when set
+		body         interface{}
+		mockDB       func() *handler.Application
+		wantRespBody response.Body
+	}{
+		{
+			name:      "success - author cannot attribute own article to another author",
+			principal: author,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.UpdateArticle() },
+			body:      handler.ArticleRequest{Title: "Test title", Content: "Test content", Author: "Mallory"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, OwnerID: "u-1"}, nil)
+				articleMock.EXPECT().Update(mock.Anything, &models.Article{ID: 1, Title: "Test title", Content: "Test content", Author: "Jane", AuthorSubject: "u-1"}).Return(nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error : author name of another user",
+			principal: author,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.UpdateArticle() },
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, OwnerID: "u-1"}, nil)
+				articleMock.EXPECT().Update(mock.Anything, &models.Article{ID: 1, Title: "Test title", Content: "Test content", Author: "Jane", AuthorSubject: "u-1"}).Return(models.ErrAuthorTaken)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusConflict, Message: "author name belongs to another user"},
+		},
+		{
+			name:      "success - editor changes author of any article",
+			principal: editor,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.UpdateArticle() },
+			body:      handler.ArticleRequest{Title: "Test title", Content: "Test content", Author: "Mallory"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Update(mock.Anything, &models.Article{ID: 1, Title: "Test title", Content: "Test content", Author: "Mallory", CreateAuthor: true}).Return(nil)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1}, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error : author patches author of own article",
+			principal: author,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.PatchArticle() },
+			body:      map[string]string{"author": "Mallory"},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, OwnerID: "u-1"}, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "author may not manage authors"},
+		},
+		{
+			name:      "success - author patches title of own article",
+			principal: author,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.PatchArticle() },
+			body:      map[string]string{"title": "New title"},
+			mockDB: func() *handler.Application {
+				title := "New title"
+
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, OwnerID: "u-1"}, nil)
+				articleMock.EXPECT().Patch(mock.Anything, 1, &models.ArticlePatch{Title: &title}).Return(nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "success - author updates own article",
+			principal: author,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.UpdateArticle() },
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, OwnerID: "u-1"}, nil)
+				articleMock.EXPECT().Update(mock.Anything, mock.Anything).Return(nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error : author updates article of another author",
+			principal: author,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.UpdateArticle() },
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, OwnerID: "u-2"}, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "author may only update articles they own"},
+		},
+		{
+			name:      "error : author patches missing article",
+			principal: author,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.PatchArticle() },
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(nil, models.ErrArticleNotFound)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusNotFound, Message: "article not found"},
+		},
+		{
+			name:      "success - author deletes own article",
+			principal: author,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.DeleteArticle() },
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, OwnerID: "u-1"}, nil)
+				articleMock.EXPECT().Delete(mock.Anything, 1).Return(nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error : author deletes article without owner",
+			principal: author,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.DeleteArticle() },
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1}, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "author may only delete articles they own"},
+		},
+		{
+			name:      "error : author restores article",
+			principal: author,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.RestoreArticle() },
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "author may not restore articles"},
+		},
+		{
+			name:      "success - editor deletes any article without fetching it",
+			principal: editor,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.DeleteArticle() },
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Delete(mock.Anything, 1).Return(nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error : reader creates article",
+			principal: reader,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.CreateArticle() },
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "reader may not create articles"},
+		},
+		{
+			name:      "error : editor purges article",
+			principal: editor,
+			handler:   func(app *handler.Application) http.HandlerFunc { return app.PurgeArticle() },
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "admin access required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// mock database calls
+			app := tt.mockDB()
+
+			var req interface{} = body
+			if tt.body != nil {
+				req = tt.body
+			}
+
+			rawReq, _ := json.Marshal(req)
+
+			r := httptest.NewRequest(http.MethodPut, "/articles/1", bytes.NewBuffer(rawReq))
+			r = r.WithContext(auth.WithPrincipal(r.Context(), tt.principal))
+
+			resp := serveRequest(t, r, tt.handler(app), map[string]string{"article_id": "1"})
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+		})
+	}
+}
+
+// callEndpoint creates a request and make a http call
+func Test_ValidationProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		handler    func(app *handler.Application) http.HandlerFunc
+		wantErrors []response.FieldError
+	}{
+		{
+			name:    "create",
+			body:    `{"title": " ", "content": "Test content"}`,
+			handler: func(app *handler.Application) http.HandlerFunc { return app.CreateArticle() },
+			// editors name the author
+			wantErrors: []response.FieldError{
+				{Field: "title", Rule: "required", Message: "must not be empty"},
+				{Field: "author", Rule: "required", Message: "must not be empty"},
+			},
+		},
+		{
+			name:    "patch",
+			body:    `{"author": " "}`,
+			handler: func(app *handler.Application) http.HandlerFunc { return app.PatchArticle() },
+			wantErrors: []response.FieldError{
+				{Field: "author", Rule: "min", Param: "1", Message: "must not be empty"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := handler.New(&models.Models{})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/articles/1", bytes.NewBufferString(tt.body))
+			r.Header.Set("Accept", response.ProblemContentType)
+			r = r.WithContext(auth.WithPrincipal(r.Context(), &auth.Principal{Subject: "u-1", Name: "Jane", Role: auth.RoleEditor}))
+			r = setURLParams(r, map[string]string{"article_id": "1"})
+
+			response.Negotiate(tt.handler(app)).ServeHTTP(w, r)
+
+			var gotProblem response.Problem
+			err := json.Unmarshal(w.Body.Bytes(), &gotProblem)
+			if err != nil {
+				t.Errorf("error unmarshalling response : %v", err)
+			}
+
+			assert.Equal(t, w.Code, http.StatusBadRequest)
+			assert.Equal(t, w.Header().Get("Content-Type"), response.ProblemContentType)
+			assert.Equal(t, gotProblem.Code, response.CodeValidationFailed)
+			assert.Equal(t, gotProblem.Instance, "/articles/1")
+			assert.Equal(t, gotProblem.Errors, tt.wantErrors)
+		})
+	}
+}
+
+// publishedOnly is the filter of lists without status query params
+var publishedOnly = models.ArticleFilter{Statuses: []models.Status{models.StatusPublished}}
+
+// callEndpoint calls handlerFunc as an editor, who may change any article
+func callEndpoint(t *testing.T, req interface{}, handlerFunc http.HandlerFunc, urlParams map[string]string) (*response.Body, error) {
+	rawReq, _ := json.Marshal(req)
+
+	// create a request
+	r, err := http.NewRequest(mock.Anything, "/articles", bytes.NewBuffer(rawReq))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r = r.WithContext(auth.WithPrincipal(r.Context(), &auth.Principal{Subject: "u-9", Name: "Ed", Role: auth.RoleEditor}))
+
+	return serveRequest(t, r, handlerFunc, urlParams), nil
+}
+
+// serveRequest serves given request and decodes response body
+func serveRequest(t *testing.T, r *http.Request, handlerFunc http.HandlerFunc, urlParams map[string]string) *response.Body {
+	w := httptest.NewRecorder()
+
+	// appends a urlParams at the end of route
+	r = setURLParams(r, urlParams)
+
+	// server http call
+	handlerFunc.ServeHTTP(w, r)
+
+	resp := response.Body{}
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	if err != nil {
+		t.Errorf("error unmarshalling response : %v", err)
+	}
+
+	return &resp
+}
+
+// setURLParams appends a urlParams at the end of route
+func setURLParams(req *http.Request, urlParams map[string]string) *http.Request {
+	if len(urlParams) > 0 {
+		routeContext := chi.NewRouteContext()
+		for k, v := range urlParams {
+			routeContext.URLParams.Add(k, v)
+		}
+
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+	}
+
+	return req
+}
+
+// adminConfig returns config accepting secret as admin token
+func adminConfig() config.Config {
+	cfg := config.Default()
+	cfg.Server.AdminToken = "secret"
+
+	return cfg
 }
@@ -0,0 +1,349 @@
+package handler
+
+import (
+	"article/internal/logging"
+	"article/internal/models"
+	"article/internal/policy"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+)
+
+// AuthorRequest used in create and update author requests
+type AuthorRequest struct {
+	Name      string `json:"name" validate:"required,max=255"`
+	Bio       string `json:"bio"`
+	AvatarURL string `json:"avatar_url" validate:"omitempty,url,max=2048"`
+}
+
+// AuthorResponse used in author responses, articles embed the compact form holding id and name
+type AuthorResponse struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Bio       string `json:"bio,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+
+	// CreatedAt and UpdatedAt are RFC 3339 timestamps in UTC
+	CreatedAt string `json:"created_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// CreateAuthor stores an author, names are unique ignoring case
+func (app *Application) CreateAuthor() http.HandlerFunc {
+	return app.traced("CreateAuthor", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.ManageAuthors, policy.Resource{}) {
+			return
+		}
+
+		var req AuthorRequest
+
+		err := app.validateAuthorRequest(w, r, &req)
+		if err != nil {
+			return
+		}
+
+		author := &models.Author{Name: req.Name, Bio: req.Bio, AvatarURL: req.AvatarURL}
+
+		_, err = app.models.Author.Create(ctx, author)
+		if err != nil {
+			app.storeError(ctx, w, err, "error creating author")
+
+			return
+		}
+
+		app.log(r).Info("author created", slog.Int("author_id", author.ID))
+
+		// fetch author with its timestamps
+		author, err = app.findAuthor(ctx, w, author.ID)
+		if err != nil {
+			return
+		}
+
+		app.response.Created(w, newAuthorResponse(author))
+	})
+}
+
+// GetAuthor fetches an author using authorID
+func (app *Application) GetAuthor() http.HandlerFunc {
+	return app.traced("GetAuthor", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.ReadAuthor, policy.Resource{}) {
+			return
+		}
+
+		id, err := app.authorID(w, r)
+		if err != nil {
+			return
+		}
+
+		author, err := app.findAuthor(ctx, w, id)
+		if err != nil {
+			return
+		}
+
+		app.response.Success(w, newAuthorResponse(author))
+	})
+}
+
+// GetAuthors fetches a page of authors ordered by id
+func (app *Application) GetAuthors() http.HandlerFunc {
+	return app.traced("GetAuthors", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.ReadAuthor, policy.Resource{}) {
+			return
+		}
+
+		page, err := app.page(w, r)
+		if err != nil {
+			return
+		}
+
+		// fetch one extra author to find out whether another page exists
+		limit := page.Limit
+		page.Limit++
+
+		authors, err := app.models.Author.List(ctx, page)
+		if err != nil {
+			app.storeError(ctx, w, err, "error fetching authors")
+
+			return
+		}
+
+		total, err := app.models.Author.Count(ctx)
+		if err != nil {
+			app.storeError(ctx, w, err, "error counting authors")
+
+			return
+		}
+
+		authors, pagination := paginate(page, authors, limit, total, models.NewAuthorCursor)
+
+		resp := []AuthorResponse{}
+		for _, author := range authors {
+			resp = append(resp, *newAuthorResponse(author))
+		}
+
+		w.Header().Set("Link", pageLinks(r, pagination))
+
+		app.response.Paginated(w, resp, pagination)
+	})
+}
+
+// GetAuthorArticles fetches a page of articles of an author, filter and sort query params
+// are those of GetArticles
+func (app *Application) GetAuthorArticles() http.HandlerFunc {
+	return app.traced("GetAuthorArticles", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.ReadArticle, policy.Resource{}) {
+			return
+		}
+
+		id, err := app.authorID(w, r)
+		if err != nil {
+			return
+		}
+
+		opts, err := app.listOptions(w, r)
+		if err != nil {
+			return
+		}
+
+		expand, err := app.expandAuthor(w, r)
+		if err != nil {
+			return
+		}
+
+		// an unknown author is 404 rather than an empty page
+		author, err := app.findAuthor(ctx, w, id)
+		if err != nil {
+			return
+		}
+
+		opts.Filter.AuthorID = author.ID
+
+		// fetch one extra article to find out whether another page exists
+		limit := opts.Limit
+		opts.Limit++
+
+		articles, err := app.models.Article.List(ctx, *opts)
+		if err != nil {
+			app.storeError(ctx, w, err, "error fetching articles of author")
+
+			return
+		}
+
+		total, err := app.models.Article.Count(ctx, *opts)
+		if err != nil {
+			app.storeError(ctx, w, err, "error counting articles of author")
+
+			return
+		}
+
+		articles, page := paginate(opts.Page, articles, limit, total, models.NewCursor)
+
+		resp := []ArticleResponse{}
+		for _, val := range articles {
+			a := newArticleResponse(val)
+
+			// the author is already fetched
+			if expand {
+				a.Author = newAuthorResponse(author)
+			}
+
+			resp = append(resp, a)
+		}
+
+		w.Header().Set("Link", pageLinks(r, page))
+
+		app.response.Paginated(w, resp, page)
+	})
+}
+
+// UpdateAuthor replaces an author with given details, its articles take the new name
+func (app *Application) UpdateAuthor() http.HandlerFunc {
+	return app.traced("UpdateAuthor", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.ManageAuthors, policy.Resource{}) {
+			return
+		}
+
+		id, err := app.authorID(w, r)
+		if err != nil {
+			return
+		}
+
+		var req AuthorRequest
+
+		err = app.validateAuthorRequest(w, r, &req)
+		if err != nil {
+			return
+		}
+
+		err = app.models.Author.Update(ctx, &models.Author{ID: id, Name: req.Name, Bio: req.Bio, AvatarURL: req.AvatarURL})
+		if err != nil {
+			app.storeError(ctx, w, err, "error updating author")
+
+			return
+		}
+
+		// fetch updated author with its new updated_at
+		author, err := app.findAuthor(ctx, w, id)
+		if err != nil {
+			return
+		}
+
+		app.response.Success(w, newAuthorResponse(author))
+	})
+}
+
+// DeleteAuthor removes an author, authors of articles cannot be deleted until the articles are purged
+func (app *Application) DeleteAuthor() http.HandlerFunc {
+	return app.traced("DeleteAuthor", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		if !app.authorize(w, r, policy.ManageAuthors, policy.Resource{}) {
+			return
+		}
+
+		id, err := app.authorID(w, r)
+		if err != nil {
+			return
+		}
+
+		err = app.models.Author.Delete(ctx, id)
+		if err != nil {
+			app.storeError(ctx, w, err, "error deleting author")
+
+			return
+		}
+
+		app.log(r).Info("author deleted", slog.Int("author_id", id))
+
+		app.response.Success(w, nil)
+	})
+}
+
+// authorID fetches authorID from url params
+func (app *Application) authorID(w http.ResponseWriter, r *http.Request) (int, error) {
+	authorID := chi.URLParam(r, "author_id")
+
+	id, err := strconv.Atoi(authorID)
+	if err != nil || id < 1 {
+		app.log(r).Info("invalid author id", slog.String("author_id", authorID))
+		app.response.BadRequest(w, "invalid author id")
+
+		return 0, errors.New("invalid author id")
+	}
+
+	return id, nil
+}
+
+// findAuthor fetches an author and responds 404 when it does not exist
+func (app *Application) findAuthor(ctx context.Context, w http.ResponseWriter, id int) (*models.Author, error) {
+	author, err := app.models.Author.GetByID(ctx, id)
+	if err != nil {
+		app.storeError(ctx, w, err, "error fetching author by authorID")
+
+		return nil, err
+	}
+
+	return author, nil
+}
+
+// validateAuthorRequest decodes and validates author request body
+func (app *Application) validateAuthorRequest(w http.ResponseWriter, r *http.Request, req *AuthorRequest) error {
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		app.log(r).Info("error decoding request body", logging.Err(err))
+		app.response.BadRequest(w, "invalid request")
+
+		return err
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.Bio = strings.TrimSpace(req.Bio)
+	req.AvatarURL = strings.TrimSpace(req.AvatarURL)
+
+	err = app.validate.Struct(req)
+	if err != nil {
+		app.log(r).Info("error validating request", logging.Err(err))
+
+		msg, errs := validationErrors(req, err.(validator.ValidationErrors))
+		app.response.ValidationFailed(w, msg, errs)
+
+		return err
+	}
+
+	return nil
+}
+
+// newAuthorResponse prepares response from author model
+func newAuthorResponse(author *models.Author) *AuthorResponse {
+	return &AuthorResponse{
+		ID:        author.ID,
+		Name:      author.Name,
+		Bio:       author.Bio,
+		AvatarURL: author.AvatarURL,
+		CreatedAt: formatTime(author.CreatedAt),
+		UpdatedAt: formatTime(author.UpdatedAt),
+	}
+}
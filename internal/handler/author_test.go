@@ -0,0 +1,279 @@
+package handler_test
+
+import (
+	"article/internal/auth"
+	"article/internal/handler"
+	"article/internal/models"
+	"article/internal/response"
+	"article/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateAuthor(t *testing.T) {
+	createdAt := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	editor := &auth.Principal{Subject: "u-9", Name: "Ed", Role: auth.RoleEditor}
+
+	tests := []struct {
+		name         string
+		principal    *auth.Principal
+		body         string
+		mockDB       func() *handler.Application
+		wantResp     *handler.AuthorResponse
+		wantRespBody response.Body
+	}{
+		{
+			name:      "success",
+			principal: editor,
+			body:      `{"name":" Jane Doe ","bio":"Writes about Go","avatar_url":"https://example.com/jane.png"}`,
+			mockDB: func() *handler.Application {
+				authorMock := mocks.NewAuthorStore(t)
+				authorMock.EXPECT().Create(mock.Anything, &models.Author{Name: "Jane Doe", Bio: "Writes about Go", AvatarURL: "https://example.com/jane.png"}).
+					RunAndReturn(func(_ context.Context, author *models.Author) (int64, error) {
+						author.ID = 1
+
+						return 1, nil
+					})
+				authorMock.EXPECT().GetByID(mock.Anything, 1).Return(&models.Author{
+					ID: 1, Name: "Jane Doe", Bio: "Writes about Go", AvatarURL: "https://example.com/jane.png", CreatedAt: createdAt, UpdatedAt: createdAt,
+				}, nil)
+
+				return handler.New(&models.Models{Author: authorMock})
+			},
+			wantResp: &handler.AuthorResponse{
+				ID: 1, Name: "Jane Doe", Bio: "Writes about Go", AvatarURL: "https://example.com/jane.png",
+				CreatedAt: "2023-01-01T10:00:00Z", UpdatedAt: "2023-01-01T10:00:00Z",
+			},
+			wantRespBody: response.Body{Status: http.StatusCreated, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error : author role",
+			principal: &auth.Principal{Subject: "u-1", Name: "Jane", Role: auth.RoleAuthor},
+			body:      `{"name":"Jane Doe"}`,
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{Author: mocks.NewAuthorStore(t)})
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "author may not manage authors"},
+		},
+		{
+			name:      "error : invalid avatar url",
+			principal: editor,
+			body:      `{"name":"Jane Doe","avatar_url":"jane.png"}`,
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{Author: mocks.NewAuthorStore(t)})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "Field validation for 'AvatarURL' failed on the 'url' tag"},
+		},
+		{
+			name:      "error : name taken",
+			principal: editor,
+			body:      `{"name":"jane doe"}`,
+			mockDB: func() *handler.Application {
+				authorMock := mocks.NewAuthorStore(t)
+				authorMock.EXPECT().Create(mock.Anything, mock.Anything).Return(0, models.ErrConflict)
+
+				return handler.New(&models.Models{Author: authorMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusConflict, Message: "conflict"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			r := httptest.NewRequest(http.MethodPost, "/authors", strings.NewReader(tt.body))
+			r = r.WithContext(auth.WithPrincipal(r.Context(), tt.principal))
+
+			resp := serveRequest(t, r, app.CreateAuthor(), nil)
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+
+			if tt.wantResp != nil {
+				data, err := json.Marshal(resp.Data)
+				require.NoError(t, err)
+
+				var got handler.AuthorResponse
+				require.NoError(t, json.Unmarshal(data, &got))
+				assert.Equal(t, &got, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_DeleteAuthor(t *testing.T) {
+	tests := []struct {
+		name         string
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody response.Body
+	}{
+		{
+			name:      "success",
+			urlParams: map[string]string{"author_id": "1"},
+			mockDB: func() *handler.Application {
+				authorMock := mocks.NewAuthorStore(t)
+				authorMock.EXPECT().Delete(mock.Anything, 1).Return(nil)
+
+				return handler.New(&models.Models{Author: authorMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error : invalid author id",
+			urlParams: map[string]string{"author_id": "0"},
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{Author: mocks.NewAuthorStore(t)})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid author id"},
+		},
+		{
+			name:      "error : author has articles",
+			urlParams: map[string]string{"author_id": "1"},
+			mockDB: func() *handler.Application {
+				authorMock := mocks.NewAuthorStore(t)
+				authorMock.EXPECT().Delete(mock.Anything, 1).Return(models.ErrAuthorHasArticles)
+
+				return handler.New(&models.Models{Author: authorMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusConflict, Message: "author has articles"},
+		},
+		{
+			name:      "error : author not found",
+			urlParams: map[string]string{"author_id": "2"},
+			mockDB: func() *handler.Application {
+				authorMock := mocks.NewAuthorStore(t)
+				authorMock.EXPECT().Delete(mock.Anything, 2).Return(models.ErrAuthorNotFound)
+
+				return handler.New(&models.Models{Author: authorMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusNotFound, Message: "author not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			resp, err := callEndpoint(t, nil, app.DeleteAuthor(), tt.urlParams)
+			require.NoError(t, err)
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+		})
+	}
+}
+
+func Test_GetAuthorArticles(t *testing.T) {
+	jane := &models.Author{ID: 1, Name: "Jane Doe", Bio: "Writes about Go"}
+
+	tests := []struct {
+		name         string
+		query        string
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantAuthor   *handler.AuthorResponse
+		wantRespBody response.Body
+	}{
+		{
+			name:      "success",
+			urlParams: map[string]string{"author_id": "1"},
+			mockDB: func() *handler.Application {
+				authorMock := mocks.NewAuthorStore(t)
+				authorMock.EXPECT().GetByID(mock.Anything, 1).Return(jane, nil)
+
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, mock.MatchedBy(func(opts models.ListOptions) bool {
+					return opts.Filter.AuthorID == 1
+				})).Return([]*models.Article{{ID: 3, Title: "Test title", Author: "Jane Doe", AuthorID: 1}}, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(1, nil)
+
+				return handler.New(&models.Models{Article: articleMock, Author: authorMock})
+			},
+			wantAuthor:   &handler.AuthorResponse{ID: 1, Name: "Jane Doe"},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "success - expand author",
+			query:     "?expand=author",
+			urlParams: map[string]string{"author_id": "1"},
+			mockDB: func() *handler.Application {
+				authorMock := mocks.NewAuthorStore(t)
+				authorMock.EXPECT().GetByID(mock.Anything, 1).Return(jane, nil)
+
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, mock.Anything).Return([]*models.Article{{ID: 3, Title: "Test title", Author: "Jane Doe", AuthorID: 1}}, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(1, nil)
+
+				return handler.New(&models.Models{Article: articleMock, Author: authorMock})
+			},
+			wantAuthor:   &handler.AuthorResponse{ID: 1, Name: "Jane Doe", Bio: "Writes about Go"},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "error : author not found",
+			urlParams: map[string]string{"author_id": "2"},
+			mockDB: func() *handler.Application {
+				authorMock := mocks.NewAuthorStore(t)
+				authorMock.EXPECT().GetByID(mock.Anything, 2).Return(nil, models.ErrAuthorNotFound)
+
+				return handler.New(&models.Models{Article: mocks.NewArticleStore(t), Author: authorMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusNotFound, Message: "author not found"},
+		},
+		{
+			name:      "error : unknown expand field",
+			query:     "?expand=owner",
+			urlParams: map[string]string{"author_id": "1"},
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{Article: mocks.NewArticleStore(t), Author: mocks.NewAuthorStore(t)})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid query parameters"},
+		},
+		{
+			name:      "error : database error",
+			urlParams: map[string]string{"author_id": "1"},
+			mockDB: func() *handler.Application {
+				authorMock := mocks.NewAuthorStore(t)
+				authorMock.EXPECT().GetByID(mock.Anything, 1).Return(nil, errors.New("db error"))
+
+				return handler.New(&models.Models{Article: mocks.NewArticleStore(t), Author: authorMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error fetching author by authorID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			r := httptest.NewRequest(http.MethodGet, "/authors/1/articles"+tt.query, nil)
+
+			resp := serveRequest(t, r, app.GetAuthorArticles(), tt.urlParams)
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+
+			if tt.wantAuthor != nil {
+				data, err := json.Marshal(resp.Data)
+				require.NoError(t, err)
+
+				var got []handler.ArticleResponse
+				require.NoError(t, json.Unmarshal(data, &got))
+				require.Len(t, got, 1)
+				assert.Equal(t, got[0].Author, tt.wantAuthor)
+			}
+		})
+	}
+}
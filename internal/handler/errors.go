@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"article/internal/logging"
+	"article/internal/models"
+	"article/internal/response"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// storeError logs err and responds with the status matching a store error kind,
+// errors of unknown kind are logged as errors and reported using msg
+func (app *Application) storeError(ctx context.Context, w http.ResponseWriter, err error, msg string) {
+	logger := logging.FromContext(ctx)
+
+	// the driver error stays in the server log, clients get the message of the store error
+	attrs := []interface{}{logging.Err(err)}
+
+	var storeErr *models.Error
+	if errors.As(err, &storeErr) && storeErr.Cause != nil {
+		attrs = append(attrs, slog.String("cause", storeErr.Cause.Error()))
+	}
+
+	switch {
+	case errors.Is(err, models.ErrNotFound), errors.Is(err, models.ErrConflict),
+		errors.Is(err, models.ErrValidation), errors.Is(err, models.ErrInvalidInput):
+		logger.Info(msg, attrs...)
+	default:
+		logger.Error(msg, attrs...)
+	}
+
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		app.response.NotFound(w, err.Error())
+	case errors.Is(err, models.ErrConflict):
+		app.response.Conflict(w, err.Error())
+	case errors.Is(err, models.ErrValidation):
+		app.response.UnprocessableEntity(w, err.Error())
+	case errors.Is(err, models.ErrInvalidInput):
+		app.response.BadRequest(w, err.Error())
+	default:
+		app.response.ServerError(w, err, msg)
+	}
+}
+
+// validationErrors converts validator errors of req into a message in the envelope format
+// and field errors named after the json fields of req
+func validationErrors(req interface{}, errs validator.ValidationErrors) (string, []response.FieldError) {
+	t := reflect.Indirect(reflect.ValueOf(req)).Type()
+
+	var (
+		messages []string
+		fields   []response.FieldError
+	)
+
+	for _, v := range errs {
+		messages = append(messages, strings.Split(v.Error(), "Error:")[1])
+
+		fields = append(fields, response.FieldError{
+			Field:   jsonName(t, v.StructField()),
+			Rule:    v.Tag(),
+			Param:   v.Param(),
+			Message: ruleMessage(v.Tag(), v.Param()),
+		})
+	}
+
+	return strings.Join(messages, ", "), fields
+}
+
+// jsonName returns json name of a struct field, go name is used when it has no json tag
+func jsonName(t reflect.Type, name string) string {
+	field, ok := t.FieldByName(name)
+	if !ok {
+		return name
+	}
+
+	tag := strings.Split(field.Tag.Get("json"), ",")[0]
+	if tag == "" || tag == "-" {
+		return name
+	}
+
+	return tag
+}
+
+// ruleMessage describes a failed validation rule
+func ruleMessage(rule, param string) string {
+	switch {
+	case rule == "required", rule == "min" && param == "1":
+		return "must not be empty"
+	case rule == "min":
+		return fmt.Sprintf("must be at least %s characters", param)
+	case rule == "max":
+		return fmt.Sprintf("must be at most %s characters", param)
+	}
+
+	return fmt.Sprintf("failed on the '%s' rule", rule)
+}
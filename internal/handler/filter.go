@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"article/internal/models"
+	"article/internal/policy"
+	"article/internal/response"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dateLayout is accepted in date range filters besides RFC 3339 timestamps
+const dateLayout = "2006-01-02"
+
+// listFilter reads filter and sort query params into opts
+func (app *Application) listFilter(w http.ResponseWriter, r *http.Request, opts *models.ListOptions) error {
+	query := r.URL.Query()
+
+	var errs []interface{}
+
+	// author may be repeated to match any of the authors
+	for _, author := range query["author"] {
+		author = strings.TrimSpace(author)
+		if author == "" {
+			errs = append(errs, response.FieldError{Field: "author", Rule: "required", Message: "must not be empty"})
+
+			continue
+		}
+
+		opts.Filter.Authors = append(opts.Filter.Authors, author)
+	}
+
+	// date ranges
+	for _, bound := range []struct {
+		field    string
+		endOfDay bool
+		dst      **time.Time
+	}{
+		{"created_from", false, &opts.Filter.CreatedFrom},
+		{"created_to", true, &opts.Filter.CreatedTo},
+		{"updated_from", false, &opts.Filter.UpdatedFrom},
+		{"updated_to", true, &opts.Filter.UpdatedTo},
+	} {
+		val := query.Get(bound.field)
+		if val == "" {
+			continue
+		}
+
+		t, err := parseTimeBound(val, bound.endOfDay)
+		if err != nil {
+			errs = append(errs, response.FieldError{Field: bound.field, Rule: "datetime", Message: "must be an RFC 3339 timestamp or YYYY-MM-DD date"})
+
+			continue
+		}
+
+		*bound.dst = &t
+	}
+
+	if from, to := opts.Filter.CreatedFrom, opts.Filter.CreatedTo; from != nil && to != nil && to.Before(*from) {
+		errs = append(errs, response.FieldError{Field: "created_to", Rule: "gtefield", Param: "created_from", Message: "must not be before created_from"})
+	}
+
+	if from, to := opts.Filter.UpdatedFrom, opts.Filter.UpdatedTo; from != nil && to != nil && to.Before(*from) {
+		errs = append(errs, response.FieldError{Field: "updated_to", Rule: "gtefield", Param: "updated_from", Message: "must not be before updated_from"})
+	}
+
+	opts.Filter.TitlePrefix = strings.TrimSpace(query.Get("title_prefix"))
+
+	// sort is a comma separated list of fields, a leading - sorts descending
+	if val := query.Get("sort"); val != "" {
+		seen := map[string]bool{}
+
+		for _, field := range strings.Split(val, ",") {
+			field = strings.TrimSpace(field)
+			desc := strings.HasPrefix(field, "-")
+			field = strings.TrimLeft(field, "+-")
+
+			if _, ok := models.SortColumns[field]; !ok {
+				errs = append(errs, response.FieldError{Field: "sort", Rule: "oneof", Param: sortFields(), Message: fmt.Sprintf("cannot sort by '%s'", field)})
+
+				continue
+			}
+
+			if seen[field] {
+				errs = append(errs, response.FieldError{Field: "sort", Rule: "unique", Message: fmt.Sprintf("'%s' is repeated", field)})
+
+				continue
+			}
+
+			seen[field] = true
+			opts.Sort = append(opts.Sort, models.SortField{Field: field, Desc: desc})
+		}
+	}
+
+	if len(errs) > 0 {
+		app.log(r).Info("error validating list query", slog.Any("errors", errs))
+		app.response.BadRequest(w, "invalid query parameters", errs...)
+
+		return errors.New("invalid query parameters")
+	}
+
+	return nil
+}
+
+// statusFilter reads status query params, which may be repeated to match any of the statuses. Only published
+// articles match without them, callers who may read their own unpublished articles only get their owner id
+// returned which limits unpublished articles to theirs, published articles of others still match
+func (app *Application) statusFilter(w http.ResponseWriter, r *http.Request) ([]models.Status, string, error) {
+	var (
+		statuses    []models.Status
+		errs        []interface{}
+		unpublished bool
+	)
+
+	for _, val := range r.URL.Query()["status"] {
+		status := models.Status(strings.TrimSpace(val))
+		if !status.Valid() {
+			errs = append(errs, response.FieldError{Field: "status", Rule: "oneof", Param: statusNames(), Message: fmt.Sprintf("unknown status '%s'", val)})
+
+			continue
+		}
+
+		statuses = append(statuses, status)
+		unpublished = unpublished || status != models.StatusPublished
+	}
+
+	if len(errs) > 0 {
+		app.log(r).Info("error validating status filter", slog.Any("errors", errs))
+		app.response.BadRequest(w, "invalid query parameters", errs...)
+
+		return nil, "", errors.New("invalid query parameters")
+	}
+
+	if len(statuses) == 0 {
+		return []models.Status{models.StatusPublished}, "", nil
+	}
+
+	if !unpublished {
+		return statuses, "", nil
+	}
+
+	if caller := app.caller(r); caller != nil && app.policy.OwnOnly(caller, policy.ReadUnpublished) {
+		return statuses, caller.Subject, nil
+	}
+
+	if !app.authorize(w, r, policy.ReadUnpublished, policy.Resource{}) {
+		return nil, "", policy.ErrForbidden
+	}
+
+	return statuses, "", nil
+}
+
+// statusNames lists statuses separated by space
+func statusNames() string {
+	var names []string
+
+	for _, status := range models.Statuses {
+		names = append(names, string(status))
+	}
+
+	return strings.Join(names, " ")
+}
+
+// sortFields lists sortable fields separated by space
+func sortFields() string {
+	var fields []string
+
+	for field := range models.SortColumns {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	return strings.Join(fields, " ")
+}
+
+// parseTimeBound parses RFC 3339 timestamp or date, endOfDay moves a date to its last instant
+func parseTimeBound(val string, endOfDay bool) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, val)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(dateLayout, val)
+	if err != nil {
+		return t, err
+	}
+
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return t, nil
+}
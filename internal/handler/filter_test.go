@@ -0,0 +1,108 @@
+package handler_test
+
+import (
+	"article/internal/handler"
+	"article/internal/models"
+	"article/internal/response"
+	"article/mocks"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_GetArticles_Filter(t *testing.T) {
+	createdFrom := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdTo := time.Date(2023, 1, 31, 23, 59, 59, 999999999, time.UTC)
+	updatedFrom := time.Date(2023, 2, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		query        string
+		mockDB       func() *handler.Application
+		wantErrors   []response.FieldError
+		wantRespBody response.Body
+	}{
+		{
+			name:  "success",
+			query: "author=Jane&author=John&created_from=2023-01-01&created_to=2023-01-31&updated_from=2023-02-01T10:30:00Z&title_prefix=Go&sort=-created_at,title",
+			mockDB: func() *handler.Application {
+				opts := models.ListOptions{
+					Filter: models.ArticleFilter{
+						Authors:     []string{"Jane", "John"},
+						CreatedFrom: &createdFrom,
+						CreatedTo:   &createdTo,
+						UpdatedFrom: &updatedFrom,
+						TitlePrefix: "Go",
+						Statuses:    []models.Status{models.StatusPublished},
+					},
+					Sort: []models.SortField{{Field: "created_at", Desc: true}, {Field: "title"}},
+					Page: models.Page{Limit: 21},
+				}
+
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, opts).Return([]*models.Article{{ID: 1}}, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(1, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:  "error : invalid params",
+			query: "author=&created_from=yesterday&updated_from=2023-02-01&updated_to=2023-01-01&sort=content,-title,title",
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantErrors: []response.FieldError{
+				{Field: "author", Rule: "required", Message: "must not be empty"},
+				{Field: "created_from", Rule: "datetime", Message: "must be an RFC 3339 timestamp or YYYY-MM-DD date"},
+				{Field: "updated_to", Rule: "gtefield", Param: "updated_from", Message: "must not be before updated_from"},
+				{Field: "sort", Rule: "oneof", Param: "author created_at id title updated_at", Message: "cannot sort by 'content'"},
+				{Field: "sort", Rule: "unique", Message: "'title' is repeated"},
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid query parameters"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// mock database calls
+			app := tt.mockDB()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/articles?"+tt.query, nil)
+
+			app.GetArticles().ServeHTTP(w, r)
+
+			resp := response.Body{}
+			err := json.Unmarshal(w.Body.Bytes(), &resp)
+			if err != nil {
+				t.Errorf("error unmarshalling response : %v", err)
+			}
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+
+			if tt.wantErrors != nil {
+				// convert response data into field errors
+				var gotErrors []response.FieldError
+				aa, err := json.Marshal(resp.Data)
+				if err != nil {
+					t.Error("error marshalling response data to bytes", err)
+				}
+
+				err = json.Unmarshal(aa, &gotErrors)
+				if err != nil {
+					t.Error("error unmarshalling response data", err)
+				}
+
+				assert.Equal(t, gotErrors, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"article/internal/health"
+	"net/http"
+)
+
+// Drain makes readiness fail so load balancers stop routing requests before the server shuts down
+func (app *Application) Drain() {
+	app.draining.Store(true)
+}
+
+// RegisterCheck adds a readiness check e.g. of a database or cache
+func (app *Application) RegisterCheck(check health.Check) {
+	app.health.Register(check)
+}
+
+// Live handles liveness probe, it only reports the process serves requests
+func (app *Application) Live() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		app.response.Success(w, map[string]string{"status": health.StatusOK})
+	}
+}
+
+// Ready handles readiness probe, it lists results of all checks and fails when a critical check fails
+func (app *Application) Ready() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := app.health.Run(r.Context())
+		if !report.Ready() {
+			app.response.ServiceUnavailable(w, "not ready", report)
+
+			return
+		}
+
+		app.response.Success(w, report)
+	}
+}
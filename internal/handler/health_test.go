@@ -0,0 +1,119 @@
+package handler_test
+
+import (
+	"article/internal/handler"
+	"article/internal/health"
+	"article/internal/models"
+	"article/internal/response"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Ready(t *testing.T) {
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+	passing := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name         string
+		mockApp      func() *handler.Application
+		wantStatus   int
+		wantMessage  string
+		wantStatuses map[string]string
+	}{
+		{
+			name: "success - all checks pass",
+			mockApp: func() *handler.Application {
+				app := handler.New(&models.Models{})
+				app.RegisterCheck(health.Check{Name: "database", Critical: true, Func: passing})
+
+				return app
+			},
+			wantStatus:   http.StatusOK,
+			wantMessage:  response.StatusSuccess,
+			wantStatuses: map[string]string{"drain": health.StatusOK, "database": health.StatusOK},
+		},
+		{
+			name: "success - non critical check fails",
+			mockApp: func() *handler.Application {
+				app := handler.New(&models.Models{})
+				app.RegisterCheck(health.Check{Name: "cache", Func: failing})
+
+				return app
+			},
+			wantStatus:   http.StatusOK,
+			wantMessage:  response.StatusSuccess,
+			wantStatuses: map[string]string{"drain": health.StatusOK, "cache": health.StatusFail},
+		},
+		{
+			name: "error - critical check fails",
+			mockApp: func() *handler.Application {
+				app := handler.New(&models.Models{})
+				app.RegisterCheck(health.Check{Name: "database", Critical: true, Func: failing})
+
+				return app
+			},
+			wantStatus:   http.StatusServiceUnavailable,
+			wantMessage:  "not ready",
+			wantStatuses: map[string]string{"drain": health.StatusOK, "database": health.StatusFail},
+		},
+		{
+			name: "error - draining",
+			mockApp: func() *handler.Application {
+				app := handler.New(&models.Models{})
+				app.Drain()
+
+				return app
+			},
+			wantStatus:   http.StatusServiceUnavailable,
+			wantMessage:  "not ready",
+			wantStatuses: map[string]string{"drain": health.StatusFail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockApp()
+
+			r := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			w := httptest.NewRecorder()
+			app.Ready().ServeHTTP(w, r)
+
+			var resp struct {
+				Status  int           `json:"status"`
+				Message string        `json:"message"`
+				Data    health.Report `json:"data"`
+			}
+
+			err := json.Unmarshal(w.Body.Bytes(), &resp)
+			if err != nil {
+				t.Fatalf("error unmarshalling response : %v", err)
+			}
+
+			statuses := map[string]string{}
+			for _, check := range resp.Data.Checks {
+				statuses[check.Name] = check.Status
+			}
+
+			assert.Equal(t, w.Code, tt.wantStatus)
+			assert.Equal(t, resp.Message, tt.wantMessage)
+			assert.Equal(t, statuses, tt.wantStatuses)
+		})
+	}
+}
+
+func Test_Live(t *testing.T) {
+	app := handler.New(&models.Models{})
+	app.Drain()
+
+	// liveness does not depend on readiness
+	r := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	resp := serveRequest(t, r, app.Live(), nil)
+
+	assert.Equal(t, resp.Status, http.StatusOK)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"article/internal/logging"
+	"article/internal/models"
+	"article/internal/response"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// listOptions reads pagination, filter and sort query params
+func (app *Application) listOptions(w http.ResponseWriter, r *http.Request) (*models.ListOptions, error) {
+	var opts models.ListOptions
+
+	// admins may list soft deleted articles
+	includeDeleted, err := app.includeDeleted(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.IncludeDeleted = includeDeleted
+
+	opts.Page, err = app.page(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	// filter and sort
+	err = app.listFilter(w, r, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Filter.Statuses, opts.Filter.OwnerID, err = app.statusFilter(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &opts, nil
+}
+
+// page reads pagination query params
+func (app *Application) page(w http.ResponseWriter, r *http.Request) (models.Page, error) {
+	query := r.URL.Query()
+	page := models.Page{Limit: app.pageSize}
+
+	var err error
+
+	if val := query.Get("limit"); val != "" {
+		page.Limit, err = strconv.Atoi(val)
+		if err != nil || page.Limit < 1 || page.Limit > app.maxPageSize {
+			msg := fmt.Sprintf("limit must be between 1 and %d", app.maxPageSize)
+			app.log(r).Info("error parsing limit", slog.String("value", val))
+			app.response.BadRequest(w, msg)
+
+			return page, errors.New(msg)
+		}
+	}
+
+	if val := query.Get("offset"); val != "" {
+		page.Offset, err = strconv.Atoi(val)
+		if err != nil || page.Offset < 0 {
+			app.log(r).Info("error parsing offset", slog.String("value", val))
+			app.response.BadRequest(w, "offset must be a non negative integer")
+
+			return page, errors.New("invalid offset")
+		}
+	}
+
+	after, before := query.Get("after"), query.Get("before")
+
+	// offset and cursors select a page in different ways and cannot be combined
+	if (after != "" && before != "") || ((after != "" || before != "") && page.Offset > 0) {
+		app.log(r).Info("conflicting pagination params")
+		app.response.BadRequest(w, "only one of offset, after and before may be passed")
+
+		return page, errors.New("conflicting pagination params")
+	}
+
+	if after != "" {
+		page.After, err = models.DecodeCursor(after)
+	}
+
+	if before != "" {
+		page.Before, err = models.DecodeCursor(before)
+	}
+
+	if err != nil {
+		app.log(r).Info("error decoding cursor", logging.Err(err))
+		app.response.BadRequest(w, "invalid cursor")
+
+		return page, err
+	}
+
+	return page, nil
+}
+
+// paginate trims the extra item fetched beyond limit and prepares cursors for neighbour pages
+func paginate[T any](opts models.Page, items []T, limit int, total int64, cursor func(T) *models.Cursor) ([]T, response.Pagination) {
+	page := response.Pagination{Total: total}
+
+	// one extra item is fetched to find out whether another page exists
+	hasMore := len(items) > limit
+	if hasMore {
+		if opts.Before != nil {
+			items = items[1:]
+		} else {
+			items = items[:limit]
+		}
+	}
+
+	if len(items) == 0 {
+		return items, page
+	}
+
+	first, last := items[0], items[len(items)-1]
+
+	if opts.Before != nil {
+		// page before a cursor always has the cursor item after it
+		page.NextCursor = cursor(last).Encode()
+
+		if hasMore {
+			page.PrevCursor = cursor(first).Encode()
+		}
+
+		return items, page
+	}
+
+	if hasMore {
+		page.NextCursor = cursor(last).Encode()
+	}
+
+	if opts.After != nil || opts.Offset > 0 {
+		page.PrevCursor = cursor(first).Encode()
+	}
+
+	return items, page
+}
+
+// pageLinks builds RFC 8288 Link header value for neighbour pages
+func pageLinks(r *http.Request, page response.Pagination) string {
+	link := func(rel, key, cursor string) string {
+		query := r.URL.Query()
+		query.Del("offset")
+		query.Del("after")
+		query.Del("before")
+
+		if key != "" {
+			query.Set(key, cursor)
+		}
+
+		u := *r.URL
+		u.RawQuery = query.Encode()
+
+		return fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel)
+	}
+
+	links := []string{link("first", "", "")}
+
+	if page.PrevCursor != "" {
+		links = append(links, link("prev", "before", page.PrevCursor))
+	}
+
+	if page.NextCursor != "" {
+		links = append(links, link("next", "after", page.NextCursor))
+	}
+
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,166 @@
+package handler_test
+
+import (
+	"article/internal/handler"
+	"article/internal/models"
+	"article/internal/response"
+	"article/mocks"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_GetArticles_Pagination(t *testing.T) {
+	cursor := func(id int) string {
+		return models.NewCursor(&models.Article{ID: id}).Encode()
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		mockDB       func() *handler.Application
+		wantIDs      []int64
+		wantLink     string
+		wantRespBody response.Body
+	}{
+		{
+			name:  "success : first page",
+			query: "limit=2",
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, models.ListOptions{Filter: publishedOnly, Page: models.Page{Limit: 3}}).Return([]*models.Article{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(5, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantIDs:      []int64{1, 2},
+			wantLink:     `</articles?limit=2>; rel="first", </articles?after=` + cursor(2) + `&limit=2>; rel="next"`,
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess, Pagination: &response.Pagination{Total: 5, NextCursor: cursor(2)}},
+		},
+		{
+			name:  "success : page after cursor",
+			query: "limit=2&after=" + cursor(2),
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, models.ListOptions{Filter: publishedOnly, Page: models.Page{Limit: 3, After: &models.Cursor{ID: 2}}}).Return([]*models.Article{{ID: 3}, {ID: 4}, {ID: 5}}, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(5, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantIDs:      []int64{3, 4},
+			wantLink:     `</articles?limit=2>; rel="first", </articles?before=` + cursor(3) + `&limit=2>; rel="prev", </articles?after=` + cursor(4) + `&limit=2>; rel="next"`,
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess, Pagination: &response.Pagination{Total: 5, NextCursor: cursor(4), PrevCursor: cursor(3)}},
+		},
+		{
+			name:  "success : last page before cursor",
+			query: "limit=2&before=" + cursor(3),
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, models.ListOptions{Filter: publishedOnly, Page: models.Page{Limit: 3, Before: &models.Cursor{ID: 3}}}).Return([]*models.Article{{ID: 1}, {ID: 2}}, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(5, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantIDs:      []int64{1, 2},
+			wantLink:     `</articles?limit=2>; rel="first", </articles?after=` + cursor(2) + `&limit=2>; rel="next"`,
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess, Pagination: &response.Pagination{Total: 5, NextCursor: cursor(2)}},
+		},
+		{
+			name:  "success : offset",
+			query: "limit=2&offset=4",
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, models.ListOptions{Filter: publishedOnly, Page: models.Page{Limit: 3, Offset: 4}}).Return([]*models.Article{{ID: 5}}, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(5, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantIDs:      []int64{5},
+			wantLink:     `</articles?limit=2>; rel="first", </articles?before=` + cursor(5) + `&limit=2>; rel="prev"`,
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess, Pagination: &response.Pagination{Total: 5, PrevCursor: cursor(5)}},
+		},
+		{
+			name:  "error : limit above max page size",
+			query: "limit=101",
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "limit must be between 1 and 100"},
+		},
+		{
+			name:  "error : offset with cursor",
+			query: "offset=2&after=" + cursor(2),
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "only one of offset, after and before may be passed"},
+		},
+		{
+			name:  "error : invalid cursor",
+			query: "after=invalid",
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid cursor"},
+		},
+		{
+			name:  "error : count error",
+			query: "limit=2",
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, mock.Anything).Return([]*models.Article{}, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(0, errors.New("db error"))
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error counting articles"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// mock database calls
+			app := tt.mockDB()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/articles?"+tt.query, nil)
+
+			app.GetArticles().ServeHTTP(w, r)
+
+			resp := response.Body{}
+			err := json.Unmarshal(w.Body.Bytes(), &resp)
+			if err != nil {
+				t.Errorf("error unmarshalling response : %v", err)
+			}
+
+			// convert response data into struct
+			var gotResp []*handler.ArticleResponse
+			aa, err := json.Marshal(resp.Data)
+			if err != nil {
+				t.Error("error marshalling response data to bytes", err)
+			}
+
+			err = json.Unmarshal(aa, &gotResp)
+			if err != nil {
+				t.Error("error unmarshalling response data", err)
+			}
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+			assert.Equal(t, resp.Pagination, tt.wantRespBody.Pagination)
+			assert.Equal(t, w.Header().Get("Link"), tt.wantLink)
+
+			var gotIDs []int64
+			for _, a := range gotResp {
+				gotIDs = append(gotIDs, a.ID)
+			}
+
+			assert.Equal(t, gotIDs, tt.wantIDs)
+		})
+	}
+}
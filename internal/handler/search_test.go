@@ -0,0 +1,119 @@
+package handler_test
+
+import (
+	"article/internal/handler"
+	"article/internal/models"
+	"article/internal/response"
+	"article/internal/search"
+	"article/mocks"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_SearchArticles(t *testing.T) {
+	score := 1.5
+	searchCursor := (&models.Cursor{ID: 3, Score: &score}).Encode()
+
+	tests := []struct {
+		name         string
+		query        string
+		mockDB       func() *handler.Application
+		wantResp     []handler.ArticleResponse
+		wantRespBody response.Body
+	}{
+		{
+			name:  "success",
+			query: "q=" + url.QueryEscape(`"worker pool"`) + "&limit=1",
+			mockDB: func() *handler.Application {
+				opts := models.SearchOptions{Query: search.Parse(`"worker pool"`), Statuses: []models.Status{models.StatusPublished}, Page: models.Page{Limit: 2}}
+
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Search(mock.Anything, opts).Return([]*models.SearchResult{
+					{Article: &models.Article{ID: 3, Title: "Pools", Content: "A worker pool in Go"}, Score: 1.5},
+					{Article: &models.Article{ID: 1, Title: "Go", Content: "Worker pool"}, Score: 0.5},
+				}, nil)
+				articleMock.EXPECT().CountSearch(mock.Anything, opts).Return(2, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantResp: []handler.ArticleResponse{
+				{ID: 3, Title: "Pools", Content: "A worker pool in Go", Score: 1.5, Snippet: "A <mark>worker</mark> <mark>pool</mark> in Go"},
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess, Pagination: &response.Pagination{Total: 2, NextCursor: searchCursor}},
+		},
+		{
+			name:  "error : empty query",
+			query: "q=+",
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "please provide search query"},
+		},
+		{
+			name:  "error : list cursor",
+			query: "q=go&after=" + models.NewCursor(&models.Article{ID: 3}).Encode(),
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Search(mock.Anything, mock.Anything).Return(nil, models.ErrInvalidSearchCursor)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid search cursor"},
+		},
+		{
+			name:  "error : database error",
+			query: "q=go",
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Search(mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error searching articles"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// mock database calls
+			app := tt.mockDB()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/articles/search?"+tt.query, nil)
+
+			app.SearchArticles().ServeHTTP(w, r)
+
+			resp := response.Body{}
+			err := json.Unmarshal(w.Body.Bytes(), &resp)
+			if err != nil {
+				t.Errorf("error unmarshalling response : %v", err)
+			}
+
+			// convert response data into struct
+			var gotResp []handler.ArticleResponse
+			aa, err := json.Marshal(resp.Data)
+			if err != nil {
+				t.Error("error marshalling response data to bytes", err)
+			}
+
+			err = json.Unmarshal(aa, &gotResp)
+			if err != nil {
+				t.Error("error unmarshalling response data", err)
+			}
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+			assert.Equal(t, resp.Pagination, tt.wantRespBody.Pagination)
+			if resp.Message == "Success" {
+				assert.Equal(t, gotResp, tt.wantResp)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"article/internal/logging"
+	"article/internal/models"
+	"article/internal/policy"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// TransitionRequest used in status transition requests, the body is optional
+type TransitionRequest struct {
+	Comment string `json:"comment" validate:"max=2000"`
+}
+
+// StatusChangeResponse used in review history responses
+type StatusChangeResponse struct {
+	ID         int    `json:"id"`
+	Transition string `json:"transition"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Actor      string `json:"actor"`
+	Comment    string `json:"comment,omitempty"`
+
+	// CreatedAt is an RFC 3339 timestamp in UTC
+	CreatedAt string `json:"created_at"`
+}
+
+// transitionActions are the policy actions guarding transitions, authors submit and withdraw
+// their own articles while editors review and archive them
+var transitionActions = map[string]policy.Action{
+	models.Submit:   policy.SubmitArticle,
+	models.Withdraw: policy.SubmitArticle,
+	models.Reject:   policy.ReviewArticle,
+	models.Publish:  policy.ReviewArticle,
+	models.Archive:  policy.ArchiveArticle,
+}
+
+// TransitionArticle moves an article along the transition named name and records the caller
+// and comment in its history, the article must be in the status the transition starts from
+func (app *Application) TransitionArticle(name string) http.HandlerFunc {
+	transition, ok := models.FindTransition(name)
+	if !ok {
+		panic(fmt.Sprintf("unknown transition %s", name))
+	}
+
+	action := transitionActions[name]
+
+	return app.traced("TransitionArticle", func(w http.ResponseWriter, r *http.Request) {
+		// database calls are bound to the request and its query timeout
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		id, err := app.articleID(w, r)
+		if err != nil {
+			return
+		}
+
+		// authors may only submit and withdraw articles they own
+		if !app.authorizeArticle(ctx, w, r, action, id) {
+			return
+		}
+
+		var req TransitionRequest
+
+		err = app.validateTransitionRequest(w, r, &req)
+		if err != nil {
+			return
+		}
+
+		change := &models.StatusChange{
+			Transition: transition.Name,
+			From:       transition.From,
+			To:         transition.To,
+			Actor:      app.caller(r).Subject,
+			Comment:    req.Comment,
+		}
+
+		err = app.models.Article.Transition(ctx, id, change)
+		if err != nil {
+			app.storeError(ctx, w, err, "error changing status of article")
+
+			return
+		}
+
+		app.log(r).Info("article status changed", slog.Int("article_id", id), slog.String("transition", name),
+			slog.String("status", string(transition.To)))
+
+		// fetch article with its new status
+		article, err := app.findArticle(ctx, w, id, false)
+		if err != nil {
+			return
+		}
+
+		app.response.Success(w, newArticleResponse(article))
+	})
+}
+
+// GetArticleHistory fetches the status changes of an article oldest first
+func (app *Application) GetArticleHistory() http.HandlerFunc {
+	return app.traced("GetArticleHistory", func(w http.ResponseWriter, r *http.Request) {
+		// database calls are bound to the request and its query timeout
+		ctx, cancel := app.queryContext(r)
+		defer cancel()
+
+		id, err := app.articleID(w, r)
+		if err != nil {
+			return
+		}
+
+		// authors may only read the history of articles they own
+		if !app.authorizeArticle(ctx, w, r, policy.ReadHistory, id) {
+			return
+		}
+
+		changes, err := app.models.Article.History(ctx, id)
+		if err != nil {
+			app.storeError(ctx, w, err, "error fetching history of article")
+
+			return
+		}
+
+		resp := []StatusChangeResponse{}
+		for _, change := range changes {
+			resp = append(resp, newStatusChangeResponse(change))
+		}
+
+		app.response.Success(w, resp)
+	})
+}
+
+// validateTransitionRequest decodes and validates transition request body, an empty body has no comment
+func (app *Application) validateTransitionRequest(w http.ResponseWriter, r *http.Request, req *TransitionRequest) error {
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		app.log(r).Info("error decoding request body", logging.Err(err))
+		app.response.BadRequest(w, "invalid request")
+
+		return err
+	}
+
+	req.Comment = strings.TrimSpace(req.Comment)
+
+	err = app.validate.Struct(req)
+	if err != nil {
+		app.log(r).Info("error validating request", logging.Err(err))
+
+		msg, errs := validationErrors(req, err.(validator.ValidationErrors))
+		app.response.ValidationFailed(w, msg, errs)
+
+		return err
+	}
+
+	return nil
+}
+
+// newStatusChangeResponse prepares response from status change model
+func newStatusChangeResponse(change *models.StatusChange) StatusChangeResponse {
+	return StatusChangeResponse{
+		ID:         change.ID,
+		Transition: change.Transition,
+		From:       string(change.From),
+		To:         string(change.To),
+		Actor:      change.Actor,
+		Comment:    change.Comment,
+		CreatedAt:  formatTime(change.CreatedAt),
+	}
+}
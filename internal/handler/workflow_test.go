@@ -0,0 +1,343 @@
+package handler_test
+
+import (
+	"article/internal/auth"
+	"article/internal/handler"
+	"article/internal/models"
+	"article/internal/response"
+	"article/mocks"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TransitionArticle(t *testing.T) {
+	publishedAt := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
+	editor := &auth.Principal{Subject: "u-9", Name: "Ed", Role: auth.RoleEditor}
+	author := &auth.Principal{Subject: "u-1", Name: "Jane", Role: auth.RoleAuthor}
+
+	tests := []struct {
+		name         string
+		transition   string
+		principal    *auth.Principal
+		body         string
+		mockDB       func() *handler.Application
+		wantResp     *handler.ArticleResponse
+		wantRespBody response.Body
+	}{
+		{
+			name:       "success : editor publishes",
+			transition: models.Publish,
+			principal:  editor,
+			body:       `{"comment":" looks good "}`,
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Transition(mock.Anything, 1, &models.StatusChange{
+					Transition: models.Publish, From: models.StatusInReview, To: models.StatusPublished, Actor: "u-9", Comment: "looks good",
+				}).Return(nil)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, Title: "Test title", Status: models.StatusPublished, PublishedAt: &publishedAt}, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantResp:     &handler.ArticleResponse{ID: 1, Title: "Test title", Status: "published", PublishedAt: "2023-01-02T10:00:00Z"},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:       "success : author submits own article without comment",
+			transition: models.Submit,
+			principal:  author,
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, OwnerID: "u-1", Status: models.StatusDraft}, nil).Once()
+				articleMock.EXPECT().Transition(mock.Anything, 1, &models.StatusChange{
+					Transition: models.Submit, From: models.StatusDraft, To: models.StatusInReview, Actor: "u-1",
+				}).Return(nil)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, OwnerID: "u-1", Status: models.StatusInReview}, nil).Once()
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantResp:     &handler.ArticleResponse{ID: 1, Status: "in_review"},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:       "error : author submits others' article",
+			transition: models.Submit,
+			principal:  author,
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, OwnerID: "u-2", Status: models.StatusDraft}, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "author may only submit articles they own"},
+		},
+		{
+			name:       "error : author publishes",
+			transition: models.Publish,
+			principal:  author,
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{Article: mocks.NewArticleStore(t)})
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "author may not review articles"},
+		},
+		{
+			name:       "error : article in another status",
+			transition: models.Archive,
+			principal:  editor,
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Transition(mock.Anything, 1, mock.Anything).Return(models.ErrConflict)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusConflict, Message: "conflict"},
+		},
+		{
+			name:       "error : invalid body",
+			transition: models.Reject,
+			principal:  editor,
+			body:       `{"comment":`,
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{Article: mocks.NewArticleStore(t)})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid request"},
+		},
+		{
+			name:       "error : comment too long",
+			transition: models.Reject,
+			principal:  editor,
+			body:       `{"comment":"` + strings.Repeat("a", 2001) + `"}`,
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{Article: mocks.NewArticleStore(t)})
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "Field validation for 'Comment' failed on the 'max' tag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			r := httptest.NewRequest(http.MethodPost, "/articles/1/"+tt.transition, strings.NewReader(tt.body))
+			r = r.WithContext(auth.WithPrincipal(r.Context(), tt.principal))
+
+			resp := serveRequest(t, r, app.TransitionArticle(tt.transition), map[string]string{"article_id": "1"})
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+
+			if tt.wantResp != nil {
+				data, err := json.Marshal(resp.Data)
+				require.NoError(t, err)
+
+				var got handler.ArticleResponse
+				require.NoError(t, json.Unmarshal(data, &got))
+				assert.Equal(t, &got, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_GetArticleHistory(t *testing.T) {
+	createdAt := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		mockDB       func() *handler.Application
+		wantResp     []handler.StatusChangeResponse
+		wantRespBody response.Body
+	}{
+		{
+			name: "success",
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().History(mock.Anything, 1).Return([]*models.StatusChange{
+					{ID: 1, ArticleID: 1, Transition: models.Submit, From: models.StatusDraft, To: models.StatusInReview, Actor: "u-1", CreatedAt: createdAt},
+					{ID: 2, ArticleID: 1, Transition: models.Reject, From: models.StatusInReview, To: models.StatusDraft, Actor: "u-9", Comment: "needs sources", CreatedAt: createdAt},
+				}, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantResp: []handler.StatusChangeResponse{
+				{ID: 1, Transition: "submit", From: "draft", To: "in_review", Actor: "u-1", CreatedAt: "2023-01-01T10:00:00Z"},
+				{ID: 2, Transition: "reject", From: "in_review", To: "draft", Actor: "u-9", Comment: "needs sources", CreatedAt: "2023-01-01T10:00:00Z"},
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name: "error : article not found",
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().History(mock.Anything, 1).Return(nil, models.ErrArticleNotFound)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusNotFound, Message: "article not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			resp, err := callEndpoint(t, nil, app.GetArticleHistory(), map[string]string{"article_id": "1"})
+			require.NoError(t, err)
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+
+			if tt.wantResp != nil {
+				data, err := json.Marshal(resp.Data)
+				require.NoError(t, err)
+
+				var got []handler.StatusChangeResponse
+				require.NoError(t, json.Unmarshal(data, &got))
+				assert.Equal(t, got, tt.wantResp)
+			}
+		})
+	}
+}
+
+func Test_GetArticle_Unpublished(t *testing.T) {
+	draft := &models.Article{ID: 1, Title: "Test title", OwnerID: "u-1", Status: models.StatusDraft}
+
+	tests := []struct {
+		name       string
+		principal  *auth.Principal
+		wantStatus int
+	}{
+		{name: "owner reads draft", principal: &auth.Principal{Subject: "u-1", Role: auth.RoleAuthor}, wantStatus: http.StatusOK},
+		{name: "editor reads draft", principal: &auth.Principal{Subject: "u-9", Role: auth.RoleEditor}, wantStatus: http.StatusOK},
+		{name: "other author cannot see draft", principal: &auth.Principal{Subject: "u-2", Role: auth.RoleAuthor}, wantStatus: http.StatusNotFound},
+		{name: "anonymous cannot see draft", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articleMock := mocks.NewArticleStore(t)
+			articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(draft, nil)
+
+			app := handler.New(&models.Models{Article: articleMock})
+
+			r := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+			if tt.principal != nil {
+				r = r.WithContext(auth.WithPrincipal(r.Context(), tt.principal))
+			}
+
+			resp := serveRequest(t, r, app.GetArticle(), map[string]string{"article_id": "1"})
+
+			assert.Equal(t, resp.Status, tt.wantStatus)
+		})
+	}
+}
+
+func Test_GetArticles_Status(t *testing.T) {
+	author := &auth.Principal{Subject: "u-1", Role: auth.RoleAuthor}
+
+	tests := []struct {
+		name         string
+		query        string
+		principal    *auth.Principal
+		mockDB       func() *handler.Application
+		wantErrors   []response.FieldError
+		wantRespBody response.Body
+	}{
+		{
+			name:      "success : editor lists articles in review",
+			query:     "status=in_review",
+			principal: &auth.Principal{Subject: "u-9", Role: auth.RoleEditor},
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, mock.MatchedBy(func(opts models.ListOptions) bool {
+					return assert.ObjectsAreEqual(opts.Filter, models.ArticleFilter{Statuses: []models.Status{models.StatusInReview}})
+				})).Return([]*models.Article{{ID: 1}}, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(1, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "success : author lists own drafts",
+			query:     "status=draft&status=published",
+			principal: author,
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, mock.MatchedBy(func(opts models.ListOptions) bool {
+					return assert.ObjectsAreEqual(opts.Filter, models.ArticleFilter{Statuses: []models.Status{models.StatusDraft, models.StatusPublished}, OwnerID: "u-1"})
+				})).Return([]*models.Article{{ID: 1}}, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(1, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:      "success : published articles of anyone",
+			query:     "status=published",
+			principal: author,
+			mockDB: func() *handler.Application {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().List(mock.Anything, mock.MatchedBy(func(opts models.ListOptions) bool {
+					return assert.ObjectsAreEqual(opts.Filter, publishedOnly)
+				})).Return([]*models.Article{{ID: 1}}, nil)
+				articleMock.EXPECT().Count(mock.Anything, mock.Anything).Return(1, nil)
+
+				return handler.New(&models.Models{Article: articleMock})
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:  "error : anonymous lists drafts",
+			query: "status=draft",
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{Article: mocks.NewArticleStore(t)})
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "anonymous may not read unpublished articles"},
+		},
+		{
+			name:  "error : unknown status",
+			query: "status=pending",
+			mockDB: func() *handler.Application {
+				return handler.New(&models.Models{Article: mocks.NewArticleStore(t)})
+			},
+			wantErrors: []response.FieldError{
+				{Field: "status", Rule: "oneof", Param: "draft in_review published archived", Message: "unknown status 'pending'"},
+			},
+			wantRespBody: response.Body{Status: http.StatusBadRequest, Message: "invalid query parameters"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			r := httptest.NewRequest(http.MethodGet, "/articles?"+tt.query, nil)
+			if tt.principal != nil {
+				r = r.WithContext(auth.WithPrincipal(context.Background(), tt.principal))
+			}
+
+			resp := serveRequest(t, r, app.GetArticles(), nil)
+
+			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
+			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+
+			if tt.wantErrors != nil {
+				data, err := json.Marshal(resp.Data)
+				require.NoError(t, err)
+
+				var gotErrors []response.FieldError
+				require.NoError(t, json.Unmarshal(data, &gotErrors))
+				assert.Equal(t, gotErrors, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// Package health runs readiness checks of the application and its dependencies
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// check statuses
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Check is a named readiness check, the application is not ready while a critical check fails
+type Check struct {
+	Name     string
+	Critical bool
+
+	// Timeout bounds the check, the registry timeout is used when zero
+	Timeout time.Duration
+
+	// Func returns nil when the dependency is usable
+	Func func(ctx context.Context) error
+}
+
+// Result is the outcome of a check
+type Result struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report holds results of all checks in order of registration
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// Ready reports whether all critical checks pass
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// Registry holds checks, dependencies register their checks on start
+type Registry struct {
+	mu      sync.RWMutex
+	checks  []Check
+	timeout time.Duration
+}
+
+// NewRegistry returns empty registry bounding checks by timeout
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Register adds check, checks are run in order of registration
+func (r *Registry) Register(check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks = append(r.checks, check)
+}
+
+// Run runs all checks concurrently, report status fails when a critical check fails
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := append([]Check(nil), r.checks...)
+	r.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Checks: make([]Result, len(checks))}
+
+	var wg sync.WaitGroup
+
+	for i, check := range checks {
+		wg.Add(1)
+
+		go func(i int, check Check) {
+			defer wg.Done()
+
+			report.Checks[i] = r.run(ctx, check)
+		}(i, check)
+	}
+
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Critical && result.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+
+	return report
+}
+
+// run runs check bounded by its timeout
+func (r *Registry) run(ctx context.Context, check Check) Result {
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = r.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Func(ctx)
+
+	result := Result{
+		Name:      check.Name,
+		Status:    StatusOK,
+		Critical:  check.Critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// Ping returns check func pinging db
+func Ping(db *sql.DB) func(ctx context.Context) error {
+	return db.PingContext
+}
+
+// versioner reports applied and latest schema version, implemented by migrations.Migrator
+type versioner interface {
+	Version(ctx context.Context) (current, latest int, err error)
+}
+
+// Migrations returns check func failing while the schema is behind the migrations the binary ships with
+func Migrations(m versioner) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		current, latest, err := m.Version(ctx)
+		if err != nil {
+			return err
+		}
+
+		if current < latest {
+			return fmt.Errorf("schema version %d is behind %d", current, latest)
+		}
+
+		return nil
+	}
+}
+
+// ErrDraining is returned by the drain check while the application shuts down
+var ErrDraining = errors.New("shutting down")
+
+// Draining returns check func failing once draining reports true
+func Draining(draining func() bool) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if draining() {
+			return ErrDraining
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+package health_test
+
+import (
+	"article/internal/health"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// versioner returns fixed schema versions
+type versioner struct {
+	current, latest int
+	err             error
+}
+
+func (v versioner) Version(ctx context.Context) (int, int, error) {
+	return v.current, v.latest, v.err
+}
+
+func Test_Run(t *testing.T) {
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name       string
+		checks     []health.Check
+		wantStatus string
+		wantErrors []string
+	}{
+		{
+			name:       "success - no checks",
+			wantStatus: health.StatusOK,
+		},
+		{
+			name: "success - non critical check fails",
+			checks: []health.Check{
+				{Name: "database", Critical: true, Func: health.Migrations(versioner{current: 5, latest: 5})},
+				{Name: "cache", Func: func(ctx context.Context) error { return errors.New("connection refused") }},
+			},
+			wantStatus: health.StatusOK,
+			wantErrors: []string{"", "connection refused"},
+		},
+		{
+			name: "error - schema behind",
+			checks: []health.Check{
+				{Name: "migrations", Critical: true, Func: health.Migrations(versioner{current: 4, latest: 5})},
+			},
+			wantStatus: health.StatusFail,
+			wantErrors: []string{"schema version 4 is behind 5"},
+		},
+		{
+			name: "error - check times out",
+			checks: []health.Check{
+				{Name: "database", Critical: true, Timeout: 10 * time.Millisecond, Func: slow},
+				{Name: "drain", Critical: true, Func: health.Draining(func() bool { return false })},
+			},
+			wantStatus: health.StatusFail,
+			wantErrors: []string{context.DeadlineExceeded.Error(), ""},
+		},
+		{
+			name: "error - draining",
+			checks: []health.Check{
+				{Name: "drain", Critical: true, Func: health.Draining(func() bool { return true })},
+			},
+			wantStatus: health.StatusFail,
+			wantErrors: []string{health.ErrDraining.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := health.NewRegistry(time.Second)
+			for _, check := range tt.checks {
+				r.Register(check)
+			}
+
+			report := r.Run(context.Background())
+
+			var gotErrors []string
+			for i, result := range report.Checks {
+				assert.Equal(t, result.Name, tt.checks[i].Name)
+				assert.GreaterOrEqual(t, result.LatencyMS, 0.0)
+
+				gotErrors = append(gotErrors, result.Error)
+			}
+
+			assert.Equal(t, report.Status, tt.wantStatus)
+			assert.Equal(t, report.Ready(), tt.wantStatus == health.StatusOK)
+			assert.Equal(t, gotErrors, tt.wantErrors)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Package logging builds structured loggers and carries request scoped loggers in contexts
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// log formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Redacted replaces values of redacted attributes
+const Redacted = "REDACTED"
+
+// redactedKeys name attributes holding article content or credentials, keys are compared case insensitively
+var redactedKeys = map[string]bool{
+	"content":       true,
+	"password":      true,
+	"token":         true,
+	"admin_token":   true,
+	"x-admin-token": true,
+	"authorization": true,
+	"api_key":       true,
+	"secret":        true,
+	"dsn":           true,
+	"cookie":        true,
+}
+
+// Config holds logger settings
+type Config struct {
+	// Level is the lowest level logged, debug, info, warn or error
+	Level string
+
+	// Format is json or text
+	Format string
+
+	// Redact replaces article content and credentials in log attributes
+	Redact bool
+}
+
+// DefaultConfig returns settings used for values not configured
+func DefaultConfig() Config {
+	return Config{
+		Level:  "info",
+		Format: FormatJSON,
+		Redact: true,
+	}
+}
+
+// Validate reports unsupported settings
+func (c Config) Validate() error {
+	_, err := parseLevel(c.Level)
+	if err != nil {
+		return err
+	}
+
+	switch c.Format {
+	case FormatJSON, FormatText:
+	default:
+		return fmt.Errorf("unsupported format %s", c.Format)
+	}
+
+	return nil
+}
+
+// parseLevel parses level names such as info or warn
+func parseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+
+	err := level.UnmarshalText([]byte(s))
+	if err != nil {
+		return level, fmt.Errorf("unsupported level %s", s)
+	}
+
+	return level, nil
+}
+
+// New returns logger writing to w as cfg says
+func New(w io.Writer, cfg Config) (*slog.Logger, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	level, _ := parseLevel(cfg.Level)
+	opts := &slog.HandlerOptions{Level: level}
+
+	if cfg.Redact {
+		opts.ReplaceAttr = redact
+	}
+
+	if cfg.Format == FormatText {
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	}
+
+	return slog.New(slog.NewJSONHandler(w, opts)), nil
+}
+
+// redact replaces values of attributes named in redactedKeys, attributes of groups are redacted too
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindGroup && redactedKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, Redacted)
+	}
+
+	return a
+}
+
+// Err returns attribute of err under the error key
+func Err(err error) slog.Attr {
+	return slog.Any("error", err)
+}
+
+// loggerKey is the context key of request loggers
+type loggerKey struct{}
+
+// WithLogger returns ctx carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns logger of ctx, slog.Default when ctx carries none
+func FromContext(ctx context.Context) *slog.Logger {
+	logger, ok := ctx.Value(loggerKey{}).(*slog.Logger)
+	if !ok {
+		return slog.Default()
+	}
+
+	return logger
+}
@@ -0,0 +1,64 @@
+package logging_test
+
+import (
+	"article/internal/logging"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	tests := []struct {
+		name   string
+		redact bool
+		want   string
+	}{
+		{
+			name:   "redacted by default",
+			redact: true,
+			want:   logging.Redacted,
+		},
+		{
+			name: "shown when redaction is disabled",
+			want: "private",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			cfg := logging.DefaultConfig()
+			cfg.Redact = tt.redact
+
+			logger, err := logging.New(&buf, cfg)
+			require.NoError(t, err)
+
+			logger.Info("article", slog.Group("article", slog.String("title", "title"), slog.String("content", "private")), slog.String("Authorization", "private"))
+
+			rec := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+
+			article := rec["article"].(map[string]interface{})
+
+			assert.Equal(t, article["title"], "title")
+			assert.Equal(t, article["content"], tt.want)
+			assert.Equal(t, rec["Authorization"], tt.want)
+		})
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	cfg := logging.DefaultConfig()
+	assert.Nil(t, cfg.Validate())
+
+	cfg.Format = "xml"
+	assert.EqualError(t, cfg.Validate(), "unsupported format xml")
+
+	cfg.Level = "verbose"
+	assert.EqualError(t, cfg.Validate(), "unsupported level verbose")
+}
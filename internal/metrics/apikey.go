@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"article/internal/models"
+	"context"
+	"time"
+)
+
+// apiKeyStore observes duration and errors of calls to the wrapped store
+type apiKeyStore struct {
+	operations
+	next models.APIKeyStore
+}
+
+// InstrumentAPIKeyStore returns store recording duration of every operation and errors by kind
+func InstrumentAPIKeyStore(r *Registry, next models.APIKeyStore) models.APIKeyStore {
+	return &apiKeyStore{operations: newOperations(r, "api_key_store", "API key store"), next: next}
+}
+
+func (s *apiKeyStore) Create(ctx context.Context, key *models.APIKey) (int64, error) {
+	start := time.Now()
+	id, err := s.next.Create(ctx, key)
+	s.observe("create", start, err)
+
+	return id, err
+}
+
+func (s *apiKeyStore) GetByID(ctx context.Context, keyID int) (*models.APIKey, error) {
+	start := time.Now()
+	key, err := s.next.GetByID(ctx, keyID)
+	s.observe("get_by_id", start, err)
+
+	return key, err
+}
+
+func (s *apiKeyStore) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	start := time.Now()
+	key, err := s.next.GetByPrefix(ctx, prefix)
+	s.observe("get_by_prefix", start, err)
+
+	return key, err
+}
+
+func (s *apiKeyStore) List(ctx context.Context) ([]*models.APIKey, error) {
+	start := time.Now()
+	keys, err := s.next.List(ctx)
+	s.observe("list", start, err)
+
+	return keys, err
+}
+
+func (s *apiKeyStore) Revoke(ctx context.Context, keyID int, at time.Time) error {
+	start := time.Now()
+	err := s.next.Revoke(ctx, keyID, at)
+	s.observe("revoke", start, err)
+
+	return err
+}
+
+func (s *apiKeyStore) SetExpiry(ctx context.Context, keyID int, at time.Time) error {
+	start := time.Now()
+	err := s.next.SetExpiry(ctx, keyID, at)
+	s.observe("set_expiry", start, err)
+
+	return err
+}
+
+func (s *apiKeyStore) Touch(ctx context.Context, keyID int, at time.Time) error {
+	start := time.Now()
+	err := s.next.Touch(ctx, keyID, at)
+	s.observe("touch", start, err)
+
+	return err
+}
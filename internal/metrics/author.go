@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"article/internal/models"
+	"context"
+	"time"
+)
+
+// authorStore observes duration and errors of calls to the wrapped store
+type authorStore struct {
+	operations
+	next models.AuthorStore
+}
+
+// InstrumentAuthorStore returns store recording duration of every operation and errors by kind
+func InstrumentAuthorStore(r *Registry, next models.AuthorStore) models.AuthorStore {
+	return &authorStore{operations: newOperations(r, "author_store", "author store"), next: next}
+}
+
+func (s *authorStore) Create(ctx context.Context, author *models.Author) (int64, error) {
+	start := time.Now()
+	id, err := s.next.Create(ctx, author)
+	s.observe("create", start, err)
+
+	return id, err
+}
+
+func (s *authorStore) GetByID(ctx context.Context, authorID int) (*models.Author, error) {
+	start := time.Now()
+	author, err := s.next.GetByID(ctx, authorID)
+	s.observe("get_by_id", start, err)
+
+	return author, err
+}
+
+func (s *authorStore) GetByIDs(ctx context.Context, authorIDs []int) ([]*models.Author, error) {
+	start := time.Now()
+	authors, err := s.next.GetByIDs(ctx, authorIDs)
+	s.observe("get_by_ids", start, err)
+
+	return authors, err
+}
+
+func (s *authorStore) List(ctx context.Context, page models.Page) ([]*models.Author, error) {
+	start := time.Now()
+	authors, err := s.next.List(ctx, page)
+	s.observe("list", start, err)
+
+	return authors, err
+}
+
+func (s *authorStore) Count(ctx context.Context) (int64, error) {
+	start := time.Now()
+	total, err := s.next.Count(ctx)
+	s.observe("count", start, err)
+
+	return total, err
+}
+
+func (s *authorStore) Update(ctx context.Context, author *models.Author) error {
+	start := time.Now()
+	err := s.next.Update(ctx, author)
+	s.observe("update", start, err)
+
+	return err
+}
+
+func (s *authorStore) Delete(ctx context.Context, authorID int) error {
+	start := time.Now()
+	err := s.next.Delete(ctx, authorID)
+	s.observe("delete", start, err)
+
+	return err
+}
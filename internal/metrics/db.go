@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"database/sql"
+)
+
+// RegisterDBStats registers gauges and counters of the db connection pool, they are read from db.Stats when scraped
+func RegisterDBStats(r *Registry, db *sql.DB) {
+	stats := func(read func(s sql.DBStats) float64) func() float64 {
+		return func() float64 {
+			return read(db.Stats())
+		}
+	}
+
+	r.NewGaugeFunc("db_max_open_connections", "Maximum number of open connections to the database.", stats(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }))
+	r.NewGaugeFunc("db_open_connections", "Established connections both in use and idle.", stats(func(s sql.DBStats) float64 { return float64(s.OpenConnections) }))
+	r.NewGaugeFunc("db_in_use_connections", "Connections currently in use.", stats(func(s sql.DBStats) float64 { return float64(s.InUse) }))
+	r.NewGaugeFunc("db_idle_connections", "Idle connections.", stats(func(s sql.DBStats) float64 { return float64(s.Idle) }))
+	r.NewCounterFunc("db_wait_count_total", "Connections waited for.", stats(func(s sql.DBStats) float64 { return float64(s.WaitCount) }))
+	r.NewCounterFunc("db_wait_duration_seconds_total", "Time blocked waiting for a connection.", stats(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }))
+	r.NewCounterFunc("db_max_idle_closed_total", "Connections closed due to max idle connections.", stats(func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }))
+	r.NewCounterFunc("db_max_idle_time_closed_total", "Connections closed due to max idle time.", stats(func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) }))
+	r.NewCounterFunc("db_max_lifetime_closed_total", "Connections closed due to max lifetime.", stats(func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }))
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+)
+
+// unmatchedRoute labels requests no route matched so scanners cannot add series per path
+const unmatchedRoute = "unmatched"
+
+// HTTP returns middleware counting requests and observing their latency by method, chi route pattern and status,
+// it must run inside the chi router so the route is known once the request is served
+func HTTP(r *Registry) func(http.Handler) http.Handler {
+	requests := r.NewCounterVec("http_requests_total", "HTTP requests served.", "method", "route", "status")
+	durations := r.NewHistogramVec("http_request_duration_seconds", "Latency of HTTP requests.", DefaultBuckets, "method", "route", "status")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, req.ProtoMajor)
+
+			next.ServeHTTP(ww, req)
+
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(req.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+
+			// handlers writing no body nor header answer 200
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			labels := []string{req.Method, route, strconv.Itoa(status)}
+			requests.Inc(labels...)
+			durations.Observe(time.Since(start).Seconds(), labels...)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package metrics_test
+
+import (
+	"article/internal/metrics"
+	"article/internal/models"
+	"article/mocks"
+	"bytes"
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	_ "modernc.org/sqlite"
+)
+
+// scrape returns metrics served by the handler of r
+func scrape(t *testing.T, r *metrics.Registry) string {
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, w.Header().Get("Content-Type"), metrics.ContentType)
+
+	return w.Body.String()
+}
+
+func Test_Registry(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	requests := r.NewCounterVec("requests_total", "Requests served.", "method", "path")
+	requests.Inc("GET", `/a"b`)
+	requests.Add(2, "GET", "/")
+
+	latency := r.NewHistogramVec("latency_seconds", "Request latency.", []float64{0.1, 1}, "method")
+	latency.Observe(0.05, "GET")
+	latency.Observe(0.5, "GET")
+	latency.Observe(3, "GET")
+
+	r.NewGaugeFunc("temperature", "Current temperature.\nIn celsius.", func() float64 { return 21.5 })
+
+	want := `# HELP latency_seconds Request latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{method="GET",le="0.1"} 1
+latency_seconds_bucket{method="GET",le="1"} 2
+latency_seconds_bucket{method="GET",le="+Inf"} 3
+latency_seconds_sum{method="GET"} 3.55
+latency_seconds_count{method="GET"} 3
+# HELP requests_total Requests served.
+# TYPE requests_total counter
+requests_total{method="GET",path="/"} 2
+requests_total{method="GET",path="/a\"b"} 1
+# HELP temperature Current temperature.\nIn celsius.
+# TYPE temperature gauge
+temperature 21.5
+`
+
+	assert.Equal(t, scrape(t, r), want)
+	assert.Panics(t, func() { r.NewCounterVec("requests_total", "Requests served.") })
+}
+
+func Test_InstrumentArticleStore(t *testing.T) {
+	articleMock := mocks.NewArticleStore(t)
+	articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1}, nil)
+	articleMock.EXPECT().GetByID(mock.Anything, 2, false).Return(nil, models.ErrNotFound)
+	articleMock.EXPECT().Delete(mock.Anything, 1).Return(context.DeadlineExceeded)
+
+	r := metrics.NewRegistry()
+	store := metrics.InstrumentArticleStore(r, articleMock)
+
+	got, err := store.GetByID(context.Background(), 1, false)
+	assert.Nil(t, err)
+	assert.Equal(t, got.ID, 1)
+
+	_, err = store.GetByID(context.Background(), 2, false)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	err = store.Delete(context.Background(), 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	out := scrape(t, r)
+	assert.Contains(t, out, `article_store_operation_duration_seconds_count{operation="get_by_id"} 2`)
+	assert.Contains(t, out, `article_store_operation_duration_seconds_count{operation="delete"} 1`)
+	assert.Contains(t, out, `article_store_operation_errors_total{operation="get_by_id",kind="not_found"} 1`)
+	assert.Contains(t, out, `article_store_operation_errors_total{operation="delete",kind="timeout"} 1`)
+}
+
+func Test_InstrumentAuthorAndAPIKeyStores(t *testing.T) {
+	authorMock := mocks.NewAuthorStore(t)
+	authorMock.EXPECT().GetByID(mock.Anything, 1).Return(nil, models.ErrAuthorNotFound)
+
+	keyMock := mocks.NewAPIKeyStore(t)
+	keyMock.EXPECT().Touch(mock.Anything, 2, mock.Anything).Return(nil)
+
+	r := metrics.NewRegistry()
+
+	_, err := metrics.InstrumentAuthorStore(r, authorMock).GetByID(context.Background(), 1)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	err = metrics.InstrumentAPIKeyStore(r, keyMock).Touch(context.Background(), 2, time.Now())
+	assert.Nil(t, err)
+
+	out := scrape(t, r)
+	assert.Contains(t, out, `author_store_operation_errors_total{operation="get_by_id",kind="not_found"} 1`)
+	assert.Contains(t, out, `api_key_store_operation_duration_seconds_count{operation="touch"} 1`)
+}
+
+func Test_RegisterDBStats(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "article.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	db.SetMaxOpenConns(3)
+
+	err = db.Ping()
+	assert.Nil(t, err)
+
+	r := metrics.NewRegistry()
+	metrics.RegisterDBStats(r, db)
+
+	var out bytes.Buffer
+	_, err = r.WriteTo(&out)
+	assert.Nil(t, err)
+
+	assert.Contains(t, out.String(), "# TYPE db_max_open_connections gauge\ndb_max_open_connections 3\n")
+	assert.Contains(t, out.String(), "db_open_connections 1\n")
+	assert.Contains(t, out.String(), "# TYPE db_wait_count_total counter\n")
+}
@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"article/internal/models"
+	"context"
+	"errors"
+	"time"
+)
+
+// operations records duration and errors of operations of a store
+type operations struct {
+	durations *HistogramVec
+	errors    *CounterVec
+}
+
+// newOperations registers metrics of store operations prefixed by name, store names the store in help texts
+func newOperations(r *Registry, name, store string) operations {
+	return operations{
+		durations: r.NewHistogramVec(name+"_operation_duration_seconds", "Duration of "+store+" operations.", DefaultBuckets, "operation"),
+		errors:    r.NewCounterVec(name+"_operation_errors_total", "Failed "+store+" operations.", "operation", "kind"),
+	}
+}
+
+// observe records duration of operation since start and the kind of err
+func (o operations) observe(operation string, start time.Time, err error) {
+	o.durations.Observe(time.Since(start).Seconds(), operation)
+
+	if err != nil {
+		o.errors.Inc(operation, errorKind(err))
+	}
+}
+
+// articleStore observes duration and errors of calls to the wrapped store
+type articleStore struct {
+	operations
+	next models.ArticleStore
+}
+
+// InstrumentArticleStore returns store recording duration of every operation and errors by kind
+func InstrumentArticleStore(r *Registry, next models.ArticleStore) models.ArticleStore {
+	return &articleStore{operations: newOperations(r, "article_store", "article store"), next: next}
+}
+
+// errorKind classifies store errors, expected outcomes such as not found are told apart from failures
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, models.ErrConflict):
+		return "conflict"
+	case errors.Is(err, models.ErrValidation), errors.Is(err, models.ErrInvalidInput):
+		return "invalid"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "internal"
+	}
+}
+
+func (s *articleStore) Store(ctx context.Context, article *models.Article) (int64, error) {
+	start := time.Now()
+	id, err := s.next.Store(ctx, article)
+	s.observe("store", start, err)
+
+	return id, err
+}
+
+func (s *articleStore) GetByID(ctx context.Context, articleID int, includeDeleted bool) (*models.Article, error) {
+	start := time.Now()
+	article, err := s.next.GetByID(ctx, articleID, includeDeleted)
+	s.observe("get_by_id", start, err)
+
+	return article, err
+}
+
+func (s *articleStore) List(ctx context.Context, opts models.ListOptions) ([]*models.Article, error) {
+	start := time.Now()
+	articles, err := s.next.List(ctx, opts)
+	s.observe("list", start, err)
+
+	return articles, err
+}
+
+func (s *articleStore) Count(ctx context.Context, opts models.ListOptions) (int64, error) {
+	start := time.Now()
+	total, err := s.next.Count(ctx, opts)
+	s.observe("count", start, err)
+
+	return total, err
+}
+
+func (s *articleStore) Search(ctx context.Context, opts models.SearchOptions) ([]*models.SearchResult, error) {
+	start := time.Now()
+	results, err := s.next.Search(ctx, opts)
+	s.observe("search", start, err)
+
+	return results, err
+}
+
+func (s *articleStore) CountSearch(ctx context.Context, opts models.SearchOptions) (int64, error) {
+	start := time.Now()
+	total, err := s.next.CountSearch(ctx, opts)
+	s.observe("count_search", start, err)
+
+	return total, err
+}
+
+func (s *articleStore) Update(ctx context.Context, article *models.Article) error {
+	start := time.Now()
+	err := s.next.Update(ctx, article)
+	s.observe("update", start, err)
+
+	return err
+}
+
+func (s *articleStore) Patch(ctx context.Context, articleID int, patch *models.ArticlePatch) error {
+	start := time.Now()
+	err := s.next.Patch(ctx, articleID, patch)
+	s.observe("patch", start, err)
+
+	return err
+}
+
+func (s *articleStore) Delete(ctx context.Context, articleID int) error {
+	start := time.Now()
+	err := s.next.Delete(ctx, articleID)
+	s.observe("delete", start, err)
+
+	return err
+}
+
+func (s *articleStore) Restore(ctx context.Context, articleID int) error {
+	start := time.Now()
+	err := s.next.Restore(ctx, articleID)
+	s.observe("restore", start, err)
+
+	return err
+}
+
+func (s *articleStore) Purge(ctx context.Context, articleID int) error {
+	start := time.Now()
+	err := s.next.Purge(ctx, articleID)
+	s.observe("purge", start, err)
+
+	return err
+}
+
+func (s *articleStore) Transition(ctx context.Context, articleID int, change *models.StatusChange) error {
+	start := time.Now()
+	err := s.next.Transition(ctx, articleID, change)
+	s.observe("transition", start, err)
+
+	return err
+}
+
+func (s *articleStore) History(ctx context.Context, articleID int) ([]*models.StatusChange, error) {
+	start := time.Now()
+	changes, err := s.next.History(ctx, articleID)
+	s.observe("history", start, err)
+
+	return changes, err
+}
@@ -0,0 +1,534 @@
+package migrations
+
+import (
+	"article/internal/database"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// lockName names the lock held while migrating
+	lockName = "article_schema_migrations"
+
+	// lockTimeout is the number of seconds to wait for another instance to finish migrating
+	lockTimeout = 60
+)
+
+var (
+	ErrChecksumMismatch = errors.New("applied migration checksum does not match source")
+	ErrUnknownMigration = errors.New("applied migration not found in source")
+	ErrLocked           = errors.New("could not acquire migration lock")
+	ErrInvalidName      = errors.New("invalid migration name")
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// fileName matches migration file names e.g. 000001_create_article.up.sql
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration holds a versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum returns sha256 of up migration, used to detect edits of applied migrations
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Status holds a migration and whether it is applied
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// applied holds a row of schema_migrations
+type applied struct {
+	version   int
+	checksum  string
+	appliedAt time.Time
+}
+
+// locker takes the migration lock on conn, release gives it back
+type locker func(ctx context.Context, conn *sql.Conn) (release func(), err error)
+
+// Migrator applies migrations to database
+type Migrator struct {
+	db     *sql.DB
+	source fs.FS
+	lock   locker
+
+	// rebind rewrites ? placeholders of schema_migrations queries, nil keeps them
+	rebind func(query string) string
+}
+
+// New returns migrator using embedded MySQL migrations
+func New(db *sql.DB) *Migrator {
+	source, _ := fs.Sub(files, "mysql")
+
+	return NewWithSource(db, source)
+}
+
+// NewPostgres returns migrator using embedded PostgreSQL migrations
+func NewPostgres(db *sql.DB) *Migrator {
+	source, _ := fs.Sub(files, "postgres")
+
+	return &Migrator{db: db, source: source, lock: postgresLock, rebind: database.Rebind}
+}
+
+// NewSQLite returns migrator using embedded SQLite migrations
+func NewSQLite(db *sql.DB) *Migrator {
+	source, _ := fs.Sub(files, "sqlite")
+
+	return &Migrator{db: db, source: source, lock: sqliteLock}
+}
+
+// NewWithSource returns migrator reading MySQL migrations from source
+func NewWithSource(db *sql.DB, source fs.FS) *Migrator {
+	return &Migrator{db: db, source: source, lock: mysqlLock}
+}
+
+// Load reads migrations from source ordered by version
+func Load(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, _ := strconv.Atoi(match[1])
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %s and %s", version, m.Name, match[2])
+		}
+
+		raw, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		if match[3] == "up" {
+			m.Up = string(raw)
+		} else {
+			m.Down = string(raw)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+
+		// rolling back without a down file would drop the record of the migration but keep its changes,
+		// migrations with nothing to roll back say so in a down file of comments
+		if m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies all pending migrations and returns them
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		pending, _, err := m.pending(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range pending {
+			err = m.apply(ctx, conn, migration.Up,
+				m.bind(`INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)`),
+				migration.Version, migration.Name, migration.Checksum())
+			if err != nil {
+				return fmt.Errorf("applying migration %d_%s : %w", migration.Version, migration.Name, err)
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down rolls back last steps applied migrations and returns them
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		_, appliedMigrations, err := m.pending(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(appliedMigrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := appliedMigrations[i]
+
+			err = m.apply(ctx, conn, migration.Down,
+				m.bind(`DELETE FROM schema_migrations WHERE version = ?`), migration.Version)
+			if err != nil {
+				return fmt.Errorf("rolling back migration %d_%s : %w", migration.Version, migration.Name, err)
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Status lists all migrations in source and applied migrations missing from source
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	migrations, err := Load(m.source)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	rows, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+
+	for _, migration := range migrations {
+		status := Status{Migration: migration}
+
+		if row, ok := rows[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = row.appliedAt
+			delete(rows, migration.Version)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	// applied migrations whose files were removed
+	for _, row := range rows {
+		statuses = append(statuses, Status{Migration: Migration{Version: row.version}, Applied: true, AppliedAt: row.appliedAt})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Pending verifies applied migrations and returns migrations not applied yet
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	pending, _, err := m.pending(ctx, conn)
+
+	return pending, err
+}
+
+// Version returns the highest applied version and the latest version in source,
+// it only reads schema_migrations so it is cheap enough for health checks
+func (m *Migrator) Version(ctx context.Context) (current, latest int, err error) {
+	migrations, err := Load(m.source)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+
+	var version sql.NullInt64
+
+	err = m.db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, latest, err
+	}
+
+	return int(version.Int64), latest, nil
+}
+
+// pending verifies checksums of applied migrations and splits source into pending and applied migrations
+func (m *Migrator) pending(ctx context.Context, conn *sql.Conn) ([]Migration, []Migration, error) {
+	migrations, err := Load(m.source)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pending, appliedMigrations []Migration
+
+	for _, migration := range migrations {
+		row, ok := rows[migration.Version]
+		if !ok {
+			pending = append(pending, migration)
+
+			continue
+		}
+
+		if row.checksum != migration.Checksum() {
+			return nil, nil, fmt.Errorf("migration %d_%s : %w", migration.Version, migration.Name, ErrChecksumMismatch)
+		}
+
+		appliedMigrations = append(appliedMigrations, migration)
+		delete(rows, migration.Version)
+	}
+
+	for version := range rows {
+		return nil, nil, fmt.Errorf("migration %d : %w", version, ErrUnknownMigration)
+	}
+
+	return pending, appliedMigrations, nil
+}
+
+// applied reads schema_migrations keyed by version, the table is created when missing
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int]applied, error) {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		checksum CHAR(64) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := map[int]applied{}
+
+	for rows.Next() {
+		var row applied
+
+		err = rows.Scan(&row.version, &row.checksum, &row.appliedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		result[row.version] = row
+	}
+
+	return result, rows.Err()
+}
+
+// bind returns query with placeholders of the database
+func (m *Migrator) bind(query string) string {
+	if m.rebind == nil {
+		return query
+	}
+
+	return m.rebind(query)
+}
+
+// apply runs migration statements and records it in a transaction,
+// MySQL commits DDL statements implicitly so a failed migration may be partially applied,
+// SQLite rolls back all of it
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range Statements(script) {
+		_, err = tx.ExecContext(ctx, statement)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, record, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// locked runs fn holding the migration lock so concurrent instances do not migrate together
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	// locks may belong to a connection, the lock and migrations share one
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	release, err := m.lock(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return fn(conn)
+}
+
+// mysqlLock takes a MySQL named lock, waiting lockTimeout for another instance to finish
+func mysqlLock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	var acquired sql.NullInt64
+
+	err := conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`, lockName, lockTimeout).Scan(&acquired)
+	if err != nil {
+		return nil, err
+	}
+
+	if acquired.Int64 != 1 {
+		return nil, ErrLocked
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), `SELECT RELEASE_LOCK(?)`, lockName)
+	}, nil
+}
+
+// postgresLock takes a session advisory lock keyed by hash of lockName, waiting lockTimeout for another instance to finish
+func postgresLock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	lockCtx, cancel := context.WithTimeout(ctx, lockTimeout*time.Second)
+	defer cancel()
+
+	_, err := conn.ExecContext(lockCtx, `SELECT pg_advisory_lock(hashtext($1))`, lockName)
+	if err != nil {
+		// waiting was cut short by lockTimeout rather than by the caller
+		if ctx.Err() == nil && lockCtx.Err() != nil {
+			return nil, ErrLocked
+		}
+
+		return nil, err
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, lockName)
+	}, nil
+}
+
+// sqliteLock takes no lock, SQLite allows a single writer and runs DDL in transactions
+// so an instance racing another one fails to record a migration and rolls it back
+func sqliteLock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	return func() {}, nil
+}
+
+// Statements splits script into statements ending with semicolon at end of line
+func Statements(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+	)
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
+
+// Create writes empty up and down files for a new migration in dir and returns their paths
+func Create(dir, name string) ([]string, error) {
+	name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return nil, ErrInvalidName
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	version := 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	var paths []string
+
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%06d_%s.%s.sql", version, name, direction))
+
+		err = os.WriteFile(path, []byte(fmt.Sprintf("-- %s migration %06d_%s\n", direction, version, name)), 0o644)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
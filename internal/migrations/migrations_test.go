@@ -0,0 +1,447 @@
+package migrations_test
+
+import (
+	"article/internal/migrations"
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+// source holds two test migrations
+var source = fstest.MapFS{
+	"000001_create_article.up.sql":   {Data: []byte("CREATE TABLE article (id INT);\n")},
+	"000001_create_article.down.sql": {Data: []byte("DROP TABLE article;\n")},
+	"000002_add_title.up.sql":        {Data: []byte("-- add title\nALTER TABLE article\n    ADD COLUMN title TEXT;\nCREATE INDEX idx ON article (title);\n")},
+	"000002_add_title.down.sql":      {Data: []byte("ALTER TABLE article DROP COLUMN title;\n")},
+	"README.md":                      {Data: []byte("not a migration")},
+}
+
+func Test_Load(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       fstest.MapFS
+		wantVersions []int
+		wantErr      bool
+	}{
+		{
+			name:         "success",
+			source:       source,
+			wantVersions: []int{1, 2},
+		},
+		{
+			name:    "error : missing up file",
+			source:  fstest.MapFS{"000001_create_article.down.sql": {Data: []byte("DROP TABLE article;")}},
+			wantErr: true,
+		},
+		{
+			name:    "error : missing down file",
+			source:  fstest.MapFS{"000001_create_article.up.sql": {Data: []byte("CREATE TABLE article (id INT);")}},
+			wantErr: true,
+		},
+		{
+			name: "error : conflicting names",
+			source: fstest.MapFS{
+				"000001_create_article.up.sql": {Data: []byte("CREATE TABLE article (id INT);")},
+				"000001_create_author.up.sql":  {Data: []byte("CREATE TABLE author (id INT);")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := migrations.Load(tt.source)
+			assert.Equal(t, err != nil, tt.wantErr)
+
+			var gotVersions []int
+			for _, m := range got {
+				gotVersions = append(gotVersions, m.Version)
+			}
+
+			assert.Equal(t, gotVersions, tt.wantVersions)
+		})
+	}
+}
+
+func Test_Statements(t *testing.T) {
+	got := migrations.Statements("-- comment\nALTER TABLE article\n    ADD COLUMN title TEXT;\n\nCREATE INDEX idx ON article (title);\nSELECT 1")
+
+	assert.Equal(t, got, []string{"ALTER TABLE article\n    ADD COLUMN title TEXT", "CREATE INDEX idx ON article (title)", "SELECT 1"})
+}
+
+func Test_MySQLMigrations(t *testing.T) {
+	got, err := migrations.Load(os.DirFS("mysql"))
+	assert.Nil(t, err)
+
+	// every migration can be rolled back
+	for i, m := range got {
+		assert.Equal(t, m.Version, i+1)
+		assert.NotEmpty(t, m.Down)
+	}
+}
+
+func Test_DialectMigrations(t *testing.T) {
+	want, err := migrations.Load(os.DirFS("mysql"))
+	assert.Nil(t, err)
+
+	// every database has the same versions so a schema change is one version everywhere
+	for _, dir := range []string{"postgres", "sqlite"} {
+		got, err := migrations.Load(os.DirFS(dir))
+		assert.Nil(t, err)
+		assert.Equal(t, len(got), len(want))
+
+		for i := range got {
+			assert.Equal(t, got[i].Version, want[i].Version)
+			assert.Equal(t, got[i].Name, want[i].Name)
+		}
+	}
+}
+
+func Test_PostgresDown(t *testing.T) {
+	all, _ := migrations.Load(os.DirFS("postgres"))
+	db, mock := newMock(t)
+
+	rows := sqlmock.NewRows([]string{"version", "checksum", "applied_at"})
+	for _, m := range all {
+		rows.AddRow(m.Version, m.Checksum(), time.Now())
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock(hashtext($1))")).WithArgs("article_schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, checksum, applied_at FROM schema_migrations").WillReturnRows(rows)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DROP INDEX idx_author_subject")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE author DROP COLUMN subject")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = $1")).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DROP TABLE IF EXISTS article_status_change")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DROP INDEX idx_article_status")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE article\n    DROP COLUMN published_at,\n    DROP COLUMN status")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = $1")).WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock(hashtext($1))")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	got, err := migrations.NewPostgres(db).Down(context.Background(), 2)
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(got), 2)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Up(t *testing.T) {
+	all, _ := migrations.Load(source)
+
+	tests := []struct {
+		name         string
+		mockDB       func() *sql.DB
+		wantVersions []int
+		wantErr      error
+	}{
+		{
+			name: "success",
+			mockDB: func() *sql.DB {
+				db, mock := newMock(t)
+
+				mock.ExpectQuery("SELECT GET_LOCK").WithArgs("article_schema_migrations", 60).WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+				expectApplied(mock, all[0].Checksum())
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE article\n    ADD COLUMN title TEXT")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX idx ON article (title)")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(2, "add_title", all[1].Checksum()).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectExec("SELECT RELEASE_LOCK").WithArgs("article_schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return db
+			},
+			wantVersions: []int{2},
+		},
+		{
+			name: "error : checksum mismatch",
+			mockDB: func() *sql.DB {
+				db, mock := newMock(t)
+
+				mock.ExpectQuery("SELECT GET_LOCK").WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+				expectApplied(mock, "edited")
+				mock.ExpectExec("SELECT RELEASE_LOCK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return db
+			},
+			wantErr: migrations.ErrChecksumMismatch,
+		},
+		{
+			name: "error : locked",
+			mockDB: func() *sql.DB {
+				db, mock := newMock(t)
+
+				mock.ExpectQuery("SELECT GET_LOCK").WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(0))
+
+				return db
+			},
+			wantErr: migrations.ErrLocked,
+		},
+		{
+			name: "error : migration error",
+			mockDB: func() *sql.DB {
+				db, mock := newMock(t)
+
+				mock.ExpectQuery("SELECT GET_LOCK").WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+				expectApplied(mock, all[0].Checksum())
+				mock.ExpectBegin()
+				mock.ExpectExec("ALTER TABLE").WillReturnError(errors.New("db error"))
+				mock.ExpectRollback()
+				mock.ExpectExec("SELECT RELEASE_LOCK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return db
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := migrations.NewWithSource(tt.mockDB(), source)
+
+			got, err := m.Up(context.Background())
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+			} else {
+				assert.Nil(t, err)
+			}
+
+			var gotVersions []int
+			for _, m := range got {
+				gotVersions = append(gotVersions, m.Version)
+			}
+
+			assert.Equal(t, gotVersions, tt.wantVersions)
+		})
+	}
+}
+
+func Test_Down(t *testing.T) {
+	all, _ := migrations.Load(source)
+	db, mock := newMock(t)
+
+	mock.ExpectQuery("SELECT GET_LOCK").WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, checksum, applied_at FROM schema_migrations").WillReturnRows(
+		sqlmock.NewRows([]string{"version", "checksum", "applied_at"}).
+			AddRow(1, all[0].Checksum(), time.Now()).
+			AddRow(2, all[1].Checksum(), time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE article DROP COLUMN title").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT RELEASE_LOCK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	got, err := migrations.NewWithSource(db, source).Down(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(got), 1)
+	assert.Equal(t, got[0].Version, 2)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Status(t *testing.T) {
+	all, _ := migrations.Load(source)
+	appliedAt := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	db, mock := newMock(t)
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, checksum, applied_at FROM schema_migrations").WillReturnRows(
+		sqlmock.NewRows([]string{"version", "checksum", "applied_at"}).AddRow(1, all[0].Checksum(), appliedAt))
+
+	got, err := migrations.NewWithSource(db, source).Status(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(got), 2)
+	assert.Equal(t, got[0].Applied, true)
+	assert.Equal(t, got[0].AppliedAt, appliedAt)
+	assert.Equal(t, got[1].Applied, false)
+}
+
+func Test_Create(t *testing.T) {
+	dir := t.TempDir()
+
+	for name, data := range source {
+		err := os.WriteFile(filepath.Join(dir, name), data.Data, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := migrations.Create(dir, "Add Author")
+	assert.Nil(t, err)
+	assert.Equal(t, got, []string{filepath.Join(dir, "000003_add_author.up.sql"), filepath.Join(dir, "000003_add_author.down.sql")})
+
+	_, err = migrations.Create(dir, "drop; table")
+	assert.Equal(t, err, migrations.ErrInvalidName)
+}
+
+// newMock opens sqlmock database connection
+func newMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection %v", err)
+	}
+
+	return db, mock
+}
+
+// expectApplied mocks schema_migrations with first migration applied
+func expectApplied(mock sqlmock.Sqlmock, checksum string) {
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, checksum, applied_at FROM schema_migrations").WillReturnRows(
+		sqlmock.NewRows([]string{"version", "checksum", "applied_at"}).AddRow(1, checksum, time.Now()))
+}
+
+func Test_SQLiteMigrations(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "article.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	m := migrations.NewSQLite(db)
+
+	// embedded migrations apply, roll back and apply again cleanly
+	applied, err := m.Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, len(applied), 10)
+
+	rolledBack, err := m.Down(ctx, len(applied))
+	assert.Nil(t, err)
+	assert.Equal(t, len(rolledBack), 10)
+
+	current, latest, err := m.Version(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, current, 0)
+	assert.Equal(t, latest, 10)
+
+	_, err = m.Up(ctx)
+	assert.Nil(t, err)
+
+	pending, err := m.Pending(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, len(pending), 0)
+
+	current, _, err = m.Version(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, current, 10)
+}
+
+func Test_SQLiteAuthorDedup(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "article.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	m := migrations.NewSQLite(db)
+
+	// articles written before authors existed
+	_, err = m.Up(ctx)
+	assert.Nil(t, err)
+
+	_, err = m.Down(ctx, 3)
+	assert.Nil(t, err)
+
+	for _, author := range []string{"Jane Doe", "jane doe", " Jane Doe ", "John"} {
+		_, err = db.ExecContext(ctx, "INSERT INTO article (title, content, author) VALUES ('title', 'content', ?)", author)
+		assert.Nil(t, err)
+	}
+
+	_, err = m.Up(ctx)
+	assert.Nil(t, err)
+
+	var authors int
+
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM author").Scan(&authors)
+	assert.Nil(t, err)
+	assert.Equal(t, authors, 2)
+
+	rows, err := db.QueryContext(ctx, "SELECT a.author, a.author_id, au.name FROM article AS a JOIN author AS au ON au.id = a.author_id ORDER BY a.id")
+	assert.Nil(t, err)
+	defer rows.Close()
+
+	var names []string
+
+	for rows.Next() {
+		var (
+			author, name string
+			authorID     int
+		)
+
+		assert.Nil(t, rows.Scan(&author, &authorID, &name))
+		assert.Equal(t, author, name)
+
+		names = append(names, author)
+	}
+
+	// every spelling of an author names the same author
+	assert.Equal(t, names[0], names[1])
+	assert.Equal(t, names[0], names[2])
+	assert.Equal(t, names[3], "John")
+}
+
+func Test_SQLiteWorkflowBackfill(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "article.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	m := migrations.NewSQLite(db)
+
+	// articles written before the workflow existed
+	_, err = m.Up(ctx)
+	assert.Nil(t, err)
+
+	_, err = m.Down(ctx, 2)
+	assert.Nil(t, err)
+
+	_, err = db.ExecContext(ctx, "INSERT INTO author (name) VALUES ('Jane')")
+	assert.Nil(t, err)
+
+	_, err = db.ExecContext(ctx, "INSERT INTO article (title, content, author, author_id, created_at) VALUES ('title', 'content', 'Jane', 1, '2023-01-01 10:00:00')")
+	assert.Nil(t, err)
+
+	_, err = m.Up(ctx)
+	assert.Nil(t, err)
+
+	// they stay visible, new articles start as drafts
+	var (
+		status      string
+		publishedAt time.Time
+	)
+
+	err = db.QueryRowContext(ctx, "SELECT status, published_at FROM article WHERE id = 1").Scan(&status, &publishedAt)
+	assert.Nil(t, err)
+	assert.Equal(t, status, "published")
+	assert.Equal(t, publishedAt.UTC(), time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC))
+
+	_, err = db.ExecContext(ctx, "INSERT INTO article (title, content, author, author_id) VALUES ('title', 'content', 'Jane', 1)")
+	assert.Nil(t, err)
+
+	err = db.QueryRowContext(ctx, "SELECT status FROM article WHERE id = 2").Scan(&status)
+	assert.Nil(t, err)
+	assert.Equal(t, status, "draft")
+
+	_, err = db.ExecContext(ctx, "UPDATE article SET status = 'deleted' WHERE id = 2")
+	assert.NotNil(t, err)
+}
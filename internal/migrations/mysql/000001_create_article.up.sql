@@ -1,10 +1,3 @@
--- create database article
-CREATE DATABASE IF NOT EXISTS article;
-
--- use created db
-USE article;
-
--- create table article
 CREATE TABLE IF NOT EXISTS article(
     id INT PRIMARY KEY AUTO_INCREMENT,
     title TEXT NOT NULL,
@@ -12,4 +5,4 @@ CREATE TABLE IF NOT EXISTS article(
     author VARCHAR(255) NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
+);
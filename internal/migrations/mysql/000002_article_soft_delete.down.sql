@@ -0,0 +1 @@
+ALTER TABLE article DROP COLUMN deleted_at;
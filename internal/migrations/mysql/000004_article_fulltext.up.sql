@@ -0,0 +1 @@
+CREATE FULLTEXT INDEX ft_article_title_content ON article (title, content);
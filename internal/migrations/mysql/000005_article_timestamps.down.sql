@@ -0,0 +1,3 @@
+ALTER TABLE article
+    MODIFY created_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+    MODIFY updated_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP;
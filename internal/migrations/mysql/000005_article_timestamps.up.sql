@@ -0,0 +1,5 @@
+UPDATE article SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE article SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE article
+    MODIFY created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    MODIFY updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;
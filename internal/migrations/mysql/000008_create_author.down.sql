@@ -0,0 +1,3 @@
+ALTER TABLE article DROP FOREIGN KEY fk_article_author;
+ALTER TABLE article DROP COLUMN author_id;
+DROP TABLE IF EXISTS author;
@@ -0,0 +1,19 @@
+-- authors are unique by name ignoring case like the article author, existing author names are deduplicated
+-- ignoring case and surrounding spaces and articles take the name of their author
+CREATE TABLE IF NOT EXISTS author(
+    id INT PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    bio TEXT NULL,
+    avatar_url VARCHAR(2048) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY author_name (name)
+);
+INSERT INTO author (name) SELECT MIN(TRIM(author)) FROM article GROUP BY LOWER(TRIM(author));
+ALTER TABLE article ADD COLUMN author_id INT NULL;
+-- updated_at tracks content changes, keep it as is
+UPDATE article SET author_id = (SELECT id FROM author WHERE author.name = TRIM(article.author)), updated_at = updated_at;
+UPDATE article SET author = (SELECT name FROM author WHERE author.id = article.author_id), updated_at = updated_at;
+ALTER TABLE article
+    MODIFY author_id INT NOT NULL,
+    ADD CONSTRAINT fk_article_author FOREIGN KEY (author_id) REFERENCES author (id);
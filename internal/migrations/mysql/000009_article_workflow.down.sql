@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS article_status_change;
+DROP INDEX idx_article_status ON article;
+ALTER TABLE article
+    DROP CHECK chk_article_status,
+    DROP COLUMN published_at,
+    DROP COLUMN status;
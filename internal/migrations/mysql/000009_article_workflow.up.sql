@@ -0,0 +1,20 @@
+-- new articles start as drafts, existing articles were visible to everyone so they stay published
+ALTER TABLE article
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'draft',
+    ADD COLUMN published_at TIMESTAMP NULL DEFAULT NULL,
+    ADD CONSTRAINT chk_article_status CHECK (status IN ('draft', 'in_review', 'published', 'archived'));
+-- updated_at tracks content changes, keep it as is
+UPDATE article SET status = 'published', published_at = created_at, updated_at = updated_at;
+CREATE INDEX idx_article_status ON article (status);
+-- review history, changes go away with their article
+CREATE TABLE IF NOT EXISTS article_status_change(
+    id INT PRIMARY KEY AUTO_INCREMENT,
+    article_id INT NOT NULL,
+    transition VARCHAR(16) NOT NULL,
+    from_status VARCHAR(16) NOT NULL,
+    to_status VARCHAR(16) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    comment TEXT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_status_change_article FOREIGN KEY (article_id) REFERENCES article (id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP INDEX idx_author_subject ON author;
+ALTER TABLE author DROP COLUMN subject;
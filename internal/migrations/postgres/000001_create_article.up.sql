@@ -0,0 +1,11 @@
+-- citext compares title and author case insensitively like the MySQL collation,
+-- created_at and updated_at are NOT NULL from the start as there are no older rows to backfill
+CREATE EXTENSION IF NOT EXISTS citext;
+CREATE TABLE IF NOT EXISTS article(
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    title CITEXT NOT NULL,
+    content TEXT NOT NULL,
+    author CITEXT NOT NULL CHECK (char_length(author::text) <= 255),
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
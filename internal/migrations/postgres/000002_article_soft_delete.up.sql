@@ -0,0 +1 @@
+ALTER TABLE article ADD COLUMN deleted_at TIMESTAMP(0) NULL DEFAULT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX idx_article_updated_at;
+DROP INDEX idx_article_created_at;
+DROP INDEX idx_article_author;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS ft_article_search;
+ALTER TABLE article DROP COLUMN search;
@@ -0,0 +1,5 @@
+-- title is weighted A and content B, simple configuration lower cases words without stemming like search.Tokenize
+ALTER TABLE article ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title::text), 'A') || setweight(to_tsvector('simple', content), 'B')
+) STORED;
+CREATE INDEX ft_article_search ON article USING GIN (search);
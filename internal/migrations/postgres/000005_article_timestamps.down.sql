@@ -0,0 +1 @@
+-- nothing to roll back, see 000005_article_timestamps.up.sql
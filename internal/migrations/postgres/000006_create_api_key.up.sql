@@ -0,0 +1,12 @@
+-- keys are looked up by prefix, only the sha256 of their secret is stored
+CREATE TABLE IF NOT EXISTS api_key(
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(32) NOT NULL UNIQUE,
+    hash CHAR(64) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP(0),
+    last_used_at TIMESTAMP(0),
+    revoked_at TIMESTAMP(0)
+);
@@ -0,0 +1,2 @@
+DROP INDEX idx_article_owner_id;
+ALTER TABLE article DROP COLUMN owner_id;
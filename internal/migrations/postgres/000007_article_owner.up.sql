@@ -0,0 +1,3 @@
+-- articles created before ownership keep a NULL owner, only editors and admins may change them
+ALTER TABLE article ADD COLUMN owner_id VARCHAR(255) NULL DEFAULT NULL;
+CREATE INDEX idx_article_owner_id ON article (owner_id);
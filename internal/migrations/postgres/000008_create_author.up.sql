@@ -0,0 +1,15 @@
+-- authors are unique by name ignoring case like the article author, existing author names are deduplicated
+-- ignoring case and surrounding spaces and articles take the name of their author
+CREATE TABLE IF NOT EXISTS author(
+    id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name CITEXT NOT NULL UNIQUE CHECK (char_length(name::text) <= 255),
+    bio TEXT,
+    avatar_url VARCHAR(2048),
+    created_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO author (name) SELECT MIN(TRIM(author::text)) FROM article GROUP BY LOWER(TRIM(author::text));
+ALTER TABLE article ADD COLUMN author_id INT REFERENCES author (id);
+UPDATE article SET author_id = au.id, author = au.name FROM author AS au WHERE au.name = TRIM(article.author::text)::citext;
+ALTER TABLE article ALTER COLUMN author_id SET NOT NULL;
+CREATE INDEX idx_article_author_id ON article (author_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS article_status_change;
+DROP INDEX idx_article_status;
+ALTER TABLE article
+    DROP COLUMN published_at,
+    DROP COLUMN status;
@@ -0,0 +1,9 @@
+-- sqlite cannot alter columns later, created_at and updated_at are NOT NULL from the start
+CREATE TABLE IF NOT EXISTS article(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL COLLATE NOCASE,
+    content TEXT NOT NULL,
+    author VARCHAR(255) NOT NULL COLLATE NOCASE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+CREATE INDEX idx_article_author ON article (author);
+CREATE INDEX idx_article_created_at ON article (created_at);
+CREATE INDEX idx_article_updated_at ON article (updated_at);
@@ -0,0 +1,6 @@
+-- external content table indexing title and content of article, triggers keep it in sync
+CREATE VIRTUAL TABLE article_fts USING fts5(title, content, content='article', content_rowid='id');
+CREATE TRIGGER article_fts_insert AFTER INSERT ON article BEGIN INSERT INTO article_fts(rowid, title, content) VALUES (new.id, new.title, new.content); END;
+CREATE TRIGGER article_fts_delete AFTER DELETE ON article BEGIN INSERT INTO article_fts(article_fts, rowid, title, content) VALUES ('delete', old.id, old.title, old.content); END;
+CREATE TRIGGER article_fts_update AFTER UPDATE OF title, content ON article BEGIN INSERT INTO article_fts(article_fts, rowid, title, content) VALUES ('delete', old.id, old.title, old.content); INSERT INTO article_fts(rowid, title, content) VALUES (new.id, new.title, new.content); END;
+INSERT INTO article_fts(article_fts) VALUES ('rebuild');
@@ -0,0 +1,3 @@
+-- columns are NOT NULL since 000001, the store sets updated_at as sqlite has no ON UPDATE
+UPDATE article SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE article SET updated_at = created_at WHERE updated_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX idx_article_author_id;
+ALTER TABLE article DROP COLUMN author_id;
+DROP TABLE IF EXISTS author;
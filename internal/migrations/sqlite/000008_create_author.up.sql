@@ -0,0 +1,16 @@
+-- authors are unique by name ignoring case like the article author, existing author names are deduplicated
+-- ignoring case and surrounding spaces and articles take the name of their author
+CREATE TABLE IF NOT EXISTS author(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL COLLATE NOCASE UNIQUE,
+    bio TEXT,
+    avatar_url VARCHAR(2048),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO author (name) SELECT MIN(TRIM(author)) FROM article GROUP BY LOWER(TRIM(author));
+-- sqlite cannot make an added column NOT NULL, the store always sets author_id
+ALTER TABLE article ADD COLUMN author_id INTEGER NULL DEFAULT NULL REFERENCES author (id);
+UPDATE article SET author_id = (SELECT id FROM author WHERE author.name = TRIM(article.author));
+UPDATE article SET author = (SELECT name FROM author WHERE author.id = article.author_id);
+CREATE INDEX idx_article_author_id ON article (author_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS article_status_change;
+DROP INDEX idx_article_status;
+ALTER TABLE article DROP COLUMN published_at;
+ALTER TABLE article DROP COLUMN status;
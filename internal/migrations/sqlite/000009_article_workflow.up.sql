@@ -0,0 +1,17 @@
+-- new articles start as drafts, existing articles were visible to everyone so they stay published
+ALTER TABLE article ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'in_review', 'published', 'archived'));
+ALTER TABLE article ADD COLUMN published_at TIMESTAMP NULL DEFAULT NULL;
+UPDATE article SET status = 'published', published_at = created_at;
+CREATE INDEX idx_article_status ON article (status);
+-- review history, changes go away with their article
+CREATE TABLE IF NOT EXISTS article_status_change(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    article_id INTEGER NOT NULL REFERENCES article (id) ON DELETE CASCADE,
+    transition VARCHAR(16) NOT NULL,
+    from_status VARCHAR(16) NOT NULL,
+    to_status VARCHAR(16) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    comment TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_status_change_article_id ON article_status_change (article_id);
@@ -0,0 +1,2 @@
+DROP INDEX idx_author_subject;
+ALTER TABLE author DROP COLUMN subject;
@@ -0,0 +1,3 @@
+-- subject links an author to the principal writing as it, authors created by editors have none
+ALTER TABLE author ADD COLUMN subject VARCHAR(255) NULL DEFAULT NULL;
+CREATE UNIQUE INDEX idx_author_subject ON author (subject);
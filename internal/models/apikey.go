@@ -0,0 +1,238 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+type apiKey struct {
+	app     *Application
+	dialect dialect
+}
+
+// APIKeyStore holds API keys of clients such as batch jobs
+type APIKeyStore interface {
+	Create(ctx context.Context, key *APIKey) (int64, error)
+	GetByID(ctx context.Context, keyID int) (*APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	List(ctx context.Context) ([]*APIKey, error)
+	Revoke(ctx context.Context, keyID int, at time.Time) error
+	SetExpiry(ctx context.Context, keyID int, at time.Time) error
+	Touch(ctx context.Context, keyID int, at time.Time) error
+}
+
+// APIKey holds API key fields, the secret of a key is never stored, only its hash
+type APIKey struct {
+	ID int
+
+	// Name tells what the key is used by
+	Name string
+
+	// Prefix is the public part of the key it is looked up by, Hash is the hex sha256 of its secret
+	Prefix string
+	Hash   string
+
+	Scopes []string
+
+	// ExpiresAt, LastUsedAt and RevokedAt are nil when the key does not expire, was not used or is not revoked
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// ErrAPIKeyNotFound is returned when no API key matches
+var ErrAPIKeyNotFound = newError(ErrNotFound, "api key not found")
+
+// maxScopesLength is the size of scopes column
+const maxScopesLength = 255
+
+// validate checks key fields against constraints of api_key table
+func (key *APIKey) validate() error {
+	err := validateField("name", key.Name, maxAuthorLength)
+	if err != nil {
+		return err
+	}
+
+	err = validateField("prefix", key.Prefix, 32)
+	if err != nil {
+		return err
+	}
+
+	err = validateField("hash", key.Hash, 64)
+	if err != nil {
+		return err
+	}
+
+	return validateField("scopes", strings.Join(key.Scopes, " "), maxScopesLength)
+}
+
+// Create stores key and returns its id, CreatedAt is set when zero
+func (k *apiKey) Create(ctx context.Context, key *APIKey) (int64, error) {
+	err := key.validate()
+	if err != nil {
+		return 0, err
+	}
+
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+
+	key.CreatedAt = key.CreatedAt.UTC().Truncate(time.Second)
+
+	query := `INSERT INTO api_key (name, prefix, hash, scopes, created_at, expires_at) VALUES(?, ?, ?, ?, ?, ?)`
+	args := []interface{}{key.Name, key.Prefix, key.Hash, strings.Join(key.Scopes, " "), k.dialect.timeArg(key.CreatedAt), k.nullTime(key.ExpiresAt)}
+
+	var id int64
+
+	if k.dialect.returningID {
+		err = k.app.db.QueryRowContext(ctx, k.dialect.statement(ctx, query+" RETURNING id"), args...).Scan(&id)
+		if err != nil {
+			return 0, storeError(err)
+		}
+	} else {
+		res, err := k.app.db.ExecContext(ctx, k.dialect.statement(ctx, query), args...)
+		if err != nil {
+			return 0, storeError(err)
+		}
+
+		id, err = res.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	key.ID = int(id)
+
+	return id, nil
+}
+
+// apiKeyColumns are selected by queries scanned with scanAPIKey
+const apiKeyColumns = `id, name, prefix, hash, scopes, created_at, expires_at, last_used_at, revoked_at`
+
+// GetByID fetches key by keyID
+func (k *apiKey) GetByID(ctx context.Context, keyID int) (*APIKey, error) {
+	return k.get(ctx, `SELECT `+apiKeyColumns+` FROM api_key WHERE id=?`, keyID)
+}
+
+// GetByPrefix fetches key by its public prefix
+func (k *apiKey) GetByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
+	return k.get(ctx, `SELECT `+apiKeyColumns+` FROM api_key WHERE prefix=?`, prefix)
+}
+
+// get fetches the key selected by query, ErrAPIKeyNotFound is returned when there is none
+func (k *apiKey) get(ctx context.Context, query string, args ...interface{}) (*APIKey, error) {
+	key, err := scanAPIKey(k.app.db.QueryRowContext(ctx, k.dialect.statement(ctx, query), args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	return key, err
+}
+
+// List fetches all keys including revoked and expired ones ordered by id
+func (k *apiKey) List(ctx context.Context) ([]*APIKey, error) {
+	rows, err := k.app.db.QueryContext(ctx, k.dialect.statement(ctx, `SELECT `+apiKeyColumns+` FROM api_key ORDER BY id`))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var keys []*APIKey
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Revoke revokes key at given time, revoking a revoked key keeps the first revocation time
+func (k *apiKey) Revoke(ctx context.Context, keyID int, at time.Time) error {
+	return k.exec(ctx, `UPDATE api_key SET revoked_at=COALESCE(revoked_at, ?) WHERE id=?`, k.dialect.timeArg(at), keyID)
+}
+
+// SetExpiry makes key expire at given time
+func (k *apiKey) SetExpiry(ctx context.Context, keyID int, at time.Time) error {
+	return k.exec(ctx, `UPDATE api_key SET expires_at=? WHERE id=?`, k.dialect.timeArg(at), keyID)
+}
+
+// Touch records key was used at given time
+func (k *apiKey) Touch(ctx context.Context, keyID int, at time.Time) error {
+	return k.exec(ctx, `UPDATE api_key SET last_used_at=? WHERE id=?`, k.dialect.timeArg(at), keyID)
+}
+
+// exec runs a write query on a single key, ErrAPIKeyNotFound is returned when no row matches
+func (k *apiKey) exec(ctx context.Context, query string, args ...interface{}) error {
+	res, err := k.app.db.ExecContext(ctx, k.dialect.statement(ctx, query), args...)
+	if err != nil {
+		return storeError(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// nullTime returns query argument of t, nil is stored as NULL
+func (k *apiKey) nullTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+
+	return k.dialect.timeArg(*t)
+}
+
+// scanner is implemented by sql.Row and sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAPIKey scans a row of apiKeyColumns
+func scanAPIKey(row scanner) (*APIKey, error) {
+	var (
+		key                            APIKey
+		scopes                         string
+		expiresAt, lastUsedAt, revoked sql.NullTime
+	)
+
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Hash, &scopes, &key.CreatedAt, &expiresAt, &lastUsedAt, &revoked)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = strings.Fields(scopes)
+	key.CreatedAt = key.CreatedAt.UTC()
+	key.ExpiresAt = timePtr(expiresAt)
+	key.LastUsedAt = timePtr(lastUsedAt)
+	key.RevokedAt = timePtr(revoked)
+
+	return &key, nil
+}
+
+// timePtr returns time of t in UTC, nil when t is NULL
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	utc := t.Time.UTC()
+
+	return &utc
+}
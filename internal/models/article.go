@@ -1,79 +1,241 @@
 package models
 
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
 type article struct {
-	app *Application
+	app     *Application
+	dialect dialect
 }
 
 // ArticleStore holds all method
 type ArticleStore interface {
-	Store(article *Article) (int64, error)
-	GetByID(articleID int) (*Article, error)
-	GetAll() ([]*Article, error)
+	Store(ctx context.Context, article *Article) (int64, error)
+	GetByID(ctx context.Context, articleID int, includeDeleted bool) (*Article, error)
+	List(ctx context.Context, opts ListOptions) ([]*Article, error)
+	Count(ctx context.Context, opts ListOptions) (int64, error)
+	Search(ctx context.Context, opts SearchOptions) ([]*SearchResult, error)
+	CountSearch(ctx context.Context, opts SearchOptions) (int64, error)
+	Update(ctx context.Context, article *Article) error
+	Patch(ctx context.Context, articleID int, patch *ArticlePatch) error
+	Delete(ctx context.Context, articleID int) error
+	Restore(ctx context.Context, articleID int) error
+	Purge(ctx context.Context, articleID int) error
+	Transition(ctx context.Context, articleID int, change *StatusChange) error
+	History(ctx context.Context, articleID int) ([]*StatusChange, error)
 }
 
-// Article holds article fields
+// Article holds article fields, Author is the name of the author of AuthorID
 type Article struct {
-	ID      int    `db:"id"`
-	Title   string `db:"title"`
-	Content string `db:"content"`
-	Author  string `db:"author"`
+	ID        int       `db:"id"`
+	Title     string    `db:"title"`
+	Content   string    `db:"content"`
+	Author    string    `db:"author"`
+	AuthorID  int       `db:"author_id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Deleted   bool      `db:"deleted"`
+
+	// OwnerID is the subject of the principal who created the article, articles created before ownership have none
+	OwnerID string `db:"owner_id"`
+
+	// Status is the editorial state, PublishedAt is set when the article is published
+	Status      Status     `db:"status"`
+	PublishedAt *time.Time `db:"published_at"`
+
+	// CreateAuthor lets Store and Update create the author when none has the name, it is not stored
+	CreateAuthor bool `db:"-"`
+
+	// AuthorSubject writes the article as the author linked to the subject whatever its name, the author named
+	// Author is linked or created when there is none. It is not stored
+	AuthorSubject string `db:"-"`
 }
 
-// Store used to store article in database
-func (a *article) Store(article *Article) (lastInsertedID int64, err error) {
-	// prepare query to insert record
-	query := `INSERT INTO article (title, content, author) 
-		VALUES(?, ?, ?)`
+// articleColumns are selected by queries scanned into articleFields
+const articleColumns = `id, title, content, author, created_at, updated_at, deleted_at IS NOT NULL AS deleted, COALESCE(owner_id, '') AS owner_id, COALESCE(author_id, 0) AS author_id, status, published_at`
+
+// articleFields returns scan destinations of articleColumns
+func articleFields(article *Article) []interface{} {
+	return []interface{}{&article.ID, &article.Title, &article.Content, &article.Author, &article.CreatedAt, &article.UpdatedAt,
+		&article.Deleted, &article.OwnerID, &article.AuthorID, &article.Status, &article.PublishedAt}
+}
+
+// ArticlePatch holds article fields for partial update, nil fields are left unchanged
+type ArticlePatch struct {
+	Title   *string
+	Content *string
+	Author  *string
+
+	// CreateAuthor lets Patch create the author when none has the name
+	CreateAuthor bool
+
+	// AuthorSubject writes the article as the author linked to the subject like Article.AuthorSubject
+	AuthorSubject string
+}
+
+// maxAuthorLength is the size of author column
+const maxAuthorLength = 255
+
+// validate checks article fields against constraints of article table
+func (article *Article) validate() error {
+	err := validateField("title", article.Title, 0)
+	if err != nil {
+		return err
+	}
+
+	err = validateField("content", article.Content, 0)
+	if err != nil {
+		return err
+	}
+
+	return validateField("author", article.Author, maxAuthorLength)
+}
+
+// validateField checks a required text field is set and fits max characters, zero max is unbounded
+func validateField(name, value string, max int) error {
+	if value == "" {
+		return newError(ErrValidation, "%s is required", name)
+	}
+
+	if max > 0 && utf8.RuneCountInString(value) > max {
+		return newError(ErrValidation, "%s must be at most %d characters", name, max)
+	}
 
-	// execute query
-	res, err := a.app.db.Exec(query, article.Title, article.Content, article.Author)
+	return nil
+}
+
+// Store used to store article in database
+func (a *article) Store(ctx context.Context, article *Article) (lastInsertedID int64, err error) {
+	err = article.validate()
 	if err != nil {
 		return lastInsertedID, err
 	}
 
-	// get last inserted record ID
-	lastInsertedID, err = res.LastInsertId()
+	// the author is looked up by name ignoring case in the transaction inserting the article
+	err = a.withAuthor(ctx, authorRef{article.Author, article.AuthorSubject, article.CreateAuthor}, func(tx *sql.Tx, author *Author) error {
+		article.Author, article.AuthorID = author.Name, author.ID
+
+		// prepare query to insert record, articles without owner store NULL
+		query := `INSERT INTO article (title, content, author, author_id, owner_id) 
+		VALUES(?, ?, ?, ?, ?)`
+		args := []interface{}{article.Title, article.Content, article.Author, article.AuthorID, nullString(article.OwnerID)}
+
+		// drivers without LastInsertId return the id from the insert
+		if a.dialect.returningID {
+			return storeError(tx.QueryRowContext(ctx, a.dialect.statement(ctx, query+" RETURNING id"), args...).
+				Scan(&lastInsertedID))
+		}
+
+		// execute query
+		res, err := tx.ExecContext(ctx, a.dialect.statement(ctx, query), args...)
+		if err != nil {
+			return storeError(err)
+		}
+
+		// get last inserted record ID
+		lastInsertedID, err = res.LastInsertId()
+
+		return err
+	})
 
 	return lastInsertedID, err
 }
 
-// GetByID fetches article by articleID
-func (a *article) GetByID(articleID int) (*Article, error) {
-	query := `SELECT id, title, content, author FROM article  
+// GetByID fetches article by articleID, soft deleted articles are skipped unless includeDeleted is set.
+// ErrArticleNotFound is returned when no article matches
+func (a *article) GetByID(ctx context.Context, articleID int, includeDeleted bool) (*Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM article  
 		WHERE id=?`
 
-	row, err := a.app.db.Query(query, articleID)
-	if err != nil {
-		return nil, err
+	if !includeDeleted {
+		query += ` AND deleted_at IS NULL`
 	}
 
 	var article Article
 
-	for row.Next() {
-		err = row.Scan(&article.ID, &article.Title, &article.Content, &article.Author)
-		if err != nil {
-			return nil, err
-		}
+	err := a.app.db.QueryRowContext(ctx, a.dialect.statement(ctx, query), articleID).
+		Scan(articleFields(&article)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrArticleNotFound
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
 	return &article, nil
 }
 
-// GetAll fetches all articles
-func (a *article) GetAll() ([]*Article, error) {
-	query := `SELECT id, title, content, author FROM article`
+// List fetches articles matching opts in sort order, a page is selected using offset or keyset cursor
+func (a *article) List(ctx context.Context, opts ListOptions) ([]*Article, error) {
+	conditions, args := opts.conditions(a.dialect)
+	fields := opts.orderBy()
+
+	// keyset cursors
+	if opts.After != nil {
+		condition, cursorArgs := keyset(fields, opts.After)
+		conditions = append(conditions, condition)
+		args = append(args, cursorArgs...)
+	}
+
+	if opts.Before != nil {
+		// read backwards from cursor, rows are reversed after scanning
+		fields = reverse(fields)
+
+		condition, cursorArgs := keyset(fields, opts.Before)
+		conditions = append(conditions, condition)
+		args = append(args, cursorArgs...)
+	}
+
+	query := `SELECT ` + articleColumns + ` FROM article`
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var order []string
+
+	for _, field := range fields {
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+
+		order = append(order, SortColumns[field.Field]+" "+direction)
+	}
+
+	query += " ORDER BY " + strings.Join(order, ", ")
 
-	row, err := a.app.db.Query(query)
+	if opts.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, opts.Limit)
+
+		if opts.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, opts.Offset)
+		}
+	}
+
+	row, err := a.app.db.QueryContext(ctx, a.dialect.statement(ctx, query), args...)
 	if err != nil {
 		return nil, err
 	}
 
+	defer row.Close()
+
 	var articles []*Article
 
 	for row.Next() {
 		var article Article
 
-		err = row.Scan(&article.ID, &article.Title, &article.Content, &article.Author)
+		err = row.Scan(articleFields(&article)...)
 		if err != nil {
 			return nil, err
 		}
@@ -81,5 +243,209 @@ func (a *article) GetAll() ([]*Article, error) {
 		articles = append(articles, &article)
 	}
 
+	// error which ended iteration early
+	err = row.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Before != nil {
+		for i, j := 0, len(articles)-1; i < j; i, j = i+1, j-1 {
+			articles[i], articles[j] = articles[j], articles[i]
+		}
+	}
+
 	return articles, nil
 }
+
+// Count counts articles matching opts, sort and pagination fields are ignored
+func (a *article) Count(ctx context.Context, opts ListOptions) (int64, error) {
+	conditions, args := opts.conditions(a.dialect)
+
+	query := `SELECT COUNT(*) FROM article`
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+
+	err := a.app.db.QueryRowContext(ctx, a.dialect.statement(ctx, query), args...).Scan(&total)
+
+	return total, err
+}
+
+// Update replaces all editable fields of an article
+func (a *article) Update(ctx context.Context, article *Article) error {
+	err := article.validate()
+	if err != nil {
+		return err
+	}
+
+	return a.withAuthor(ctx, authorRef{article.Author, article.AuthorSubject, article.CreateAuthor}, func(tx *sql.Tx, author *Author) error {
+		article.Author, article.AuthorID = author.Name, author.ID
+
+		query := `UPDATE article SET title=?, content=?, author=?, author_id=?, updated_at=CURRENT_TIMESTAMP
+		WHERE id=? AND deleted_at IS NULL`
+
+		return a.execOn(ctx, tx, query, article.Title, article.Content, article.Author, article.AuthorID, article.ID)
+	})
+}
+
+// Patch updates only the fields set in patch
+func (a *article) Patch(ctx context.Context, articleID int, patch *ArticlePatch) error {
+	var (
+		columns []string
+		args    []interface{}
+	)
+
+	if patch.Title != nil {
+		err := validateField("title", *patch.Title, 0)
+		if err != nil {
+			return err
+		}
+
+		columns = append(columns, "title=?")
+		args = append(args, *patch.Title)
+	}
+
+	if patch.Content != nil {
+		err := validateField("content", *patch.Content, 0)
+		if err != nil {
+			return err
+		}
+
+		columns = append(columns, "content=?")
+		args = append(args, *patch.Content)
+	}
+
+	if patch.Author != nil {
+		err := validateField("author", *patch.Author, maxAuthorLength)
+		if err != nil {
+			return err
+		}
+	}
+
+	// nothing to update, article must still exist
+	if len(columns) == 0 && patch.Author == nil {
+		_, err := a.GetByID(ctx, articleID, false)
+
+		return err
+	}
+
+	update := func(q querier, columns []string, args []interface{}) error {
+		columns = append(columns, "updated_at=CURRENT_TIMESTAMP")
+		args = append(args, articleID)
+
+		query := fmt.Sprintf(`UPDATE article SET %s WHERE id=? AND deleted_at IS NULL`, strings.Join(columns, ", "))
+
+		return a.execOn(ctx, q, query, args...)
+	}
+
+	if patch.Author == nil {
+		return update(a.app.db, columns, args)
+	}
+
+	return a.withAuthor(ctx, authorRef{*patch.Author, patch.AuthorSubject, patch.CreateAuthor}, func(tx *sql.Tx, author *Author) error {
+		return update(tx, append(columns, "author=?", "author_id=?"), append(args, author.Name, author.ID))
+	})
+}
+
+// Delete soft deletes an article by setting deleted_at
+func (a *article) Delete(ctx context.Context, articleID int) error {
+	// updated_at tracks content changes, keep it as is
+	query := `UPDATE article SET deleted_at=CURRENT_TIMESTAMP, updated_at=updated_at
+		WHERE id=? AND deleted_at IS NULL`
+
+	return a.exec(ctx, query, articleID)
+}
+
+// Restore undoes a soft delete
+func (a *article) Restore(ctx context.Context, articleID int) error {
+	// updated_at tracks content changes, keep it as is
+	query := `UPDATE article SET deleted_at=NULL, updated_at=updated_at WHERE id=? AND deleted_at IS NOT NULL`
+
+	err := a.exec(ctx, query, articleID)
+	if !errors.Is(err, ErrArticleNotFound) {
+		return err
+	}
+
+	// no deleted article matched, tell a live article from a missing one
+	_, err = a.GetByID(ctx, articleID, false)
+	if err != nil {
+		return err
+	}
+
+	return ErrArticleNotDeleted
+}
+
+// Purge permanently removes an article
+func (a *article) Purge(ctx context.Context, articleID int) error {
+	query := `DELETE FROM article WHERE id=?`
+
+	return a.exec(ctx, query, articleID)
+}
+
+// authors returns the author store sharing the database of a
+func (a *article) authors() *author {
+	return &author{app: a.app, dialect: a.dialect}
+}
+
+// withAuthor runs write in a transaction with the author of ref
+// and there is none. Articles take the stored spelling of the name. The transaction is retried once when
+// another request created the author meanwhile, so the retry finds it
+func (a *article) withAuthor(ctx context.Context, ref authorRef, write func(tx *sql.Tx, author *Author) error) error {
+	err := a.writeWithAuthor(ctx, ref, write)
+	if errors.Is(err, errAuthorRace) {
+		err = a.writeWithAuthor(ctx, ref, write)
+	}
+
+	return err
+}
+
+// writeWithAuthor resolves the author of ref and runs write in one transaction
+func (a *article) writeWithAuthor(ctx context.Context, ref authorRef, write func(tx *sql.Tx, author *Author) error) error {
+	tx, err := a.app.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	author, err := a.authors().resolve(ctx, tx, ref)
+	if err != nil {
+		return err
+	}
+
+	err = write(tx, author)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// exec runs a write query on a single article, ErrArticleNotFound is returned when no row matches.
+// drivers report matched rows (clientFoundRows in MySQL) so writes leaving a row unchanged still count
+func (a *article) exec(ctx context.Context, query string, args ...interface{}) error {
+	return a.execOn(ctx, a.app.db, query, args...)
+}
+
+// execOn runs exec using q
+func (a *article) execOn(ctx context.Context, q querier, query string, args ...interface{}) error {
+	res, err := q.ExecContext(ctx, a.dialect.statement(ctx, query), args...)
+	if err != nil {
+		return storeError(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrArticleNotFound
+	}
+
+	return nil
+}
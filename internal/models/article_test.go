@@ -4,24 +4,32 @@ import (
 	"article/internal/handler"
 	"article/internal/models"
 	"article/internal/response"
+	"context"
 	"database/sql"
 	"errors"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_Store(t *testing.T) {
+	valid := models.Article{Title: "Test title", Content: "Test content", Author: "Test author"}
+	dbErr := errors.New("db error")
 
 	tests := []struct {
-		name         string
-		mockDB       func() *sql.DB
-		wantResp     handler.ArticleResponse
-		wantRespBody response.Body
+		name    string
+		article models.Article
+		mockDB  func() *sql.DB
+		wantErr error
 	}{
 		{
-			name: "success",
+			name:    "success",
+			article: valid,
 			mockDB: func() *sql.DB {
 				// create sql mock database connection
 				db, mock, err := sqlmock.New()
@@ -30,13 +38,56 @@ func Test_Store(t *testing.T) {
 				}
 
 				// mock expected query
+				mock.ExpectBegin()
+				expectAuthor(mock, "Test author", 1)
 				mock.ExpectExec("INSERT INTO article").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 
 				return db
 			},
 		},
 		{
-			name: "error",
+			name:    "success : author created on first use",
+			article: models.Article{Title: "Test title", Content: "Test content", Author: "Test author", CreateAuthor: true},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock expected queries
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id, name").WithArgs("Test author").WillReturnRows(sqlmock.NewRows(authorColumns))
+				mock.ExpectExec("INSERT INTO author").WithArgs("Test author", nil, nil, nil).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec("INSERT INTO article").WithArgs("Test title", "Test content", "Test author", 3, nil).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+
+				return db
+			},
+		},
+		{
+			name:    "error : unknown author",
+			article: valid,
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock expected queries, no author is created without CreateAuthor
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id, name").WithArgs("Test author").WillReturnRows(sqlmock.NewRows(authorColumns))
+				mock.ExpectRollback()
+
+				return db
+			},
+			wantErr: models.ErrUnknownAuthor,
+		},
+		{
+			name:    "error",
+			article: valid,
 			mockDB: func() *sql.DB {
 				// create sql mock database connection
 				db, mock, err := sqlmock.New()
@@ -45,10 +96,82 @@ func Test_Store(t *testing.T) {
 				}
 
 				// mock expected query
-				mock.ExpectExec("INSERT INTO article").WillReturnError(errors.New("db error"))
+				mock.ExpectBegin()
+				expectAuthor(mock, "Test author", 1)
+				mock.ExpectExec("INSERT INTO article").WillReturnError(dbErr)
+				mock.ExpectRollback()
+
+				return db
+			},
+			wantErr: dbErr,
+		},
+		{
+			name:    "error : duplicate entry",
+			article: valid,
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock expected query
+				mock.ExpectBegin()
+				expectAuthor(mock, "Test author", 1)
+				mock.ExpectExec("INSERT INTO article").WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+				mock.ExpectRollback()
+
+				return db
+			},
+			wantErr: models.ErrConflict,
+		},
+		{
+			name:    "error : data too long",
+			article: valid,
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock expected query
+				mock.ExpectBegin()
+				expectAuthor(mock, "Test author", 1)
+				mock.ExpectExec("INSERT INTO article").WillReturnError(&mysql.MySQLError{Number: 1406, Message: "Data too long for column 'title'"})
+				mock.ExpectRollback()
+
+				return db
+			},
+			wantErr: models.ErrValidation,
+		},
+		{
+			name:    "error : missing title",
+			article: models.Article{Content: "Test content", Author: "Test author"},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection without expectations
+				db, _, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
 
 				return db
 			},
+			wantErr: models.ErrValidation,
+		},
+		{
+			name:    "error : author too long",
+			article: models.Article{Title: "Test title", Content: "Test content", Author: strings.Repeat("a", 256)},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection without expectations
+				db, _, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				return db
+			},
+			wantErr: models.ErrValidation,
 		},
 	}
 
@@ -60,10 +183,9 @@ func Test_Store(t *testing.T) {
 			a := models.NewModels(db)
 
 			// call model function
-			gotID, err := a.Article.Store(&models.Article{})
-			if err != nil {
-				assert.NotNil(t, err)
-				assert.Equal(t, err.Error(), "db error")
+			gotID, err := a.Article.Store(context.Background(), &tt.article)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
 				assert.Nil(t, err)
 				assert.Equal(t, gotID, int64(1))
@@ -73,7 +195,32 @@ func Test_Store(t *testing.T) {
 
 }
 
+func Test_StoreErrorMessage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection %v", err)
+	}
+
+	driverErr := &mysql.MySQLError{Number: 1406, Message: "Data too long for column 'title' at row 1"}
+
+	mock.ExpectBegin()
+	expectAuthor(mock, "Test author", 1)
+	mock.ExpectExec("INSERT INTO article").WillReturnError(driverErr)
+	mock.ExpectRollback()
+
+	_, err = models.NewModels(db).Article.Store(context.Background(), &models.Article{Title: "Test title", Content: "Test content", Author: "Test author"})
+
+	// clients get a fixed message, the driver error is kept for the server log
+	var storeErr *models.Error
+	if assert.ErrorAs(t, err, &storeErr) {
+		assert.Equal(t, storeErr.Message, "invalid value")
+		assert.Equal(t, storeErr.Cause, error(driverErr))
+	}
+}
+
 func Test_GetByID(t *testing.T) {
+	createdAt := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name         string
@@ -91,12 +238,12 @@ func Test_GetByID(t *testing.T) {
 				}
 
 				// mock return valid rows
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "author"}).AddRow(int64(1), "Test title", "Test content", "Test author")
-				mock.ExpectQuery("SELECT id, title, content, author FROM article").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "created_at", "updated_at", "deleted", "owner_id", "author_id", "status", "published_at"}).AddRow(int64(1), "Test title", "Test content", "Test author", createdAt, updatedAt, false, "", int64(1), "published", nil)
+				mock.ExpectQuery("SELECT id, title, content, author, created_at, updated_at, deleted_at IS NOT NULL AS deleted, COALESCE\\(owner_id, ''\\) AS owner_id, COALESCE\\(author_id, 0\\) AS author_id, status, published_at FROM article").WillReturnRows(rows)
 
 				return db
 			},
-			wantResp: handler.ArticleResponse{ID: 1, Title: "Test title", Content: "Test content", Author: "Test author"},
+			wantResp: handler.ArticleResponse{ID: 1, Title: "Test title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test author"}},
 		},
 		{
 			name: "error : select query error",
@@ -108,7 +255,7 @@ func Test_GetByID(t *testing.T) {
 				}
 
 				// mock return error
-				mock.ExpectQuery("SELECT id, title, content, author FROM article").WillReturnError(errors.New("db error"))
+				mock.ExpectQuery("SELECT id, title, content, author, created_at, updated_at, deleted_at IS NOT NULL AS deleted, COALESCE\\(owner_id, ''\\) AS owner_id, COALESCE\\(author_id, 0\\) AS author_id, status, published_at FROM article").WillReturnError(errors.New("db error"))
 
 				return db
 			},
@@ -123,7 +270,7 @@ func Test_GetByID(t *testing.T) {
 			a := models.NewModels(db)
 
 			// call model function
-			gotResp, err := a.Article.GetByID(int(tt.wantResp.ID))
+			gotResp, err := a.Article.GetByID(context.Background(), int(tt.wantResp.ID), false)
 			if err != nil {
 				assert.NotNil(t, err)
 				assert.Equal(t, err.Error(), "db error")
@@ -132,17 +279,61 @@ func Test_GetByID(t *testing.T) {
 				assert.Equal(t, gotResp.ID, int(tt.wantResp.ID))
 				assert.Equal(t, gotResp.Title, tt.wantResp.Title)
 				assert.Equal(t, gotResp.Content, tt.wantResp.Content)
-				assert.Equal(t, gotResp.Author, tt.wantResp.Author)
+				assert.Equal(t, gotResp.Author, tt.wantResp.Author.Name)
+				assert.Equal(t, gotResp.CreatedAt, createdAt)
+				assert.Equal(t, gotResp.UpdatedAt, updatedAt)
 			}
 		})
 	}
 
 }
 
-func Test_GetAll(t *testing.T) {
+func Test_GetByIDNotFound(t *testing.T) {
+	// create sql mock database connection
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection %v", err)
+	}
+
+	// mock no matching row
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "created_at", "updated_at", "deleted", "owner_id", "author_id", "status", "published_at"})
+	mock.ExpectQuery("SELECT id, title").WithArgs(1).WillReturnRows(rows)
+
+	// store mocked db object in models
+	a := models.NewModels(db)
+
+	gotResp, err := a.Article.GetByID(context.Background(), 1, false)
+	assert.Nil(t, gotResp)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+	assert.Equal(t, err.Error(), "article not found")
+}
+
+func Test_GetByIDCanceled(t *testing.T) {
+	// create sql mock database connection
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection %v", err)
+	}
+
+	// store mocked db object in models
+	a := models.NewModels(db)
+
+	// query is not run once client has gone away
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = a.Article.GetByID(ctx, 1, false)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_List(t *testing.T) {
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name         string
+		opts         models.ListOptions
 		mockDB       func() *sql.DB
 		wantResp     []handler.ArticleResponse
 		wantRespBody response.Body
@@ -157,8 +348,8 @@ func Test_GetAll(t *testing.T) {
 				}
 
 				// mock return valid rows
-				rows := sqlmock.NewRows([]string{"id", "title", "content", "author"}).AddRow(int64(1), "Test title", "Test content", "Test author")
-				mock.ExpectQuery("SELECT id, title, content, author FROM article").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "created_at", "updated_at", "deleted", "owner_id", "author_id", "status", "published_at"}).AddRow(int64(1), "Test title", "Test content", "Test author", createdAt, updatedAt, false, "", int64(1), "published", nil)
+				mock.ExpectQuery("SELECT id, title, content, author, created_at, updated_at, deleted_at IS NOT NULL AS deleted, COALESCE\\(owner_id, ''\\) AS owner_id, COALESCE\\(author_id, 0\\) AS author_id, status, published_at FROM article").WillReturnRows(rows)
 
 				return db
 			},
@@ -167,7 +358,89 @@ func Test_GetAll(t *testing.T) {
 					ID:      1,
 					Title:   "Test title",
 					Content: "Test content",
-					Author:  "Test author"},
+					Author:  &handler.AuthorResponse{Name: "Test author"}},
+			},
+		},
+		{
+			name: "success : page before cursor",
+			opts: models.ListOptions{Page: models.Page{Limit: 2, Before: &models.Cursor{ID: 3}}},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock return rows in descending order
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "created_at", "updated_at", "deleted", "owner_id", "author_id", "status", "published_at"}).
+					AddRow(int64(2), "Second title", "Test content", "Test author", createdAt, updatedAt, false, "", int64(1), "published", nil).
+					AddRow(int64(1), "First title", "Test content", "Test author", createdAt, updatedAt, false, "", int64(1), "published", nil)
+				mock.ExpectQuery("FROM article WHERE deleted_at IS NULL AND \\(\\(id < \\?\\)\\) ORDER BY id DESC LIMIT \\?").
+					WithArgs(3, 2).
+					WillReturnRows(rows)
+
+				return db
+			},
+			wantResp: []handler.ArticleResponse{
+				{ID: 1, Title: "First title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test author"}},
+				{ID: 2, Title: "Second title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test author"}},
+			},
+		},
+		{
+			name: "success : offset page after cursor including deleted",
+			opts: models.ListOptions{IncludeDeleted: true, Page: models.Page{Limit: 2, Offset: 2, After: &models.Cursor{ID: 3}}},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock return valid rows
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "created_at", "updated_at", "deleted", "owner_id", "author_id", "status", "published_at"}).AddRow(int64(6), "Test title", "Test content", "Test author", createdAt, updatedAt, true, "", int64(1), "published", nil)
+				mock.ExpectQuery("FROM article WHERE \\(\\(id > \\?\\)\\) ORDER BY id ASC LIMIT \\? OFFSET \\?").
+					WithArgs(3, 2, 2).
+					WillReturnRows(rows)
+
+				return db
+			},
+			wantResp: []handler.ArticleResponse{
+				{ID: 6, Title: "Test title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test author"}},
+			},
+		},
+		{
+			name: "success : filtered and sorted page after cursor",
+			opts: models.ListOptions{
+				Filter: models.ArticleFilter{
+					Authors:     []string{"Jane", "John"},
+					CreatedFrom: &from,
+					TitlePrefix: "100%_",
+				},
+				Sort: []models.SortField{{Field: "created_at", Desc: true}, {Field: "title"}},
+				Page: models.Page{Limit: 2, After: &models.Cursor{ID: 3}},
+			},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock return valid rows
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "created_at", "updated_at", "deleted", "owner_id", "author_id", "status", "published_at"}).AddRow(int64(4), "100%_ title", "Test content", "Jane", createdAt, updatedAt, false, "", int64(1), "published", nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, content, author, created_at, updated_at, deleted_at IS NOT NULL AS deleted, COALESCE(owner_id, '') AS owner_id, COALESCE(author_id, 0) AS author_id, status, published_at FROM article "+
+					"WHERE deleted_at IS NULL AND author IN (?, ?) AND created_at >= ? AND title LIKE ? AND ("+
+					"(created_at < (SELECT created_at FROM article WHERE id = ?)) OR "+
+					"(created_at = (SELECT created_at FROM article WHERE id = ?) AND title > (SELECT title FROM article WHERE id = ?)) OR "+
+					"(created_at = (SELECT created_at FROM article WHERE id = ?) AND title = (SELECT title FROM article WHERE id = ?) AND id > ?)) "+
+					"ORDER BY created_at DESC, title ASC, id ASC LIMIT ?")).
+					WithArgs("Jane", "John", from, `100\%\_%`, 3, 3, 3, 3, 3, 3, 2).
+					WillReturnRows(rows)
+
+				return db
+			},
+			wantResp: []handler.ArticleResponse{
+				{ID: 4, Title: "100%_ title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Jane"}},
 			},
 		},
 		{
@@ -180,7 +453,26 @@ func Test_GetAll(t *testing.T) {
 				}
 
 				// mock return error
-				mock.ExpectQuery("SELECT id, title, content, author FROM article").WillReturnError(errors.New("db error"))
+				mock.ExpectQuery("SELECT id, title, content, author, created_at, updated_at, deleted_at IS NOT NULL AS deleted, COALESCE\\(owner_id, ''\\) AS owner_id, COALESCE\\(author_id, 0\\) AS author_id, status, published_at FROM article").WillReturnError(errors.New("db error"))
+
+				return db
+			},
+		},
+		{
+			name: "error : iteration error",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock rows failing after first row
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "created_at", "updated_at", "deleted", "owner_id", "author_id", "status", "published_at"}).
+					AddRow(int64(1), "Test title", "Test content", "Test author", createdAt, updatedAt, false, "", int64(1), "published", nil).
+					AddRow(int64(2), "Test title", "Test content", "Test author", createdAt, updatedAt, false, "", int64(1), "published", nil).
+					RowError(1, errors.New("db error"))
+				mock.ExpectQuery("SELECT id, title, content, author, created_at, updated_at, deleted_at IS NOT NULL AS deleted, COALESCE\\(owner_id, ''\\) AS owner_id, COALESCE\\(author_id, 0\\) AS author_id, status, published_at FROM article").WillReturnRows(rows).RowsWillBeClosed()
 
 				return db
 			},
@@ -195,7 +487,7 @@ func Test_GetAll(t *testing.T) {
 			a := models.NewModels(db)
 
 			// call model function
-			gotResp, err := a.Article.GetAll()
+			gotResp, err := a.Article.List(context.Background(), tt.opts)
 			if err != nil {
 				assert.NotNil(t, err)
 				assert.Equal(t, err.Error(), "db error")
@@ -206,9 +498,445 @@ func Test_GetAll(t *testing.T) {
 					assert.Equal(t, val.ID, int(tt.wantResp[key].ID))
 					assert.Equal(t, val.Title, tt.wantResp[key].Title)
 					assert.Equal(t, val.Content, tt.wantResp[key].Content)
-					assert.Equal(t, val.Author, tt.wantResp[key].Author)
+					assert.Equal(t, val.Author, tt.wantResp[key].Author.Name)
 				}
 			}
 		})
 	}
 }
+
+func Test_Update(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		mockDB  func() *sql.DB
+		wantErr error
+	}{
+		{
+			name: "success",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock expected query
+				mock.ExpectBegin()
+				expectAuthor(mock, "Test author", 2)
+				mock.ExpectExec("UPDATE article SET title=\\?, content=\\?, author=\\?, author_id=\\?, updated_at=CURRENT_TIMESTAMP").
+					WithArgs("Test title", "Test content", "Test author", 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				return db
+			},
+		},
+		{
+			name: "error",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock expected query
+				mock.ExpectBegin()
+				expectAuthor(mock, "Test author", 2)
+				mock.ExpectExec("UPDATE article").WillReturnError(errors.New("db error"))
+				mock.ExpectRollback()
+
+				return db
+			},
+			wantErr: errors.New("db error"),
+		},
+		{
+			name: "error : missing article creates no author",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// the author is created in the transaction which is rolled back
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id, name").WithArgs("Test author").WillReturnRows(sqlmock.NewRows(authorColumns))
+				mock.ExpectExec("INSERT INTO author").WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec("UPDATE article").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				return db
+			},
+			wantErr: models.ErrArticleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.mockDB()
+
+			// store mocked db object in models
+			a := models.NewModels(db)
+
+			// call model function
+			err := a.Article.Update(context.Background(), &models.Article{ID: 1, Title: "Test title", Content: "Test content", Author: "Test author", CreateAuthor: true})
+			assert.Equal(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_Patch(t *testing.T) {
+	title := "Test title"
+	author := "Test author"
+
+	tests := []struct {
+		name    string
+		patch   models.ArticlePatch
+		mockDB  func() *sql.DB
+		wantErr error
+	}{
+		{
+			name:  "success",
+			patch: models.ArticlePatch{Title: &title, Author: &author},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock expected query
+				mock.ExpectBegin()
+				expectAuthor(mock, "Test author", 2)
+				mock.ExpectExec("UPDATE article SET title=\\?, author=\\?, author_id=\\?, updated_at=CURRENT_TIMESTAMP WHERE id=\\?").
+					WithArgs("Test title", "Test author", 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				return db
+			},
+		},
+		{
+			name:  "success : empty patch",
+			patch: models.ArticlePatch{},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// nothing to update, only existence is checked
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "created_at", "updated_at", "deleted", "owner_id", "author_id", "status", "published_at"}).
+					AddRow(int64(1), "Test title", "Test content", "Test author", time.Now(), time.Now(), false, "", int64(1), "published", nil)
+				mock.ExpectQuery("SELECT id, title").WithArgs(1).WillReturnRows(rows)
+
+				return db
+			},
+		},
+		{
+			name:  "error : not found",
+			patch: models.ArticlePatch{Title: &title},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock no matching row
+				mock.ExpectExec("UPDATE article").WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return db
+			},
+			wantErr: models.ErrArticleNotFound,
+		},
+		{
+			name:  "error : empty title",
+			patch: models.ArticlePatch{Title: new(string)},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection without expectations
+				db, _, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				return db
+			},
+			wantErr: &models.Error{Kind: models.ErrValidation, Message: "title is required"},
+		},
+		{
+			name:  "error",
+			patch: models.ArticlePatch{Title: &title},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock expected query
+				mock.ExpectExec("UPDATE article").WillReturnError(errors.New("db error"))
+
+				return db
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.mockDB()
+
+			// store mocked db object in models
+			a := models.NewModels(db)
+
+			// call model function
+			err := a.Article.Patch(context.Background(), 1, &tt.patch)
+			assert.Equal(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_SoftDelete(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		call    func(a models.ArticleStore) error
+		mockDB  func() *sql.DB
+		wantErr error
+	}{
+		{
+			name: "delete success",
+			call: func(a models.ArticleStore) error { return a.Delete(context.Background(), 1) },
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock expected query
+				mock.ExpectExec("UPDATE article SET deleted_at=CURRENT_TIMESTAMP").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return db
+			},
+		},
+		{
+			name: "restore success",
+			call: func(a models.ArticleStore) error { return a.Restore(context.Background(), 1) },
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock expected query
+				mock.ExpectExec("UPDATE article SET deleted_at=NULL").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return db
+			},
+		},
+		{
+			name: "error : restore article not deleted",
+			call: func(a models.ArticleStore) error { return a.Restore(context.Background(), 1) },
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock no deleted row, the live article is found
+				mock.ExpectExec("UPDATE article SET deleted_at=NULL, updated_at=updated_at WHERE id=\\? AND deleted_at IS NOT NULL").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "created_at", "updated_at", "deleted", "owner_id", "author_id", "status", "published_at"}).AddRow(int64(1), "Test title", "Test content", "Test author", time.Now(), time.Now(), false, "", int64(1), "published", nil)
+				mock.ExpectQuery("SELECT id, title").WithArgs(1).WillReturnRows(rows)
+
+				return db
+			},
+			wantErr: models.ErrArticleNotDeleted,
+		},
+		{
+			name: "error : restore not found",
+			call: func(a models.ArticleStore) error { return a.Restore(context.Background(), 1) },
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock no matching row
+				mock.ExpectExec("UPDATE article SET deleted_at=NULL").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT id, title").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				return db
+			},
+			wantErr: models.ErrArticleNotFound,
+		},
+		{
+			name: "purge success",
+			call: func(a models.ArticleStore) error { return a.Purge(context.Background(), 1) },
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock expected query
+				mock.ExpectExec("DELETE FROM article").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return db
+			},
+		},
+		{
+			name: "error : not found",
+			call: func(a models.ArticleStore) error { return a.Delete(context.Background(), 1) },
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock no matching row
+				mock.ExpectExec("UPDATE article SET deleted_at=CURRENT_TIMESTAMP").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return db
+			},
+			wantErr: models.ErrArticleNotFound,
+		},
+		{
+			name: "error",
+			call: func(a models.ArticleStore) error { return a.Delete(context.Background(), 1) },
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock expected query
+				mock.ExpectExec("UPDATE article").WillReturnError(errors.New("db error"))
+
+				return db
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.mockDB()
+
+			// store mocked db object in models
+			a := models.NewModels(db)
+
+			// call model function
+			err := tt.call(a.Article)
+			assert.Equal(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_Count(t *testing.T) {
+	to := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		opts      models.ListOptions
+		mockDB    func() *sql.DB
+		wantTotal int64
+		wantErr   error
+	}{
+		{
+			name: "success",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock return count
+				rows := sqlmock.NewRows([]string{"count"}).AddRow(int64(5))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM article WHERE deleted_at IS NULL").WillReturnRows(rows)
+
+				return db
+			},
+			wantTotal: 5,
+		},
+		{
+			name: "success : filtered",
+			opts: models.ListOptions{Filter: models.ArticleFilter{Authors: []string{"Jane"}, UpdatedTo: &to}},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock return count
+				rows := sqlmock.NewRows([]string{"count"}).AddRow(int64(2))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM article WHERE deleted_at IS NULL AND author IN (?) AND updated_at <= ?")).
+					WithArgs("Jane", to).
+					WillReturnRows(rows)
+
+				return db
+			},
+			wantTotal: 2,
+		},
+		{
+			name: "success : pagination ignored",
+			opts: models.ListOptions{IncludeDeleted: true, Page: models.Page{Limit: 2, After: &models.Cursor{ID: 1}}},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock return count
+				rows := sqlmock.NewRows([]string{"count"}).AddRow(int64(7))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM article$").WithArgs().WillReturnRows(rows)
+
+				return db
+			},
+			wantTotal: 7,
+		},
+		{
+			name: "error",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock return error
+				mock.ExpectQuery("SELECT COUNT").WillReturnError(errors.New("db error"))
+
+				return db
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.mockDB()
+
+			// store mocked db object in models
+			a := models.NewModels(db)
+
+			// call model function
+			gotTotal, err := a.Article.Count(context.Background(), tt.opts)
+			assert.Equal(t, err, tt.wantErr)
+			assert.Equal(t, gotTotal, tt.wantTotal)
+		})
+	}
+}
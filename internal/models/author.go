@@ -0,0 +1,416 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type author struct {
+	app     *Application
+	dialect dialect
+}
+
+// AuthorStore holds authors of articles
+type AuthorStore interface {
+	Create(ctx context.Context, author *Author) (int64, error)
+	GetByID(ctx context.Context, authorID int) (*Author, error)
+	GetByIDs(ctx context.Context, authorIDs []int) ([]*Author, error)
+	List(ctx context.Context, page Page) ([]*Author, error)
+	Count(ctx context.Context) (int64, error)
+	Update(ctx context.Context, author *Author) error
+	Delete(ctx context.Context, authorID int) error
+}
+
+// Author holds author fields, names are unique ignoring case
+type Author struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	Bio       string    `db:"bio"`
+	AvatarURL string    `db:"avatar_url"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+
+	// Subject is the principal writing as the author, authors created by editors have none until
+	// a principal of their name writes an article
+	Subject string `db:"subject"`
+}
+
+// ErrAuthorNotFound is returned when no author matches given id
+var ErrAuthorNotFound = newError(ErrNotFound, "author not found")
+
+// ErrAuthorHasArticles is returned when deleting an author articles still refer to, soft deleted ones included
+var ErrAuthorHasArticles = newError(ErrConflict, "author has articles")
+
+// ErrUnknownAuthor is returned when an article names an author which does not exist and may not be created
+var ErrUnknownAuthor = newError(ErrValidation, "author does not exist, authors are created by editors")
+
+// ErrAuthorTaken is returned when a principal writes as an author name linked to another principal
+var ErrAuthorTaken = newError(ErrConflict, "author name belongs to another user")
+
+// errAuthorRace is returned when another request created an author while it was being created
+var errAuthorRace = newError(ErrConflict, "author was created concurrently")
+
+// maxAvatarURLLength is the size of avatar_url column
+const maxAvatarURLLength = 2048
+
+// validate checks author fields against constraints of author table
+func (author *Author) validate() error {
+	err := validateField("name", author.Name, maxAuthorLength)
+	if err != nil {
+		return err
+	}
+
+	if utf8.RuneCountInString(author.AvatarURL) > maxAvatarURLLength {
+		return newError(ErrValidation, "avatar_url must be at most %d characters", maxAvatarURLLength)
+	}
+
+	return nil
+}
+
+// NewAuthorCursor returns cursor positioned at author
+func NewAuthorCursor(author *Author) *Cursor {
+	return &Cursor{ID: author.ID}
+}
+
+// authorColumns are selected by queries scanned with scanAuthor
+const authorColumns = `id, name, COALESCE(bio, '') AS bio, COALESCE(avatar_url, '') AS avatar_url, created_at, updated_at,
+	COALESCE(subject, '') AS subject`
+
+// Create stores author and returns its id
+func (s *author) Create(ctx context.Context, author *Author) (int64, error) {
+	err := author.validate()
+	if err != nil {
+		return 0, err
+	}
+
+	return s.insert(ctx, s.app.db, author)
+}
+
+// insert stores a validated author using q and assigns its id
+func (s *author) insert(ctx context.Context, q querier, author *Author) (int64, error) {
+	query := `INSERT INTO author (name, bio, avatar_url, subject) VALUES(?, ?, ?, ?)`
+	args := []interface{}{author.Name, nullString(author.Bio), nullString(author.AvatarURL), nullString(author.Subject)}
+
+	var id int64
+
+	if s.dialect.returningID {
+		err := q.QueryRowContext(ctx, s.dialect.statement(ctx, query+" RETURNING id"), args...).Scan(&id)
+		if err != nil {
+			return 0, storeError(err)
+		}
+	} else {
+		res, err := q.ExecContext(ctx, s.dialect.statement(ctx, query), args...)
+		if err != nil {
+			return 0, storeError(err)
+		}
+
+		id, err = res.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	author.ID = int(id)
+
+	return id, nil
+}
+
+// GetByID fetches author by authorID
+func (s *author) GetByID(ctx context.Context, authorID int) (*Author, error) {
+	return s.get(ctx, s.app.db, `SELECT `+authorColumns+` FROM author WHERE id=?`, authorID)
+}
+
+// getByName fetches author by name ignoring case using q
+func (s *author) getByName(ctx context.Context, q querier, name string) (*Author, error) {
+	return s.get(ctx, q, `SELECT `+authorColumns+` FROM author WHERE name=?`, name)
+}
+
+// getBySubject fetches the author linked to subject using q
+func (s *author) getBySubject(ctx context.Context, q querier, subject string) (*Author, error) {
+	return s.get(ctx, q, `SELECT `+authorColumns+` FROM author WHERE subject=?`, subject)
+}
+
+// get fetches the author selected by query using q, ErrAuthorNotFound is returned when there is none
+func (s *author) get(ctx context.Context, q querier, query string, args ...interface{}) (*Author, error) {
+	author, err := scanAuthor(q.QueryRowContext(ctx, s.dialect.statement(ctx, query), args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAuthorNotFound
+	}
+
+	return author, err
+}
+
+// GetByIDs fetches authors of authorIDs ordered by id, ids without author are skipped
+func (s *author) GetByIDs(ctx context.Context, authorIDs []int) ([]*Author, error) {
+	if len(authorIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, 0, len(authorIDs))
+	for _, id := range authorIDs {
+		args = append(args, id)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(authorIDs)), ", ")
+
+	return s.list(ctx, `SELECT `+authorColumns+` FROM author WHERE id IN (`+placeholders+`) ORDER BY id`, args...)
+}
+
+// List fetches a page of authors ordered by id, a page is selected using offset or keyset cursor
+func (s *author) List(ctx context.Context, page Page) ([]*Author, error) {
+	var (
+		conditions []string
+		args       []interface{}
+		order      = "id ASC"
+	)
+
+	if page.After != nil {
+		conditions = append(conditions, "id > ?")
+		args = append(args, page.After.ID)
+	}
+
+	if page.Before != nil {
+		// read backwards from cursor, rows are reversed after scanning
+		conditions = append(conditions, "id < ?")
+		args = append(args, page.Before.ID)
+		order = "id DESC"
+	}
+
+	query := `SELECT ` + authorColumns + ` FROM author`
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY " + order
+
+	if page.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, page.Limit)
+
+		if page.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, page.Offset)
+		}
+	}
+
+	authors, err := s.list(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.Before != nil {
+		for i, j := 0, len(authors)-1; i < j; i, j = i+1, j-1 {
+			authors[i], authors[j] = authors[j], authors[i]
+		}
+	}
+
+	return authors, nil
+}
+
+// list fetches authors selected by query
+func (s *author) list(ctx context.Context, query string, args ...interface{}) ([]*Author, error) {
+	rows, err := s.app.db.QueryContext(ctx, s.dialect.statement(ctx, query), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var authors []*Author
+
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		authors = append(authors, author)
+	}
+
+	return authors, rows.Err()
+}
+
+// Count counts all authors
+func (s *author) Count(ctx context.Context) (int64, error) {
+	var total int64
+
+	err := s.app.db.QueryRowContext(ctx, s.dialect.statement(ctx, `SELECT COUNT(*) FROM author`)).Scan(&total)
+
+	return total, err
+}
+
+// Update replaces all editable fields of an author, articles of the author take the new name
+func (s *author) Update(ctx context.Context, author *Author) error {
+	err := author.validate()
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.app.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	query := `UPDATE author SET name=?, bio=?, avatar_url=?, updated_at=CURRENT_TIMESTAMP WHERE id=?`
+
+	res, err := tx.ExecContext(ctx, s.dialect.statement(ctx, query), author.Name, nullString(author.Bio), nullString(author.AvatarURL), author.ID)
+	if err != nil {
+		return storeError(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrAuthorNotFound
+	}
+
+	// updated_at tracks content changes, keep it as is
+	query = `UPDATE article SET author=?, updated_at=updated_at WHERE author_id=?`
+
+	_, err = tx.ExecContext(ctx, s.dialect.statement(ctx, query), author.Name, author.ID)
+	if err != nil {
+		return storeError(err)
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes an author, ErrAuthorHasArticles is returned while articles refer to it
+func (s *author) Delete(ctx context.Context, authorID int) error {
+	res, err := s.app.db.ExecContext(ctx, s.dialect.statement(ctx, `DELETE FROM author WHERE id=?`), authorID)
+
+	err = storeError(err)
+	if errors.Is(err, ErrConflict) {
+		return ErrAuthorHasArticles
+	}
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrAuthorNotFound
+	}
+
+	return nil
+}
+
+// authorRef names the author an article is written as
+type authorRef struct {
+	// name is looked up ignoring case
+	name string
+
+	// subject is the principal writing as its own author, empty when the author is chosen by name
+	subject string
+
+	// create lets the author of name be created when there is none
+	create bool
+}
+
+// resolve returns the author of ref using q. The author linked to the subject of ref is returned whatever its name,
+// an author of the name without subject is linked to it and one is created otherwise. Without subject the author
+// named name ignoring case is returned, it is created if create is set and ErrUnknownAuthor is returned otherwise.
+// errAuthorRace is returned when another request created or linked the author meanwhile
+func (s *author) resolve(ctx context.Context, q querier, ref authorRef) (*Author, error) {
+	if ref.subject != "" {
+		author, err := s.getBySubject(ctx, q, ref.subject)
+		if !errors.Is(err, ErrAuthorNotFound) {
+			return author, err
+		}
+	}
+
+	author, err := s.getByName(ctx, q, ref.name)
+	if err == nil && ref.subject != "" {
+		return s.link(ctx, q, author, ref.subject)
+	}
+
+	if !errors.Is(err, ErrAuthorNotFound) {
+		return author, err
+	}
+
+	if !ref.create && ref.subject == "" {
+		return nil, ErrUnknownAuthor
+	}
+
+	author = &Author{Name: ref.name, Subject: ref.subject}
+
+	err = author.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.insert(ctx, q, author)
+	if errors.Is(err, ErrConflict) {
+		return nil, errAuthorRace
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return author, nil
+}
+
+// link links author to subject using q, ErrAuthorTaken is returned when it is linked to another subject
+func (s *author) link(ctx context.Context, q querier, author *Author, subject string) (*Author, error) {
+	if author.Subject != "" {
+		return nil, ErrAuthorTaken
+	}
+
+	res, err := q.ExecContext(ctx, s.dialect.statement(ctx, `UPDATE author SET subject=? WHERE id=? AND subject IS NULL`), subject, author.ID)
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if affected == 0 {
+		return nil, errAuthorRace
+	}
+
+	author.Subject = subject
+
+	return author, nil
+}
+
+// scanAuthor scans a row of authorColumns
+func scanAuthor(row scanner) (*Author, error) {
+	var author Author
+
+	err := row.Scan(&author.ID, &author.Name, &author.Bio, &author.AvatarURL, &author.CreatedAt, &author.UpdatedAt, &author.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	author.CreatedAt = author.CreatedAt.UTC()
+	author.UpdatedAt = author.UpdatedAt.UTC()
+
+	return &author, nil
+}
+
+// nullString returns query argument of s, empty strings are stored as NULL
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
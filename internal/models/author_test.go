@@ -0,0 +1,178 @@
+package models_test
+
+import (
+	"article/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+// authorColumns are the columns of author rows
+var authorColumns = []string{"id", "name", "bio", "avatar_url", "created_at", "updated_at", "subject"}
+
+// expectAuthor expects the lookup of the author named name, which exists with id
+func expectAuthor(mock sqlmock.Sqlmock, name string, id int64) {
+	rows := sqlmock.NewRows(authorColumns).AddRow(id, name, "", "", time.Now(), time.Now(), "")
+	mock.ExpectQuery("SELECT id, name").WithArgs(name).WillReturnRows(rows)
+}
+
+func Test_AuthorUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mockDB  func() *sql.DB
+		wantErr error
+	}{
+		{
+			name: "success",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// articles take the new name in the same transaction
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE author SET name=\\?, bio=\\?, avatar_url=\\?").
+					WithArgs("Jane Doe", "Writes about Go", nil, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE article SET author=\\?, updated_at=updated_at WHERE author_id=\\?").
+					WithArgs("Jane Doe", 1).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+
+				return db
+			},
+		},
+		{
+			name: "error : not found",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE author").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				return db
+			},
+			wantErr: models.ErrAuthorNotFound,
+		},
+		{
+			name: "error : name taken",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE author").WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+				mock.ExpectRollback()
+
+				return db
+			},
+			wantErr: models.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := models.NewModels(tt.mockDB())
+
+			err := a.Author.Update(context.Background(), &models.Author{ID: 1, Name: "Jane Doe", Bio: "Writes about Go"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func Test_AuthorDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		mockDB  func() *sql.DB
+		wantErr error
+	}{
+		{
+			name: "success",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				mock.ExpectExec("DELETE FROM author WHERE id=\\?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				return db
+			},
+		},
+		{
+			name: "error : not found",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				mock.ExpectExec("DELETE FROM author").WillReturnResult(sqlmock.NewResult(0, 0))
+
+				return db
+			},
+			wantErr: models.ErrAuthorNotFound,
+		},
+		{
+			name: "error : author has articles",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				mock.ExpectExec("DELETE FROM author").WillReturnError(&mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"})
+
+				return db
+			},
+			wantErr: models.ErrAuthorHasArticles,
+		},
+		{
+			name: "error",
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				mock.ExpectExec("DELETE FROM author").WillReturnError(errors.New("db error"))
+
+				return db
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := models.NewModels(tt.mockDB())
+
+			err := a.Author.Delete(context.Background(), 1)
+			assert.Equal(t, err, tt.wantErr)
+		})
+	}
+}
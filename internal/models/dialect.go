@@ -0,0 +1,197 @@
+package models
+
+import (
+	"article/internal/database"
+	"article/internal/search"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// dialect holds the SQL which differs between databases backing the article store,
+// queries are written with ? placeholders and rebound before they are run
+type dialect struct {
+	// system names the database in traces
+	system string
+
+	// rebind rewrites ? placeholders, nil keeps them
+	rebind func(query string) string
+
+	// returningID makes inserts return the new id as the driver does not support LastInsertId
+	returningID bool
+
+	// upsert returns clause appended to an INSERT which updates columns of the row with same keys instead
+	upsert func(keys, columns []string) string
+
+	// likeEscape is appended to LIKE conditions to make backslash escape wildcards
+	likeEscape string
+
+	// timeArg converts a time into a query argument comparable with stored timestamps
+	timeArg func(t time.Time) interface{}
+
+	// matchQuery returns a query selecting articles matching q with their relevance as score. Scores are rounded
+	// to 10 decimals so the value read back and sent in a search cursor compares equal to the score of its row
+	matchQuery func(q search.Query) (string, []interface{})
+
+	// countQuery returns a query counting articles matching q
+	countQuery func(q search.Query) (string, []interface{})
+}
+
+// bind returns query with placeholders of the dialect
+func (d dialect) bind(query string) string {
+	if d.rebind == nil {
+		return query
+	}
+
+	return d.rebind(query)
+}
+
+// statement returns query bound to the dialect and adds it sanitized to the span of ctx
+func (d dialect) statement(ctx context.Context, query string) string {
+	query = d.bind(query)
+
+	span := trace.SpanFromContext(ctx)
+	if span.IsRecording() {
+		span.SetAttributes(
+			attribute.String("db.system", d.system),
+			attribute.String("db.statement", sanitizeSQL(query)),
+		)
+	}
+
+	return query
+}
+
+// sqlLiteral matches quoted string literals of SQL
+var sqlLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+// sanitizeSQL collapses whitespace of query and replaces string literals with ?,
+// values are passed as arguments so only literals could carry data into traces
+func sanitizeSQL(query string) string {
+	return strings.Join(strings.Fields(sqlLiteral.ReplaceAllString(query, "?")), " ")
+}
+
+// mysqlDialect uses FULLTEXT index of title and content for search
+var mysqlDialect = dialect{
+	system: "mysql",
+	upsert: onDuplicateKey,
+	timeArg: func(t time.Time) interface{} {
+		return t.UTC()
+	},
+	matchQuery: func(q search.Query) (string, []interface{}) {
+		expr := booleanMode(q)
+
+		return `SELECT ` + articleColumns + `,
+			CAST(MATCH(title, content) AGAINST(? IN BOOLEAN MODE) AS DECIMAL(20, 10)) AS score
+		FROM article
+		WHERE deleted_at IS NULL AND MATCH(title, content) AGAINST(? IN BOOLEAN MODE)`, []interface{}{expr, expr}
+	},
+	countQuery: func(q search.Query) (string, []interface{}) {
+		return `SELECT COUNT(*) FROM article
+		WHERE deleted_at IS NULL AND MATCH(title, content) AGAINST(? IN BOOLEAN MODE)`, []interface{}{booleanMode(q)}
+	},
+}
+
+// sqliteTimeLayout matches text of CURRENT_TIMESTAMP, fractional seconds keep bounds exact
+const sqliteTimeLayout = "2006-01-02 15:04:05.999999999"
+
+// sqliteDialect uses the article_fts FTS5 table for search, timestamps are stored as UTC text
+var sqliteDialect = dialect{
+	system:     "sqlite",
+	upsert:     onConflict,
+	likeEscape: ` ESCAPE '\'`,
+	timeArg: func(t time.Time) interface{} {
+		return t.UTC().Format(sqliteTimeLayout)
+	},
+	matchQuery: func(q search.Query) (string, []interface{}) {
+		// bm25 is lower for better matches, it is negated to order by score descending like MySQL
+		return `SELECT a.id, a.title, a.content, a.author, a.created_at, a.updated_at, a.deleted_at IS NOT NULL AS deleted,
+			COALESCE(a.owner_id, '') AS owner_id, COALESCE(a.author_id, 0) AS author_id, a.status, a.published_at, ROUND(-bm25(article_fts, 2.0, 1.0), 10) AS score
+		FROM article_fts JOIN article AS a ON a.id = article_fts.rowid
+		WHERE a.deleted_at IS NULL AND article_fts MATCH ?`, []interface{}{ftsQuery(q)}
+	},
+	countQuery: func(q search.Query) (string, []interface{}) {
+		return `SELECT COUNT(*) FROM article_fts JOIN article AS a ON a.id = article_fts.rowid
+		WHERE a.deleted_at IS NULL AND article_fts MATCH ?`, []interface{}{ftsQuery(q)}
+	},
+}
+
+// postgresDialect uses the generated search tsvector column for search, title is weighted A and content B
+var postgresDialect = dialect{
+	system:      "postgresql",
+	rebind:      database.Rebind,
+	returningID: true,
+	upsert:      onConflict,
+	timeArg: func(t time.Time) interface{} {
+		return t.UTC()
+	},
+	matchQuery: func(q search.Query) (string, []interface{}) {
+		expr := tsQuery(q)
+
+		// weights of D, C, B and A labels, title matches count twice as much as content ones like in MySQL
+		return `SELECT ` + articleColumns + `,
+			ROUND(ts_rank('{0.1, 0.2, 0.5, 1.0}', search, to_tsquery('simple', ?))::numeric, 10)::float8 AS score
+		FROM article
+		WHERE deleted_at IS NULL AND search @@ to_tsquery('simple', ?)`, []interface{}{expr, expr}
+	},
+	countQuery: func(q search.Query) (string, []interface{}) {
+		return `SELECT COUNT(*) FROM article
+		WHERE deleted_at IS NULL AND search @@ to_tsquery('simple', ?)`, []interface{}{tsQuery(q)}
+	},
+}
+
+// onDuplicateKey returns MySQL upsert clause, keys are implied by the unique index hit
+func onDuplicateKey(keys, columns []string) string {
+	var set []string
+
+	for _, column := range columns {
+		set = append(set, fmt.Sprintf("%s=VALUES(%s)", column, column))
+	}
+
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(set, ", ")
+}
+
+// onConflict returns upsert clause of postgres and sqlite
+func onConflict(keys, columns []string) string {
+	var set []string
+
+	for _, column := range columns {
+		set = append(set, fmt.Sprintf("%s=excluded.%s", column, column))
+	}
+
+	return fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(keys, ", "), strings.Join(set, ", "))
+}
+
+// tsQuery returns postgres tsquery requiring all terms and phrases,
+// tokens hold only letters and digits so they cannot inject operators
+func tsQuery(q search.Query) string {
+	var parts []string
+
+	parts = append(parts, q.Terms...)
+
+	for _, phrase := range q.Phrases {
+		parts = append(parts, "("+strings.Join(phrase, " <-> ")+")")
+	}
+
+	return strings.Join(parts, " & ")
+}
+
+// ftsQuery returns FTS5 expression requiring all terms and phrases,
+// tokens hold only letters and digits so quoting them cannot inject operators
+func ftsQuery(q search.Query) string {
+	var parts []string
+
+	for _, term := range q.Terms {
+		parts = append(parts, `"`+term+`"`)
+	}
+
+	for _, phrase := range q.Phrases {
+		parts = append(parts, `"`+strings.Join(phrase, " ")+`"`)
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// error kinds returned by stores, match them using errors.Is
+var (
+	// ErrNotFound is returned when the requested record does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is returned when a write clashes with an existing record
+	ErrConflict = errors.New("conflict")
+
+	// ErrValidation is returned when a record breaks a constraint of the store
+	ErrValidation = errors.New("validation failed")
+
+	// ErrInvalidInput is returned when options passed to a store are malformed
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+// ErrArticleNotFound is returned when no article matches given id
+var ErrArticleNotFound = newError(ErrNotFound, "article not found")
+
+// ErrArticleNotDeleted is returned when restoring an article which is not deleted
+var ErrArticleNotDeleted = newError(ErrConflict, "article is not deleted")
+
+// errReferenced is returned when a write breaks a foreign key, stores return a more specific error where they can
+var errReferenced = newError(ErrConflict, "record is referenced by other records")
+
+// Error is a store error of a kind with a message fit for clients
+type Error struct {
+	Kind    error
+	Message string
+
+	// Cause is the driver error behind the store error, it is meant for server logs and never sent to clients
+	Cause error
+}
+
+func newError(kind error, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// driverError returns a store error of kind with the fixed message of the kind, cause keeps the driver error
+func driverError(kind error, cause error) *Error {
+	message := "record already exists"
+	if kind == ErrValidation {
+		message = "invalid value"
+	}
+
+	return &Error{Kind: kind, Message: message, Cause: cause}
+}
+
+// Error returns error message
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns error kind
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// mysql server error numbers
+const (
+	errBadNull         = 1048
+	errDupEntry        = 1062
+	errDataTooLong     = 1406
+	errRowIsReferenced = 1451
+	errNoReferencedRow = 1452
+)
+
+// postgres SQLSTATE codes
+const (
+	pgStringTooLong = "22001"
+	pgNotNull       = "23502"
+	pgUnique        = "23505"
+	pgCheck         = "23514"
+	pgForeignKey    = "23503"
+)
+
+// storeError translates driver errors into store errors with fixed messages, the driver error is kept as Cause
+// as its text names tables, columns and values. Other errors are returned as is
+func storeError(err error) error {
+	var (
+		mysqlErr  *mysql.MySQLError
+		pgErr     *pgconn.PgError
+		sqliteErr *sqlite.Error
+	)
+
+	switch {
+	case errors.As(err, &mysqlErr):
+		switch mysqlErr.Number {
+		case errDupEntry:
+			return driverError(ErrConflict, err)
+		case errBadNull, errDataTooLong:
+			return driverError(ErrValidation, err)
+		case errRowIsReferenced, errNoReferencedRow:
+			return errReferenced
+		}
+	case errors.As(err, &pgErr):
+		switch pgErr.Code {
+		case pgUnique:
+			return driverError(ErrConflict, err)
+		case pgNotNull, pgStringTooLong, pgCheck:
+			return driverError(ErrValidation, err)
+		case pgForeignKey:
+			return errReferenced
+		}
+	case errors.As(err, &sqliteErr):
+		// sqlite reports extended result codes naming the failed constraint
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return driverError(ErrConflict, err)
+		case sqlite3.SQLITE_CONSTRAINT_NOTNULL, sqlite3.SQLITE_CONSTRAINT_CHECK:
+			return driverError(ErrValidation, err)
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return errReferenced
+		}
+	}
+
+	return err
+}
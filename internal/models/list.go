@@ -0,0 +1,308 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a cursor cannot be decoded
+var ErrInvalidCursor = newError(ErrInvalidInput, "invalid cursor")
+
+// SortColumns whitelists columns articles can be sorted by, keyed by API field name
+var SortColumns = map[string]string{
+	"id":         "id",
+	"title":      "title",
+	"author":     "author",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// ListOptions holds options to list articles
+type ListOptions struct {
+	// IncludeDeleted lists soft deleted articles as well
+	IncludeDeleted bool
+
+	// Filter narrows down listed articles
+	Filter ArticleFilter
+
+	// Sort orders articles, id is always used as the final tie breaker
+	Sort []SortField
+
+	Page
+}
+
+// Page selects a page of articles using offset or keyset cursor
+type Page struct {
+	// Limit caps number of articles, zero means no limit
+	Limit int
+
+	// Offset skips number of articles, used only with Limit
+	Offset int
+
+	// After selects articles positioned after the cursor
+	After *Cursor
+
+	// Before selects articles positioned before the cursor
+	Before *Cursor
+}
+
+// ArticleFilter holds article list filters, zero value fields are not applied
+type ArticleFilter struct {
+	// Authors matches any of given authors exactly
+	Authors []string
+
+	// AuthorID matches articles of the author
+	AuthorID int
+
+	// CreatedFrom and CreatedTo bound created_at, both inclusive
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+
+	// UpdatedFrom and UpdatedTo bound updated_at, both inclusive
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+
+	// TitlePrefix matches titles starting with prefix
+	TitlePrefix string
+
+	// Statuses matches any of given statuses
+	Statuses []Status
+
+	// OwnerID limits articles which are not published to those owned by the subject,
+	// published articles match whoever owns them
+	OwnerID string
+}
+
+// SortField holds a column to sort by
+type SortField struct {
+	// Field is an API field name, a key of SortColumns
+	Field string
+	Desc  bool
+}
+
+// conditions returns where conditions and their args shared by list and count queries
+func (o ListOptions) conditions(d dialect) ([]string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	if !o.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	f := o.Filter
+
+	if len(f.Authors) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(f.Authors)), ", ")
+		conditions = append(conditions, fmt.Sprintf("author IN (%s)", placeholders))
+
+		for _, author := range f.Authors {
+			args = append(args, author)
+		}
+	}
+
+	if f.AuthorID > 0 {
+		conditions = append(conditions, "author_id = ?")
+		args = append(args, f.AuthorID)
+	}
+
+	for _, bound := range []struct {
+		condition string
+		val       *time.Time
+	}{
+		{"created_at >= ?", f.CreatedFrom},
+		{"created_at <= ?", f.CreatedTo},
+		{"updated_at >= ?", f.UpdatedFrom},
+		{"updated_at <= ?", f.UpdatedTo},
+	} {
+		if bound.val != nil {
+			conditions = append(conditions, bound.condition)
+			args = append(args, d.timeArg(*bound.val))
+		}
+	}
+
+	if f.TitlePrefix != "" {
+		conditions = append(conditions, "title LIKE ?"+d.likeEscape)
+		args = append(args, escapeLike(f.TitlePrefix)+"%")
+	}
+
+	if condition, statusArgs := statusCondition(f.Statuses, f.OwnerID); condition != "" {
+		conditions = append(conditions, condition)
+		args = append(args, statusArgs...)
+	}
+
+	return conditions, args
+}
+
+// statusCondition returns condition matching any of statuses, all statuses match when empty. Articles which
+// are not published must be owned by ownerID when it is set. The condition is empty when nothing is filtered
+func statusCondition(statuses []Status, ownerID string) (string, []interface{}) {
+	if ownerID == "" {
+		if len(statuses) == 0 {
+			return "", nil
+		}
+
+		return statusIn(statuses)
+	}
+
+	// articles which are not published must be owned
+	owned, args := "owner_id = ?", []interface{}{ownerID}
+
+	if len(statuses) > 0 {
+		var unpublished []Status
+
+		for _, status := range statuses {
+			if status != StatusPublished {
+				unpublished = append(unpublished, status)
+			}
+		}
+
+		if len(unpublished) == 0 {
+			return statusIn(statuses)
+		}
+
+		condition, statusArgs := statusIn(unpublished)
+		owned, args = condition+" AND "+owned, append(statusArgs, ownerID)
+
+		if !containsStatus(statuses, StatusPublished) {
+			return owned, args
+		}
+	}
+
+	return fmt.Sprintf("(status = ? OR (%s))", owned), append([]interface{}{StatusPublished}, args...)
+}
+
+// statusIn returns condition matching any of statuses
+func statusIn(statuses []Status) (string, []interface{}) {
+	var args []interface{}
+	for _, status := range statuses {
+		args = append(args, status)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(statuses)), ", ")
+
+	return fmt.Sprintf("status IN (%s)", placeholders), args
+}
+
+// orderBy returns sort columns with id appended as tie breaker, unknown fields are skipped
+func (o ListOptions) orderBy() []SortField {
+	var fields []SortField
+
+	for _, field := range o.Sort {
+		if _, ok := SortColumns[field.Field]; !ok {
+			continue
+		}
+
+		fields = append(fields, field)
+
+		// id is unique, columns after it never take effect
+		if field.Field == "id" {
+			return fields
+		}
+	}
+
+	return append(fields, SortField{Field: "id"})
+}
+
+// keyset returns condition selecting rows positioned after the cursor row in given order,
+// cursor row values are looked up by id so cursors stay valid for any sort
+func keyset(fields []SortField, cursor *Cursor) (string, []interface{}) {
+	var (
+		alternatives []string
+		args         []interface{}
+	)
+
+	for i, field := range fields {
+		var parts []string
+
+		// all previous columns are equal to cursor row
+		for _, prev := range fields[:i] {
+			column := SortColumns[prev.Field]
+			parts = append(parts, fmt.Sprintf("%s = (SELECT %s FROM article WHERE id = ?)", column, column))
+			args = append(args, cursor.ID)
+		}
+
+		op := ">"
+		if field.Desc {
+			op = "<"
+		}
+
+		column := SortColumns[field.Field]
+		if column == "id" {
+			parts = append(parts, fmt.Sprintf("id %s ?", op))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s %s (SELECT %s FROM article WHERE id = ?)", column, op, column))
+		}
+
+		args = append(args, cursor.ID)
+
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// reverse flips sort direction of all fields
+func reverse(fields []SortField) []SortField {
+	reversed := make([]SortField, len(fields))
+
+	for i, field := range fields {
+		reversed[i] = SortField{Field: field.Field, Desc: !field.Desc}
+	}
+
+	return reversed
+}
+
+// escapeLike escapes LIKE wildcards in s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Cursor points to an article position for keyset pagination
+type Cursor struct {
+	ID int `json:"id"`
+
+	// Score positions cursor in search results ordered by relevance, it is the score the store returned
+	// and is encoded exactly so results tied on score are paged by id
+	Score *float64 `json:"score,omitempty"`
+}
+
+// NewCursor returns cursor positioned at article
+func NewCursor(article *Article) *Cursor {
+	return &Cursor{ID: article.ID}
+}
+
+// NewSearchCursor returns cursor positioned at search result
+func NewSearchCursor(result *SearchResult) *Cursor {
+	score := result.Score
+
+	return &Cursor{ID: result.Article.ID, Score: &score}
+}
+
+// Encode returns opaque string form of cursor
+func (c *Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses cursor returned by Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+
+	err = json.Unmarshal(raw, &c)
+	if err != nil || c.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
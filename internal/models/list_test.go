@@ -0,0 +1,42 @@
+package models_test
+
+import (
+	"article/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Cursor(t *testing.T) {
+	tests := []struct {
+		name       string
+		cursor     string
+		wantCursor *models.Cursor
+		wantErr    error
+	}{
+		{
+			name:       "success",
+			cursor:     models.NewCursor(&models.Article{ID: 42}).Encode(),
+			wantCursor: &models.Cursor{ID: 42},
+		},
+		{
+			name:    "error : invalid encoding",
+			cursor:  "not a cursor",
+			wantErr: models.ErrInvalidCursor,
+		},
+		{
+			name:    "error : invalid id",
+			cursor:  (&models.Cursor{ID: 0}).Encode(),
+			wantErr: models.ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCursor, err := models.DecodeCursor(tt.cursor)
+
+			assert.Equal(t, err, tt.wantErr)
+			assert.Equal(t, gotCursor, tt.wantCursor)
+		})
+	}
+}
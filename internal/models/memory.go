@@ -0,0 +1,1012 @@
+package models
+
+import (
+	"article/internal/search"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryArticle is a concurrency safe in-memory ArticleStore following the semantics of the mysql store:
+// ids are assigned in sequence and never reused, timestamps have second precision and text
+// is compared case insensitively like the default mysql collation
+type memoryArticle struct {
+	mu sync.RWMutex
+
+	// path of the json snapshot, empty disables persistence
+	path string
+
+	nextID   int
+	articles map[int]*memoryRecord
+	index    *search.Index
+
+	// authors are kept with articles so both are saved to one snapshot
+	nextAuthorID int
+	authors      map[int]*Author
+
+	// changes is the review history of all articles in order
+	nextChangeID int
+	changes      []*StatusChange
+
+	now func() time.Time
+}
+
+// memoryRecord holds a stored article with its soft delete time
+type memoryRecord struct {
+	Article   Article
+	DeletedAt *time.Time
+}
+
+// snapshot is the json document a memory store is persisted to
+type snapshot struct {
+	NextID   int               `json:"next_id"`
+	Articles []snapshotArticle `json:"articles"`
+
+	// snapshots saved before authors were stored have none, authors of their articles are created on load
+	NextAuthorID int              `json:"next_author_id,omitempty"`
+	Authors      []snapshotAuthor `json:"authors,omitempty"`
+
+	NextChangeID  int                    `json:"next_change_id,omitempty"`
+	StatusChanges []snapshotStatusChange `json:"status_changes,omitempty"`
+}
+
+type snapshotStatusChange struct {
+	ID         int       `json:"id"`
+	ArticleID  int       `json:"article_id"`
+	Transition string    `json:"transition"`
+	From       Status    `json:"from"`
+	To         Status    `json:"to"`
+	Actor      string    `json:"actor"`
+	Comment    string    `json:"comment,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type snapshotAuthor struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Bio       string    `json:"bio,omitempty"`
+	AvatarURL string    `json:"avatar_url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Subject   string    `json:"subject,omitempty"`
+}
+
+type snapshotArticle struct {
+	ID        int        `json:"id"`
+	Title     string     `json:"title"`
+	Content   string     `json:"content"`
+	Author    string     `json:"author"`
+	AuthorID  int        `json:"author_id,omitempty"`
+	OwnerID   string     `json:"owner_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	// articles saved before the workflow have no status, they were public so they are published
+	Status      Status     `json:"status,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// NewMemoryModels returns models backed by an in-memory store. When path is set the store
+// is loaded from the json snapshot at path, if it exists, and saved to it after every write
+func NewMemoryModels(path string) (*Models, error) {
+	store := &memoryArticle{
+		path:     path,
+		nextID:   1,
+		articles: map[int]*memoryRecord{},
+		index:    search.NewIndex(),
+
+		nextAuthorID: 1,
+		authors:      map[int]*Author{},
+
+		nextChangeID: 1,
+
+		now: func() time.Time {
+			return time.Now().UTC().Truncate(time.Second)
+		},
+	}
+
+	if path != "" {
+		err := store.load()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Models{Article: store, Author: &memoryAuthor{m: store}, APIKey: newMemoryAPIKey()}, nil
+}
+
+// load reads snapshot file, a missing file leaves the store empty
+func (m *memoryArticle) load() error {
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var snap snapshot
+
+	err = json.Unmarshal(data, &snap)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range snap.Authors {
+		m.authors[a.ID] = &Author{
+			ID:        a.ID,
+			Name:      a.Name,
+			Bio:       a.Bio,
+			AvatarURL: a.AvatarURL,
+			CreatedAt: a.CreatedAt.UTC(),
+			UpdatedAt: a.UpdatedAt.UTC(),
+			Subject:   a.Subject,
+		}
+
+		if a.ID >= m.nextAuthorID {
+			m.nextAuthorID = a.ID + 1
+		}
+	}
+
+	if snap.NextAuthorID > m.nextAuthorID {
+		m.nextAuthorID = snap.NextAuthorID
+	}
+
+	for _, a := range snap.Articles {
+		// articles saved before authors were stored get an author per name ignoring case and surrounding spaces
+		if a.AuthorID == 0 {
+			author, _ := m.resolveAuthor(nil, authorRef{name: strings.TrimSpace(a.Author), create: true})
+			a.Author, a.AuthorID = author.Name, author.ID
+		}
+
+		if a.Status == "" {
+			createdAt := a.CreatedAt
+			a.Status, a.PublishedAt = StatusPublished, &createdAt
+		}
+
+		m.articles[a.ID] = &memoryRecord{
+			Article: Article{
+				ID:        a.ID,
+				Title:     a.Title,
+				Content:   a.Content,
+				Author:    a.Author,
+				AuthorID:  a.AuthorID,
+				OwnerID:   a.OwnerID,
+				CreatedAt: a.CreatedAt.UTC(),
+				UpdatedAt: a.UpdatedAt.UTC(),
+				Deleted:   a.DeletedAt != nil,
+
+				Status:      a.Status,
+				PublishedAt: utcPtr(a.PublishedAt),
+			},
+			DeletedAt: a.DeletedAt,
+		}
+
+		m.index.Add(a.ID, a.Title, a.Content)
+
+		if a.ID >= m.nextID {
+			m.nextID = a.ID + 1
+		}
+	}
+
+	if snap.NextID > m.nextID {
+		m.nextID = snap.NextID
+	}
+
+	for _, c := range snap.StatusChanges {
+		m.changes = append(m.changes, &StatusChange{
+			ID:         c.ID,
+			ArticleID:  c.ArticleID,
+			Transition: c.Transition,
+			From:       c.From,
+			To:         c.To,
+			Actor:      c.Actor,
+			Comment:    c.Comment,
+			CreatedAt:  c.CreatedAt.UTC(),
+		})
+
+		if c.ID >= m.nextChangeID {
+			m.nextChangeID = c.ID + 1
+		}
+	}
+
+	if snap.NextChangeID > m.nextChangeID {
+		m.nextChangeID = snap.NextChangeID
+	}
+
+	return nil
+}
+
+// save writes snapshot file atomically, caller must hold the write lock
+func (m *memoryArticle) save() error {
+	if m.path == "" {
+		return nil
+	}
+
+	snap := snapshot{NextID: m.nextID, Articles: []snapshotArticle{}, NextAuthorID: m.nextAuthorID, NextChangeID: m.nextChangeID}
+
+	for _, id := range m.authorIDs() {
+		a := m.authors[id]
+
+		snap.Authors = append(snap.Authors, snapshotAuthor{
+			ID:        a.ID,
+			Name:      a.Name,
+			Bio:       a.Bio,
+			AvatarURL: a.AvatarURL,
+			CreatedAt: a.CreatedAt,
+			UpdatedAt: a.UpdatedAt,
+			Subject:   a.Subject,
+		})
+	}
+
+	for _, id := range m.ids() {
+		r := m.articles[id]
+
+		snap.Articles = append(snap.Articles, snapshotArticle{
+			ID:        r.Article.ID,
+			Title:     r.Article.Title,
+			Content:   r.Article.Content,
+			Author:    r.Article.Author,
+			AuthorID:  r.Article.AuthorID,
+			OwnerID:   r.Article.OwnerID,
+			CreatedAt: r.Article.CreatedAt,
+			UpdatedAt: r.Article.UpdatedAt,
+			DeletedAt: r.DeletedAt,
+
+			Status:      r.Article.Status,
+			PublishedAt: r.Article.PublishedAt,
+		})
+	}
+
+	for _, c := range m.changes {
+		snap.StatusChanges = append(snap.StatusChanges, snapshotStatusChange{
+			ID:         c.ID,
+			ArticleID:  c.ArticleID,
+			Transition: c.Transition,
+			From:       c.From,
+			To:         c.To,
+			Actor:      c.Actor,
+			Comment:    c.Comment,
+			CreatedAt:  c.CreatedAt,
+		})
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so a crash never leaves a partial snapshot
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), m.path)
+}
+
+// memoryUndo records the stored data a write is about to change, the write is undone with it
+// when the snapshot cannot be saved. Only records the write touches are copied
+type memoryUndo struct {
+	nextID       int
+	nextAuthorID int
+	nextChangeID int
+
+	// changes is never modified in place, writes append to it or replace it
+	changes []*StatusChange
+
+	// previous records by id, nil when the write adds the record
+	articles map[int]*memoryRecord
+	authors  map[int]*Author
+}
+
+// backup starts the undo record of a write, nil is returned when nothing is persisted as saving cannot fail.
+// Caller must hold the write lock
+func (m *memoryArticle) backup() *memoryUndo {
+	if m.path == "" {
+		return nil
+	}
+
+	return &memoryUndo{
+		nextID:       m.nextID,
+		nextAuthorID: m.nextAuthorID,
+		nextChangeID: m.nextChangeID,
+		changes:      m.changes,
+		articles:     map[int]*memoryRecord{},
+		authors:      map[int]*Author{},
+	}
+}
+
+// keepArticle records r, the article of id before the write changes it, r is nil when the write adds it
+func (u *memoryUndo) keepArticle(id int, r *memoryRecord) {
+	if u == nil {
+		return
+	}
+
+	if _, ok := u.articles[id]; ok {
+		return
+	}
+
+	if r == nil {
+		u.articles[id] = nil
+
+		return
+	}
+
+	c := *r
+	u.articles[id] = &c
+}
+
+// keepAuthor records a, the author of id before the write changes it, a is nil when the write adds it
+func (u *memoryUndo) keepAuthor(id int, a *Author) {
+	if u == nil {
+		return
+	}
+
+	if _, ok := u.authors[id]; ok {
+		return
+	}
+
+	if a == nil {
+		u.authors[id] = nil
+
+		return
+	}
+
+	c := *a
+	u.authors[id] = &c
+}
+
+// commit saves the snapshot, the records kept in before are put back when saving fails so callers
+// never see a write that is not persisted. Caller must hold the write lock
+func (m *memoryArticle) commit(before *memoryUndo) error {
+	err := m.save()
+	if err == nil || before == nil {
+		return err
+	}
+
+	m.nextID = before.nextID
+	m.nextAuthorID = before.nextAuthorID
+	m.nextChangeID = before.nextChangeID
+	m.changes = before.changes
+
+	for id, r := range before.articles {
+		if r == nil {
+			delete(m.articles, id)
+			m.index.Remove(id)
+
+			continue
+		}
+
+		m.articles[id] = r
+		m.index.Add(id, r.Article.Title, r.Article.Content)
+	}
+
+	for id, a := range before.authors {
+		if a == nil {
+			delete(m.authors, id)
+
+			continue
+		}
+
+		m.authors[id] = a
+	}
+
+	return err
+}
+
+// ids returns stored ids in ascending order
+func (m *memoryArticle) ids() []int {
+	ids := make([]int, 0, len(m.articles))
+	for id := range m.articles {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+// find returns the record of an article, soft deleted articles are skipped unless includeDeleted is set
+func (m *memoryArticle) find(articleID int, includeDeleted bool) (*memoryRecord, error) {
+	r, ok := m.articles[articleID]
+	if !ok || (r.DeletedAt != nil && !includeDeleted) {
+		return nil, ErrArticleNotFound
+	}
+
+	return r, nil
+}
+
+// Store stores article and assigns the next id
+func (m *memoryArticle) Store(ctx context.Context, article *Article) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	err := article.validate()
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before := m.backup()
+
+	// the author is looked up by name ignoring case
+	author, err := m.resolveAuthor(before, authorRef{article.Author, article.AuthorSubject, article.CreateAuthor})
+	if err != nil {
+		return 0, err
+	}
+
+	now := m.now()
+	id := m.nextID
+	m.nextID++
+
+	before.keepArticle(id, nil)
+
+	m.articles[id] = &memoryRecord{
+		Article: Article{
+			ID:        id,
+			Title:     article.Title,
+			Content:   article.Content,
+			Author:    author.Name,
+			AuthorID:  author.ID,
+			OwnerID:   article.OwnerID,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Status:    StatusDraft,
+		},
+	}
+
+	m.index.Add(id, article.Title, article.Content)
+
+	err = m.commit(before)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(id), nil
+}
+
+// GetByID fetches article by articleID, soft deleted articles are skipped unless includeDeleted is set.
+// ErrArticleNotFound is returned when no article matches
+func (m *memoryArticle) GetByID(ctx context.Context, articleID int, includeDeleted bool) (*Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, err := m.find(articleID, includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	article := r.Article
+
+	return &article, nil
+}
+
+// List fetches articles matching opts in sort order, a page is selected using offset or keyset cursor
+func (m *memoryArticle) List(ctx context.Context, opts ListOptions) ([]*Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	fields := opts.orderBy()
+	articles := m.filter(opts)
+
+	sort.Slice(articles, func(i, j int) bool {
+		return compareArticles(articles[i], articles[j], fields) < 0
+	})
+
+	// keyset cursors are positioned at the current values of the cursor article,
+	// like the mysql subqueries nothing matches when it no longer exists
+	for _, cursor := range []struct {
+		cursor *Cursor
+		after  bool
+	}{
+		{opts.After, true},
+		{opts.Before, false},
+	} {
+		if cursor.cursor == nil {
+			continue
+		}
+
+		var at *Article
+		if r, ok := m.articles[cursor.cursor.ID]; ok {
+			at = &r.Article
+		}
+
+		var kept []*Article
+
+		for _, article := range articles {
+			if at == nil {
+				break
+			}
+
+			cmp := compareArticles(article, at, fields)
+			if (cursor.after && cmp > 0) || (!cursor.after && cmp < 0) {
+				kept = append(kept, article)
+			}
+		}
+
+		articles = kept
+	}
+
+	return pageOf(articles, opts.Page), nil
+}
+
+// Count counts articles matching opts, sort and pagination fields are ignored
+func (m *memoryArticle) Count(ctx context.Context, opts ListOptions) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return int64(len(m.filter(opts))), nil
+}
+
+// filter returns copies of articles matching filter of opts
+func (m *memoryArticle) filter(opts ListOptions) []*Article {
+	f := opts.Filter
+
+	var articles []*Article
+
+	for _, id := range m.ids() {
+		r := m.articles[id]
+		a := r.Article
+
+		if r.DeletedAt != nil && !opts.IncludeDeleted {
+			continue
+		}
+
+		if len(f.Authors) > 0 && !containsFold(f.Authors, a.Author) {
+			continue
+		}
+
+		if f.AuthorID > 0 && a.AuthorID != f.AuthorID {
+			continue
+		}
+
+		if !withinBounds(a.CreatedAt, f.CreatedFrom, f.CreatedTo) || !withinBounds(a.UpdatedAt, f.UpdatedFrom, f.UpdatedTo) {
+			continue
+		}
+
+		if f.TitlePrefix != "" && !strings.HasPrefix(strings.ToLower(a.Title), strings.ToLower(f.TitlePrefix)) {
+			continue
+		}
+
+		if !matchesStatus(&a, f.Statuses, f.OwnerID) {
+			continue
+		}
+
+		articles = append(articles, &a)
+	}
+
+	return articles
+}
+
+// Search finds articles matching query using the inverted index, ordered by relevance
+func (m *memoryArticle) Search(ctx context.Context, opts SearchOptions) ([]*SearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if (opts.After != nil && opts.After.Score == nil) || (opts.Before != nil && opts.Before.Score == nil) {
+		return nil, ErrInvalidSearchCursor
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []*SearchResult
+
+	for _, hit := range m.hits(opts) {
+		// results after a cursor have lower score or same score and higher id
+		if c := opts.After; c != nil && !(hit.Score < *c.Score || (hit.Score == *c.Score && hit.ID > c.ID)) {
+			continue
+		}
+
+		if c := opts.Before; c != nil && !(hit.Score > *c.Score || (hit.Score == *c.Score && hit.ID < c.ID)) {
+			continue
+		}
+
+		article := m.articles[hit.ID].Article
+		results = append(results, &SearchResult{Article: &article, Score: hit.Score})
+	}
+
+	return pageOf(results, opts.Page), nil
+}
+
+// CountSearch counts articles matching query, pagination fields are ignored
+func (m *memoryArticle) CountSearch(ctx context.Context, opts SearchOptions) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return int64(len(m.hits(opts))), nil
+}
+
+// hits returns index hits of articles which are not soft deleted and match the filter of opts
+func (m *memoryArticle) hits(opts SearchOptions) []search.Hit {
+	var hits []search.Hit
+
+	for _, hit := range m.index.Search(opts.Query) {
+		r, ok := m.articles[hit.ID]
+		if !ok || r.DeletedAt != nil {
+			continue
+		}
+
+		if !matchesStatus(&r.Article, opts.Statuses, opts.OwnerID) {
+			continue
+		}
+
+		hits = append(hits, hit)
+	}
+
+	return hits
+}
+
+// Update replaces all editable fields of an article
+func (m *memoryArticle) Update(ctx context.Context, article *Article) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := article.validate()
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.find(article.ID, false)
+	if err != nil {
+		return err
+	}
+
+	before := m.backup()
+
+	author, err := m.resolveAuthor(before, authorRef{article.Author, article.AuthorSubject, article.CreateAuthor})
+	if err != nil {
+		return err
+	}
+
+	before.keepArticle(r.Article.ID, r)
+
+	r.Article.Title = article.Title
+	r.Article.Content = article.Content
+	r.Article.Author = author.Name
+	r.Article.AuthorID = author.ID
+	r.Article.UpdatedAt = m.now()
+
+	m.index.Add(r.Article.ID, r.Article.Title, r.Article.Content)
+
+	return m.commit(before)
+}
+
+// Patch updates only the fields set in patch
+func (m *memoryArticle) Patch(ctx context.Context, articleID int, patch *ArticlePatch) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for _, field := range []struct {
+		name string
+		val  *string
+		max  int
+	}{
+		{"title", patch.Title, 0},
+		{"content", patch.Content, 0},
+		{"author", patch.Author, maxAuthorLength},
+	} {
+		if field.val == nil {
+			continue
+		}
+
+		err := validateField(field.name, *field.val, field.max)
+		if err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.find(articleID, false)
+	if err != nil {
+		return err
+	}
+
+	// nothing to update
+	if patch.Title == nil && patch.Content == nil && patch.Author == nil {
+		return nil
+	}
+
+	before := m.backup()
+	before.keepArticle(r.Article.ID, r)
+
+	// the author is resolved first so an unknown author leaves the article unchanged
+	if patch.Author != nil {
+		author, err := m.resolveAuthor(before, authorRef{*patch.Author, patch.AuthorSubject, patch.CreateAuthor})
+		if err != nil {
+			return err
+		}
+
+		r.Article.Author = author.Name
+		r.Article.AuthorID = author.ID
+	}
+
+	if patch.Title != nil {
+		r.Article.Title = *patch.Title
+	}
+
+	if patch.Content != nil {
+		r.Article.Content = *patch.Content
+	}
+
+	r.Article.UpdatedAt = m.now()
+
+	m.index.Add(r.Article.ID, r.Article.Title, r.Article.Content)
+
+	return m.commit(before)
+}
+
+// Delete soft deletes an article, updated_at is left as is
+func (m *memoryArticle) Delete(ctx context.Context, articleID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.find(articleID, false)
+	if err != nil {
+		return err
+	}
+
+	before := m.backup()
+	before.keepArticle(r.Article.ID, r)
+
+	now := m.now()
+	r.DeletedAt = &now
+	r.Article.Deleted = true
+
+	return m.commit(before)
+}
+
+// Restore undoes a soft delete
+func (m *memoryArticle) Restore(ctx context.Context, articleID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.find(articleID, true)
+	if err != nil {
+		return err
+	}
+
+	if r.DeletedAt == nil {
+		return ErrArticleNotDeleted
+	}
+
+	before := m.backup()
+	before.keepArticle(r.Article.ID, r)
+
+	r.DeletedAt = nil
+	r.Article.Deleted = false
+
+	return m.commit(before)
+}
+
+// Purge permanently removes an article
+func (m *memoryArticle) Purge(ctx context.Context, articleID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.find(articleID, true)
+	if err != nil {
+		return err
+	}
+
+	before := m.backup()
+	before.keepArticle(articleID, r)
+
+	delete(m.articles, articleID)
+	m.index.Remove(articleID)
+
+	// the history goes with the article
+	var changes []*StatusChange
+	for _, c := range m.changes {
+		if c.ArticleID != articleID {
+			changes = append(changes, c)
+		}
+	}
+
+	m.changes = changes
+
+	return m.commit(before)
+}
+
+// compareArticles compares articles by sort fields, text is compared case insensitively
+func compareArticles(a, b *Article, fields []SortField) int {
+	for _, field := range fields {
+		var cmp int
+
+		switch field.Field {
+		case "id":
+			cmp = compareInts(a.ID, b.ID)
+		case "title":
+			cmp = strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		case "author":
+			cmp = strings.Compare(strings.ToLower(a.Author), strings.ToLower(b.Author))
+		case "created_at":
+			cmp = compareTimes(a.CreatedAt, b.CreatedAt)
+		case "updated_at":
+			cmp = compareTimes(a.UpdatedAt, b.UpdatedAt)
+		}
+
+		if field.Desc {
+			cmp = -cmp
+		}
+
+		if cmp != 0 {
+			return cmp
+		}
+	}
+
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+
+	return 0
+}
+
+// containsFold reports whether val equals any of values ignoring case
+func containsFold(values []string, val string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, val) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesStatus reports whether article has any of statuses, all statuses match when empty. Articles
+// which are not published must be owned by ownerID when it is set
+func matchesStatus(article *Article, statuses []Status, ownerID string) bool {
+	if len(statuses) > 0 && !containsStatus(statuses, article.Status) {
+		return false
+	}
+
+	return ownerID == "" || article.Status == StatusPublished || article.OwnerID == ownerID
+}
+
+// containsStatus reports whether statuses holds status
+func containsStatus(statuses []Status, status Status) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// utcPtr returns t in UTC, nil stays nil
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	u := t.UTC()
+
+	return &u
+}
+
+// withinBounds reports whether t lies in the inclusive range, nil bounds are open
+func withinBounds(t time.Time, from, to *time.Time) bool {
+	if from != nil && t.Before(*from) {
+		return false
+	}
+
+	if to != nil && t.After(*to) {
+		return false
+	}
+
+	return true
+}
+
+// pageOf selects a page out of sorted items, a page before a cursor is taken from its end
+func pageOf[T any](items []T, page Page) []T {
+	if page.Limit <= 0 {
+		return items
+	}
+
+	if page.Before != nil {
+		end := len(items) - page.Offset
+		if end < 0 {
+			end = 0
+		}
+
+		start := end - page.Limit
+		if start < 0 {
+			start = 0
+		}
+
+		return items[start:end]
+	}
+
+	start := page.Offset
+	if start > len(items) {
+		start = len(items)
+	}
+
+	end := start + page.Limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	return items[start:end]
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryAPIKey is a concurrency safe in-memory APIKeyStore, keys are not persisted to the snapshot
+// of the article store so they only live as long as the process
+type memoryAPIKey struct {
+	mu sync.RWMutex
+
+	nextID int
+	keys   map[int]*APIKey
+}
+
+// newMemoryAPIKey returns an empty store
+func newMemoryAPIKey() *memoryAPIKey {
+	return &memoryAPIKey{nextID: 1, keys: map[int]*APIKey{}}
+}
+
+// copyAPIKey returns a copy of key not sharing scopes or times
+func copyAPIKey(key *APIKey) *APIKey {
+	c := *key
+	c.Scopes = append([]string(nil), key.Scopes...)
+	c.ExpiresAt = copyTime(key.ExpiresAt)
+	c.LastUsedAt = copyTime(key.LastUsedAt)
+	c.RevokedAt = copyTime(key.RevokedAt)
+
+	return &c
+}
+
+// copyTime returns a copy of t truncated to the second precision of databases
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	c := t.UTC().Truncate(time.Second)
+
+	return &c
+}
+
+// Create stores key and assigns the next id, prefixes are unique
+func (m *memoryAPIKey) Create(ctx context.Context, key *APIKey) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	err := key.validate()
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, k := range m.keys {
+		if k.Prefix == key.Prefix {
+			return 0, newError(ErrConflict, "api key prefix already exists")
+		}
+	}
+
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now().UTC()
+	}
+
+	key.CreatedAt = key.CreatedAt.UTC().Truncate(time.Second)
+	key.ID = m.nextID
+	m.nextID++
+
+	m.keys[key.ID] = copyAPIKey(key)
+
+	return int64(key.ID), nil
+}
+
+// GetByID fetches key by keyID
+func (m *memoryAPIKey) GetByID(ctx context.Context, keyID int) (*APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key, ok := m.keys[keyID]
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	return copyAPIKey(key), nil
+}
+
+// GetByPrefix fetches key by its public prefix
+func (m *memoryAPIKey) GetByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.keys {
+		if key.Prefix == prefix {
+			return copyAPIKey(key), nil
+		}
+	}
+
+	return nil, ErrAPIKeyNotFound
+}
+
+// List fetches all keys including revoked and expired ones ordered by id
+func (m *memoryAPIKey) List(ctx context.Context) ([]*APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var keys []*APIKey
+	for _, key := range m.keys {
+		keys = append(keys, copyAPIKey(key))
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys, nil
+}
+
+// Revoke revokes key at given time, revoking a revoked key keeps the first revocation time
+func (m *memoryAPIKey) Revoke(ctx context.Context, keyID int, at time.Time) error {
+	return m.update(ctx, keyID, func(key *APIKey) {
+		if key.RevokedAt == nil {
+			key.RevokedAt = copyTime(&at)
+		}
+	})
+}
+
+// SetExpiry makes key expire at given time
+func (m *memoryAPIKey) SetExpiry(ctx context.Context, keyID int, at time.Time) error {
+	return m.update(ctx, keyID, func(key *APIKey) {
+		key.ExpiresAt = copyTime(&at)
+	})
+}
+
+// Touch records key was used at given time
+func (m *memoryAPIKey) Touch(ctx context.Context, keyID int, at time.Time) error {
+	return m.update(ctx, keyID, func(key *APIKey) {
+		key.LastUsedAt = copyTime(&at)
+	})
+}
+
+// update applies change to key of keyID
+func (m *memoryAPIKey) update(ctx context.Context, keyID int, change func(key *APIKey)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.keys[keyID]
+	if !ok {
+		return ErrAPIKeyNotFound
+	}
+
+	change(key)
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package models
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// memoryAuthor is the AuthorStore of an in-memory article store, authors share its lock and snapshot
+// as articles refer to them and take their names
+type memoryAuthor struct {
+	m *memoryArticle
+}
+
+// authorIDs returns stored author ids in ascending order, caller must hold the lock
+func (m *memoryArticle) authorIDs() []int {
+	ids := make([]int, 0, len(m.authors))
+	for id := range m.authors {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+// authorNamed returns author named name ignoring case, nil when there is none. Caller must hold the lock
+func (m *memoryArticle) authorNamed(name string) *Author {
+	for _, id := range m.authorIDs() {
+		if strings.EqualFold(m.authors[id].Name, name) {
+			return m.authors[id]
+		}
+	}
+
+	return nil
+}
+
+// authorOf returns author linked to subject, nil when there is none. Caller must hold the lock
+func (m *memoryArticle) authorOf(subject string) *Author {
+	for _, id := range m.authorIDs() {
+		if m.authors[id].Subject == subject {
+			return m.authors[id]
+		}
+	}
+
+	return nil
+}
+
+// resolveAuthor returns the author of ref like the author store of the database does, authors it changes
+// are kept in before. Caller must hold the write lock
+func (m *memoryArticle) resolveAuthor(before *memoryUndo, ref authorRef) (*Author, error) {
+	if ref.subject != "" {
+		if author := m.authorOf(ref.subject); author != nil {
+			return author, nil
+		}
+	}
+
+	if author := m.authorNamed(ref.name); author != nil {
+		if ref.subject == "" {
+			return author, nil
+		}
+
+		if author.Subject != "" {
+			return nil, ErrAuthorTaken
+		}
+
+		before.keepAuthor(author.ID, author)
+		author.Subject = ref.subject
+
+		return author, nil
+	}
+
+	if !ref.create && ref.subject == "" {
+		return nil, ErrUnknownAuthor
+	}
+
+	now := m.now()
+	author := &Author{ID: m.nextAuthorID, Name: ref.name, Subject: ref.subject, CreatedAt: now, UpdatedAt: now}
+
+	before.keepAuthor(author.ID, nil)
+	m.authors[author.ID] = author
+	m.nextAuthorID++
+
+	return author, nil
+}
+
+// Create stores author and assigns the next id, names are unique ignoring case
+func (s *memoryAuthor) Create(ctx context.Context, author *Author) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	err := author.validate()
+	if err != nil {
+		return 0, err
+	}
+
+	m := s.m
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.authorNamed(author.Name) != nil || (author.Subject != "" && m.authorOf(author.Subject) != nil) {
+		return 0, newError(ErrConflict, "record already exists")
+	}
+
+	before := m.backup()
+
+	now := m.now()
+	stored := *author
+	stored.ID = m.nextAuthorID
+	stored.CreatedAt = now
+	stored.UpdatedAt = now
+
+	before.keepAuthor(stored.ID, nil)
+	m.authors[stored.ID] = &stored
+	m.nextAuthorID++
+
+	err = m.commit(before)
+	if err != nil {
+		return 0, err
+	}
+
+	author.ID = stored.ID
+
+	return int64(stored.ID), nil
+}
+
+// GetByID fetches author by authorID
+func (s *memoryAuthor) GetByID(ctx context.Context, authorID int) (*Author, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	author, ok := s.m.authors[authorID]
+	if !ok {
+		return nil, ErrAuthorNotFound
+	}
+
+	c := *author
+
+	return &c, nil
+}
+
+// GetByIDs fetches authors of authorIDs ordered by id, ids without author are skipped
+func (s *memoryAuthor) GetByIDs(ctx context.Context, authorIDs []int) ([]*Author, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	wanted := map[int]bool{}
+	for _, id := range authorIDs {
+		wanted[id] = true
+	}
+
+	var authors []*Author
+
+	for _, id := range s.m.authorIDs() {
+		if wanted[id] {
+			c := *s.m.authors[id]
+			authors = append(authors, &c)
+		}
+	}
+
+	return authors, nil
+}
+
+// List fetches a page of authors ordered by id, a page is selected using offset or keyset cursor
+func (s *memoryAuthor) List(ctx context.Context, page Page) ([]*Author, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var authors []*Author
+
+	for _, id := range s.m.authorIDs() {
+		if (page.After != nil && id <= page.After.ID) || (page.Before != nil && id >= page.Before.ID) {
+			continue
+		}
+
+		c := *s.m.authors[id]
+		authors = append(authors, &c)
+	}
+
+	return pageOf(authors, page), nil
+}
+
+// Count counts all authors
+func (s *memoryAuthor) Count(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	return int64(len(s.m.authors)), nil
+}
+
+// Update replaces all editable fields of an author, articles of the author take the new name
+func (s *memoryAuthor) Update(ctx context.Context, author *Author) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := author.validate()
+	if err != nil {
+		return err
+	}
+
+	m := s.m
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.authors[author.ID]
+	if !ok {
+		return ErrAuthorNotFound
+	}
+
+	if other := m.authorNamed(author.Name); other != nil && other.ID != author.ID {
+		return newError(ErrConflict, "record already exists")
+	}
+
+	before := m.backup()
+	before.keepAuthor(stored.ID, stored)
+
+	stored.Name = author.Name
+	stored.Bio = author.Bio
+	stored.AvatarURL = author.AvatarURL
+	stored.UpdatedAt = m.now()
+
+	// updated_at of articles tracks content changes, keep it as is
+	for _, r := range m.articles {
+		if r.Article.AuthorID == author.ID {
+			before.keepArticle(r.Article.ID, r)
+			r.Article.Author = author.Name
+		}
+	}
+
+	return m.commit(before)
+}
+
+// Delete removes an author, ErrAuthorHasArticles is returned while articles refer to it
+func (s *memoryAuthor) Delete(ctx context.Context, authorID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m := s.m
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.authors[authorID]; !ok {
+		return ErrAuthorNotFound
+	}
+
+	for _, r := range m.articles {
+		if r.Article.AuthorID == authorID {
+			return ErrAuthorHasArticles
+		}
+	}
+
+	before := m.backup()
+	before.keepAuthor(authorID, m.authors[authorID])
+
+	delete(m.authors, authorID)
+
+	return m.commit(before)
+}
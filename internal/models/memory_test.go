@@ -0,0 +1,233 @@
+package models_test
+
+import (
+	"article/internal/models"
+	"article/internal/models/storetest"
+	"article/internal/search"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MemoryConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) models.ArticleStore {
+		m, err := models.NewMemoryModels("")
+		require.NoError(t, err)
+
+		return m.Article
+	})
+
+	storetest.RunAPIKeys(t, func(t *testing.T) models.APIKeyStore {
+		m, err := models.NewMemoryModels("")
+		require.NoError(t, err)
+
+		return m.APIKey
+	})
+
+	storetest.RunAuthors(t, func(t *testing.T) *models.Models {
+		m, err := models.NewMemoryModels("")
+		require.NoError(t, err)
+
+		return m
+	})
+}
+
+func Test_MemorySnapshot(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "articles.json")
+
+	m, err := models.NewMemoryModels(path)
+	require.NoError(t, err)
+
+	first, err := m.Article.Store(ctx, &models.Article{Title: "First title", Content: "Worker pools", Author: "Jane", CreateAuthor: true})
+	require.NoError(t, err)
+
+	second, err := m.Article.Store(ctx, &models.Article{Title: "Second title", Content: "Channels", Author: "John", CreateAuthor: true})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Article.Delete(ctx, int(first)))
+	require.NoError(t, m.Article.Purge(ctx, int(second)))
+
+	// reopen store from snapshot
+	m, err = models.NewMemoryModels(path)
+	require.NoError(t, err)
+
+	got, err := m.Article.GetByID(ctx, int(first), true)
+	require.NoError(t, err)
+	assert.Equal(t, got.Title, "First title")
+	assert.True(t, got.Deleted)
+
+	_, err = m.Article.GetByID(ctx, int(second), true)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	// purged id is not reused after reload
+	third, err := m.Article.Store(ctx, &models.Article{Title: "Third title", Content: "Worker pools", Author: "Jane", CreateAuthor: true})
+	require.NoError(t, err)
+	assert.Greater(t, third, second)
+
+	// search index is rebuilt from snapshot
+	require.NoError(t, m.Article.Restore(ctx, int(first)))
+
+	total, err := m.Article.CountSearch(ctx, models.SearchOptions{Query: search.Parse("worker pools")})
+	require.NoError(t, err)
+	assert.Equal(t, total, int64(2))
+
+	// temporary files are cleaned up
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func Test_MemorySnapshotWorkflow(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "articles.json")
+
+	// snapshots written before the workflow hold public articles without status
+	legacy := `{"next_id":2,"articles":[{"id":1,"title":"Old title","content":"Old content","author":"Jane",` +
+		`"created_at":"2023-01-01T10:00:00Z","updated_at":"2023-01-01T10:00:00Z"}]}`
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0o600))
+
+	m, err := models.NewMemoryModels(path)
+	require.NoError(t, err)
+
+	old, err := m.Article.GetByID(ctx, 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, old.Status, models.StatusPublished)
+	require.NotNil(t, old.PublishedAt)
+	assert.Equal(t, *old.PublishedAt, old.CreatedAt)
+
+	id, err := m.Article.Store(ctx, &models.Article{Title: "New title", Content: "New content", Author: "Jane", CreateAuthor: true})
+	require.NoError(t, err)
+
+	submit := &models.StatusChange{Transition: models.Submit, From: models.StatusDraft, To: models.StatusInReview, Actor: "u-1", Comment: "please review"}
+	require.NoError(t, m.Article.Transition(ctx, int(id), submit))
+
+	// statuses and history survive a reload
+	m, err = models.NewMemoryModels(path)
+	require.NoError(t, err)
+
+	got, err := m.Article.GetByID(ctx, int(id), false)
+	require.NoError(t, err)
+	assert.Equal(t, got.Status, models.StatusInReview)
+
+	history, err := m.Article.History(ctx, int(id))
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, history[0].Comment, "please review")
+
+	// change ids are not reused after reload
+	publish := &models.StatusChange{Transition: models.Publish, From: models.StatusInReview, To: models.StatusPublished, Actor: "u-9"}
+	require.NoError(t, m.Article.Transition(ctx, int(id), publish))
+	assert.Greater(t, publish.ID, history[0].ID)
+}
+
+func Test_MemorySnapshotFailure(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "data")
+	require.NoError(t, os.Mkdir(dir, 0o700))
+
+	m, err := models.NewMemoryModels(filepath.Join(dir, "articles.json"))
+	require.NoError(t, err)
+
+	id, err := m.Article.Store(ctx, &models.Article{Title: "First title", Content: "Worker pools", Author: "Jane", CreateAuthor: true})
+	require.NoError(t, err)
+
+	// writes fail while the snapshot cannot be saved and leave the store as it was
+	require.NoError(t, os.RemoveAll(dir))
+
+	_, err = m.Article.Store(ctx, &models.Article{Title: "Second title", Content: "Channels", Author: "John", CreateAuthor: true})
+	assert.Error(t, err)
+
+	assert.Error(t, m.Article.Update(ctx, &models.Article{ID: int(id), Title: "New title", Content: "Mutexes", Author: "John", CreateAuthor: true}))
+	assert.Error(t, m.Article.Transition(ctx, int(id), &models.StatusChange{Transition: models.Submit, From: models.StatusDraft, To: models.StatusInReview, Actor: "u-1"}))
+	assert.Error(t, m.Article.Delete(ctx, int(id)))
+	assert.Error(t, m.Article.Purge(ctx, int(id)))
+
+	_, err = m.Author.Create(ctx, &models.Author{Name: "Ann"})
+	assert.Error(t, err)
+
+	jane, err := m.Article.GetByID(ctx, int(id), false)
+	require.NoError(t, err)
+	assert.Error(t, m.Author.Update(ctx, &models.Author{ID: jane.AuthorID, Name: "Janet"}))
+	assert.Error(t, m.Article.Patch(ctx, int(id), &models.ArticlePatch{Author: &jane.Author, AuthorSubject: "u-1"}))
+
+	author, err := m.Author.GetByID(ctx, jane.AuthorID)
+	require.NoError(t, err)
+	assert.Equal(t, author.Name, "Jane")
+	assert.Empty(t, author.Subject)
+
+	got, err := m.Article.GetByID(ctx, int(id), false)
+	require.NoError(t, err)
+	assert.Equal(t, got.Title, "First title")
+	assert.Equal(t, got.Author, "Jane")
+	assert.Equal(t, got.Status, models.StatusDraft)
+
+	history, err := m.Article.History(ctx, int(id))
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	authors, err := m.Author.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, authors, int64(1))
+
+	total, err := m.Article.CountSearch(ctx, models.SearchOptions{Query: search.Parse("mutexes")})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	// ids of failed writes are not taken
+	require.NoError(t, os.Mkdir(dir, 0o700))
+
+	next, err := m.Article.Store(ctx, &models.Article{Title: "Second title", Content: "Channels", Author: "Jane"})
+	require.NoError(t, err)
+	assert.Equal(t, next, id+1)
+}
+
+func Test_MemorySnapshotInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "articles.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+	_, err := models.NewMemoryModels(path)
+	assert.Error(t, err)
+}
+
+func Test_MemoryConcurrency(t *testing.T) {
+	ctx := context.Background()
+
+	m, err := models.NewMemoryModels("")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			id, err := m.Article.Store(ctx, &models.Article{Title: "Test title", Content: "Test content", Author: "Jane", CreateAuthor: true})
+			assert.NoError(t, err)
+
+			_, err = m.Article.List(ctx, models.ListOptions{})
+			assert.NoError(t, err)
+
+			title := "New title"
+			assert.NoError(t, m.Article.Patch(ctx, int(id), &models.ArticlePatch{Title: &title}))
+		}()
+	}
+
+	wg.Wait()
+
+	articles, err := m.Article.List(ctx, models.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, articles, 20)
+
+	// ids are assigned in sequence
+	for i, a := range articles {
+		assert.Equal(t, a.ID, i+1)
+	}
+}
@@ -0,0 +1,82 @@
+package models
+
+import "context"
+
+// Transition moves an article along change and records change in its history, published_at is set on publishing.
+// The article must be in change.From, updated_at is left as is
+func (m *memoryArticle) Transition(ctx context.Context, articleID int, change *StatusChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := change.validate()
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.find(articleID, false)
+	if err != nil {
+		return err
+	}
+
+	if r.Article.Status != change.From {
+		return statusConflict(change, r.Article.Status)
+	}
+
+	before := m.backup()
+	before.keepArticle(articleID, r)
+
+	now := m.now()
+
+	r.Article.Status = change.To
+	if change.To == StatusPublished {
+		r.Article.PublishedAt = &now
+	}
+
+	stored := *change
+	stored.ID = m.nextChangeID
+	stored.ArticleID = articleID
+	stored.CreatedAt = now
+
+	m.changes = append(m.changes, &stored)
+	m.nextChangeID++
+
+	err = m.commit(before)
+	if err != nil {
+		return err
+	}
+
+	change.ID = stored.ID
+	change.ArticleID = articleID
+
+	return nil
+}
+
+// History fetches status changes of an article oldest first, ErrArticleNotFound is returned when no article matches
+func (m *memoryArticle) History(ctx context.Context, articleID int) ([]*StatusChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, err := m.find(articleID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []*StatusChange{}
+
+	for _, c := range m.changes {
+		if c.ArticleID == articleID {
+			stored := *c
+			changes = append(changes, &stored)
+		}
+	}
+
+	return changes, nil
+}
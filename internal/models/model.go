@@ -1,15 +1,27 @@
 package models
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+)
 
 // Application holds database object
 type Application struct {
 	db *sql.DB
 }
 
-// Models holds article interface
+// querier runs queries on the database or in a transaction
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Models holds article, author and API key interfaces
 type Models struct {
 	Article ArticleStore
+	Author  AuthorStore
+	APIKey  APIKeyStore
 }
 
 // NewModels store db object and return models
@@ -17,6 +29,30 @@ func NewModels(db *sql.DB) *Models {
 	app := Application{db: db}
 
 	return &Models{
-		Article: &article{app: &app},
+		Article: &article{app: &app, dialect: mysqlDialect},
+		Author:  &author{app: &app, dialect: mysqlDialect},
+		APIKey:  &apiKey{app: &app, dialect: mysqlDialect},
+	}
+}
+
+// NewSQLiteModels returns models backed by a SQLite database migrated with the sqlite migrations
+func NewSQLiteModels(db *sql.DB) *Models {
+	app := Application{db: db}
+
+	return &Models{
+		Article: &article{app: &app, dialect: sqliteDialect},
+		Author:  &author{app: &app, dialect: sqliteDialect},
+		APIKey:  &apiKey{app: &app, dialect: sqliteDialect},
+	}
+}
+
+// NewPostgresModels returns models backed by a PostgreSQL database migrated with the postgres migrations
+func NewPostgresModels(db *sql.DB) *Models {
+	app := Application{db: db}
+
+	return &Models{
+		Article: &article{app: &app, dialect: postgresDialect},
+		Author:  &author{app: &app, dialect: postgresDialect},
+		APIKey:  &apiKey{app: &app, dialect: postgresDialect},
 	}
 }
@@ -0,0 +1,56 @@
+package models_test
+
+import (
+	"article/internal/migrations"
+	"article/internal/models"
+	"article/internal/models/storetest"
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_MySQLConformance runs the store conformance suite against a real database,
+// MYSQL_TEST_DSN must point to an empty database e.g.
+// root:root@tcp(localhost:3306)/article_test?parseTime=true&loc=UTC&clientFoundRows=true
+func Test_MySQLConformance(t *testing.T) {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { db.Close() })
+
+	_, err = migrations.New(db).Up(context.Background())
+	require.NoError(t, err)
+
+	storetest.Run(t, func(t *testing.T) models.ArticleStore {
+		_, err := db.Exec("DELETE FROM article")
+		require.NoError(t, err)
+
+		return models.NewModels(db).Article
+	})
+
+	storetest.RunAPIKeys(t, func(t *testing.T) models.APIKeyStore {
+		_, err := db.Exec("DELETE FROM api_key")
+		require.NoError(t, err)
+
+		return models.NewModels(db).APIKey
+	})
+
+	storetest.RunAuthors(t, func(t *testing.T) *models.Models {
+		_, err := db.Exec("DELETE FROM article")
+		require.NoError(t, err)
+
+		_, err = db.Exec("DELETE FROM author")
+		require.NoError(t, err)
+
+		return models.NewModels(db)
+	})
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"article/internal/search"
+	"context"
+	"strings"
+)
+
+// ErrInvalidSearchCursor is returned when a search is paged using a cursor without score
+var ErrInvalidSearchCursor = newError(ErrInvalidInput, "invalid search cursor")
+
+// SearchOptions holds options to search articles
+type SearchOptions struct {
+	Query search.Query
+
+	// Statuses matches any of given statuses, all statuses match when empty
+	Statuses []Status
+
+	// OwnerID limits articles which are not published to those of the owner when not empty
+	OwnerID string
+
+	Page
+}
+
+// SearchResult holds a matching article with its relevance score
+type SearchResult struct {
+	Article *Article
+	Score   float64
+}
+
+// booleanMode returns MySQL boolean mode expression requiring all terms and phrases,
+// tokens hold only letters and digits so they cannot inject operators
+func booleanMode(q search.Query) string {
+	var parts []string
+
+	for _, term := range q.Terms {
+		parts = append(parts, "+"+term)
+	}
+
+	for _, phrase := range q.Phrases {
+		parts = append(parts, `+"`+strings.Join(phrase, " ")+`"`)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// filter returns conditions on status and owner of matching articles with their args
+func (opts SearchOptions) filter() ([]string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	if condition, statusArgs := statusCondition(opts.Statuses, opts.OwnerID); condition != "" {
+		conditions = append(conditions, condition)
+		args = append(args, statusArgs...)
+	}
+
+	return conditions, args
+}
+
+// Search finds articles matching query using the full text index, ordered by relevance
+func (a *article) Search(ctx context.Context, opts SearchOptions) ([]*SearchResult, error) {
+	match, args := a.dialect.matchQuery(opts.Query)
+
+	var (
+		conditions []string
+		order      = "score DESC, id ASC"
+	)
+
+	filter, filterArgs := opts.filter()
+	conditions = append(conditions, filter...)
+	args = append(args, filterArgs...)
+
+	// keyset cursors, results after a cursor have lower score or same score and higher id
+	if opts.After != nil {
+		if opts.After.Score == nil {
+			return nil, ErrInvalidSearchCursor
+		}
+
+		conditions = append(conditions, "(score < ? OR (score = ? AND id > ?))")
+		args = append(args, *opts.After.Score, *opts.After.Score, opts.After.ID)
+	}
+
+	if opts.Before != nil {
+		if opts.Before.Score == nil {
+			return nil, ErrInvalidSearchCursor
+		}
+
+		// read backwards from cursor, rows are reversed after scanning
+		conditions = append(conditions, "(score > ? OR (score = ? AND id < ?))")
+		args = append(args, *opts.Before.Score, *opts.Before.Score, opts.Before.ID)
+		order = "score ASC, id DESC"
+	}
+
+	query := "SELECT id, title, content, author, created_at, updated_at, deleted, owner_id, author_id, status, published_at, score FROM (\n\t\t" + match + "\n\t) AS result"
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY " + order
+
+	if opts.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, opts.Limit)
+
+		if opts.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, opts.Offset)
+		}
+	}
+
+	row, err := a.app.db.QueryContext(ctx, a.dialect.statement(ctx, query), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer row.Close()
+
+	var results []*SearchResult
+
+	for row.Next() {
+		var (
+			article Article
+			result  = SearchResult{Article: &article}
+		)
+
+		err = row.Scan(append(articleFields(&article), &result.Score)...)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &result)
+	}
+
+	// error which ended iteration early
+	err = row.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Before != nil {
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+	}
+
+	return results, nil
+}
+
+// CountSearch counts articles matching query, pagination fields are ignored
+func (a *article) CountSearch(ctx context.Context, opts SearchOptions) (int64, error) {
+	query, args := a.dialect.countQuery(opts.Query)
+
+	// count queries end with their where clause
+	filter, filterArgs := opts.filter()
+	for _, condition := range filter {
+		query += " AND " + condition
+	}
+
+	args = append(args, filterArgs...)
+
+	var total int64
+
+	err := a.app.db.QueryRowContext(ctx, a.dialect.statement(ctx, query), args...).Scan(&total)
+
+	return total, err
+}
@@ -0,0 +1,174 @@
+package models_test
+
+import (
+	"article/internal/models"
+	"article/internal/search"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Search(t *testing.T) {
+	score := 0.5
+	createdAt := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		opts       models.SearchOptions
+		mockDB     func() *sql.DB
+		wantIDs    []int
+		wantErr    error
+		wantScores []float64
+	}{
+		{
+			name: "success",
+			opts: models.SearchOptions{Query: search.Parse(`go "worker pool"`), Page: models.Page{Limit: 2}},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock return valid rows
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "created_at", "updated_at", "deleted", "owner_id", "author_id", "status", "published_at", "score"}).
+					AddRow(int64(3), "Test title", "Test content", "Test author", createdAt, createdAt, false, "", int64(1), "published", nil, 1.5).
+					AddRow(int64(1), "Test title", "Test content", "Test author", createdAt, createdAt, false, "", int64(1), "published", nil, 0.5)
+				mock.ExpectQuery(regexp.QuoteMeta("AGAINST(? IN BOOLEAN MODE)\n\t) AS result ORDER BY score DESC, id ASC LIMIT ?")).
+					WithArgs(`+go +"worker pool"`, `+go +"worker pool"`, 2).
+					WillReturnRows(rows)
+
+				return db
+			},
+			wantIDs:    []int{3, 1},
+			wantScores: []float64{1.5, 0.5},
+		},
+		{
+			name: "success : page before cursor",
+			opts: models.SearchOptions{Query: search.Parse("go"), Page: models.Page{Limit: 2, Before: &models.Cursor{ID: 4, Score: &score}}},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock return rows in reverse order
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "created_at", "updated_at", "deleted", "owner_id", "author_id", "status", "published_at", "score"}).
+					AddRow(int64(1), "Test title", "Test content", "Test author", createdAt, createdAt, false, "", int64(1), "published", nil, 1.0).
+					AddRow(int64(2), "Test title", "Test content", "Test author", createdAt, createdAt, false, "", int64(1), "published", nil, 2.0)
+				mock.ExpectQuery(regexp.QuoteMeta("AS result WHERE (score > ? OR (score = ? AND id < ?)) ORDER BY score ASC, id DESC LIMIT ?")).
+					WithArgs("+go", "+go", score, score, 4, 2).
+					WillReturnRows(rows)
+
+				return db
+			},
+			wantIDs:    []int{2, 1},
+			wantScores: []float64{2.0, 1.0},
+		},
+		{
+			name: "success : statuses and owner",
+			opts: models.SearchOptions{Query: search.Parse("go"), Statuses: []models.Status{models.StatusDraft, models.StatusInReview}, OwnerID: "u-1"},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				rows := sqlmock.NewRows([]string{"id", "title", "content", "author", "created_at", "updated_at", "deleted", "owner_id", "author_id", "status", "published_at", "score"}).
+					AddRow(int64(5), "Test title", "Test content", "Test author", createdAt, createdAt, false, "u-1", int64(1), "draft", nil, 1.0)
+				mock.ExpectQuery(regexp.QuoteMeta("AS result WHERE status IN (?, ?) AND owner_id = ? ORDER BY score DESC, id ASC")).
+					WithArgs("+go", "+go", models.StatusDraft, models.StatusInReview, "u-1").
+					WillReturnRows(rows)
+
+				return db
+			},
+			wantIDs:    []int{5},
+			wantScores: []float64{1.0},
+		},
+		{
+			name: "error : cursor without score",
+			opts: models.SearchOptions{Query: search.Parse("go"), Page: models.Page{After: &models.Cursor{ID: 4}}},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection without expectations
+				db, _, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				return db
+			},
+			wantErr: models.ErrInvalidSearchCursor,
+		},
+		{
+			name: "error : select query error",
+			opts: models.SearchOptions{Query: search.Parse("go")},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// mock return error
+				mock.ExpectQuery("MATCH").WillReturnError(errors.New("db error"))
+
+				return db
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.mockDB()
+
+			// store mocked db object in models
+			a := models.NewModels(db)
+
+			// call model function
+			gotResp, err := a.Article.Search(context.Background(), tt.opts)
+			assert.Equal(t, err, tt.wantErr)
+
+			var (
+				gotIDs    []int
+				gotScores []float64
+			)
+
+			for _, result := range gotResp {
+				gotIDs = append(gotIDs, result.Article.ID)
+				gotScores = append(gotScores, result.Score)
+			}
+
+			assert.Equal(t, gotIDs, tt.wantIDs)
+			assert.Equal(t, gotScores, tt.wantScores)
+		})
+	}
+}
+
+func Test_CountSearch(t *testing.T) {
+	// create sql mock database connection
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection %v", err)
+	}
+
+	// mock return count
+	rows := sqlmock.NewRows([]string{"count"}).AddRow(int64(3))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM article")).WithArgs("+go +channels").WillReturnRows(rows)
+
+	// store mocked db object in models
+	a := models.NewModels(db)
+
+	gotTotal, err := a.Article.CountSearch(context.Background(), models.SearchOptions{Query: search.Parse("Go channels")})
+
+	assert.Nil(t, err)
+	assert.Equal(t, gotTotal, int64(3))
+}
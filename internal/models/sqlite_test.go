@@ -0,0 +1,43 @@
+package models_test
+
+import (
+	"article/internal/migrations"
+	"article/internal/models"
+	"article/internal/models/storetest"
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteModels returns models of a migrated database, every test gets its own database file
+// as in-memory databases are private to a connection
+func sqliteModels(t *testing.T) *models.Models {
+	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_txlock=immediate", filepath.Join(t.TempDir(), "article.db"))
+
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { db.Close() })
+
+	_, err = migrations.NewSQLite(db).Up(context.Background())
+	require.NoError(t, err)
+
+	return models.NewSQLiteModels(db)
+}
+
+func Test_SQLiteConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) models.ArticleStore {
+		return sqliteModels(t).Article
+	})
+
+	storetest.RunAPIKeys(t, func(t *testing.T) models.APIKeyStore {
+		return sqliteModels(t).APIKey
+	})
+
+	storetest.RunAuthors(t, sqliteModels)
+}
@@ -0,0 +1,153 @@
+package storetest
+
+import (
+	"article/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// NewAPIKeyStore returns an empty API key store, it is called once per test
+type NewAPIKeyStore func(t *testing.T) models.APIKeyStore
+
+// RunAPIKeys runs the conformance suite against API key stores returned by newStore
+func RunAPIKeys(t *testing.T, newStore NewAPIKeyStore) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, store models.APIKeyStore)
+	}{
+		{"create and get", testKeyCreateAndGet},
+		{"create validation", testKeyCreateValidation},
+		{"list", testKeyList},
+		{"revoke, expire and touch", testKeyUpdates},
+		{"missing key", testKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStore(t))
+		})
+	}
+}
+
+// newKey returns a valid key with given prefix
+func newKey(prefix string) *models.APIKey {
+	return &models.APIKey{
+		Name:   "importer",
+		Prefix: prefix,
+		Hash:   strings.Repeat("a", 64),
+		Scopes: []string{"articles:read", "articles:write"},
+	}
+}
+
+func testKeyCreateAndGet(t *testing.T, s models.APIKeyStore) {
+	expires := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	key := newKey("p1")
+	key.ExpiresAt = &expires
+
+	id, err := s.Create(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, int(id))
+	assert.False(t, key.CreatedAt.IsZero())
+
+	got, err := s.GetByID(ctx, int(id))
+	require.NoError(t, err)
+	assert.Equal(t, got.Name, "importer")
+	assert.Equal(t, got.Prefix, "p1")
+	assert.Equal(t, got.Hash, key.Hash)
+	assert.Equal(t, got.Scopes, []string{"articles:read", "articles:write"})
+	assert.Equal(t, got.CreatedAt, key.CreatedAt)
+	require.NotNil(t, got.ExpiresAt)
+	assert.Equal(t, *got.ExpiresAt, expires)
+	assert.Nil(t, got.LastUsedAt)
+	assert.Nil(t, got.RevokedAt)
+
+	byPrefix, err := s.GetByPrefix(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, byPrefix, got)
+
+	// prefixes are unique
+	_, err = s.Create(ctx, newKey("p1"))
+	assert.ErrorIs(t, err, models.ErrConflict)
+}
+
+func testKeyCreateValidation(t *testing.T, s models.APIKeyStore) {
+	tests := []struct {
+		name   string
+		change func(key *models.APIKey)
+	}{
+		{"name missing", func(key *models.APIKey) { key.Name = "" }},
+		{"prefix missing", func(key *models.APIKey) { key.Prefix = "" }},
+		{"hash too long", func(key *models.APIKey) { key.Hash += "a" }},
+		{"scopes missing", func(key *models.APIKey) { key.Scopes = nil }},
+	}
+
+	for _, tt := range tests {
+		key := newKey("p1")
+		tt.change(key)
+
+		_, err := s.Create(ctx, key)
+		assert.ErrorIs(t, err, models.ErrValidation, tt.name)
+	}
+}
+
+func testKeyList(t *testing.T, s models.APIKeyStore) {
+	keys, err := s.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	for _, prefix := range []string{"p1", "p2", "p3"} {
+		_, err := s.Create(ctx, newKey(prefix))
+		require.NoError(t, err)
+	}
+
+	keys, err = s.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+
+	for i, prefix := range []string{"p1", "p2", "p3"} {
+		assert.Equal(t, keys[i].Prefix, prefix)
+	}
+}
+
+func testKeyUpdates(t *testing.T, s models.APIKeyStore) {
+	id, err := s.Create(ctx, newKey("p1"))
+	require.NoError(t, err)
+
+	used := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, s.Touch(ctx, int(id), used))
+
+	expires := used.Add(time.Hour)
+	require.NoError(t, s.SetExpiry(ctx, int(id), expires))
+
+	revoked := used.Add(time.Minute)
+	require.NoError(t, s.Revoke(ctx, int(id), revoked))
+
+	// revoking again keeps the first revocation time
+	require.NoError(t, s.Revoke(ctx, int(id), revoked.Add(time.Minute)))
+
+	got, err := s.GetByID(ctx, int(id))
+	require.NoError(t, err)
+	require.NotNil(t, got.LastUsedAt)
+	assert.Equal(t, *got.LastUsedAt, used)
+	require.NotNil(t, got.ExpiresAt)
+	assert.Equal(t, *got.ExpiresAt, expires)
+	require.NotNil(t, got.RevokedAt)
+	assert.Equal(t, *got.RevokedAt, revoked)
+}
+
+func testKeyNotFound(t *testing.T, s models.APIKeyStore) {
+	_, err := s.GetByID(ctx, 42)
+	assert.ErrorIs(t, err, models.ErrAPIKeyNotFound)
+
+	_, err = s.GetByPrefix(ctx, "missing")
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	assert.ErrorIs(t, s.Revoke(ctx, 42, time.Now()), models.ErrNotFound)
+	assert.ErrorIs(t, s.SetExpiry(ctx, 42, time.Now()), models.ErrNotFound)
+	assert.ErrorIs(t, s.Touch(ctx, 42, time.Now()), models.ErrNotFound)
+}
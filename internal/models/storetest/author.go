@@ -0,0 +1,311 @@
+package storetest
+
+import (
+	"article/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// NewModels returns empty article and author stores sharing a database, it is called once per test
+type NewModels func(t *testing.T) *models.Models
+
+// RunAuthors runs the conformance suite against author stores of models returned by newModels
+func RunAuthors(t *testing.T, newModels NewModels) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, m *models.Models)
+	}{
+		{"create and get", testAuthorCreateAndGet},
+		{"articles resolve authors", testAuthorResolve},
+		{"articles resolve authors of subjects", testAuthorSubject},
+		{"list", testAuthorList},
+		{"update", testAuthorUpdate},
+		{"delete", testAuthorDelete},
+		{"articles of author", testArticlesOfAuthor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newModels(t))
+		})
+	}
+}
+
+// createAuthors creates authors named names and returns their ids
+func createAuthors(t *testing.T, s models.AuthorStore, names ...string) []int {
+	t.Helper()
+
+	var ids []int
+
+	for _, name := range names {
+		id, err := s.Create(ctx, &models.Author{Name: name})
+		require.NoError(t, err)
+
+		ids = append(ids, int(id))
+	}
+
+	return ids
+}
+
+// authorIDs returns ids of authors in order
+func authorIDs(authors []*models.Author) []int {
+	ids := []int{}
+	for _, a := range authors {
+		ids = append(ids, a.ID)
+	}
+
+	return ids
+}
+
+func testAuthorCreateAndGet(t *testing.T, m *models.Models) {
+	author := &models.Author{Name: "Jane Doe", Bio: "Writes about Go", AvatarURL: "https://example.com/jane.png"}
+
+	id, err := m.Author.Create(ctx, author)
+	require.NoError(t, err)
+	assert.Equal(t, author.ID, int(id))
+
+	got, err := m.Author.GetByID(ctx, int(id))
+	require.NoError(t, err)
+	assert.Equal(t, got.Name, "Jane Doe")
+	assert.Equal(t, got.Bio, "Writes about Go")
+	assert.Equal(t, got.AvatarURL, "https://example.com/jane.png")
+	assert.False(t, got.CreatedAt.IsZero())
+	assert.Equal(t, got.UpdatedAt, got.CreatedAt)
+
+	// names are unique ignoring case
+	_, err = m.Author.Create(ctx, &models.Author{Name: "JANE DOE"})
+	assert.ErrorIs(t, err, models.ErrConflict)
+
+	_, err = m.Author.Create(ctx, &models.Author{})
+	assert.ErrorIs(t, err, models.ErrValidation)
+
+	_, err = m.Author.GetByID(ctx, int(id)+100)
+	assert.ErrorIs(t, err, models.ErrAuthorNotFound)
+}
+
+func testAuthorResolve(t *testing.T, m *models.Models) {
+	ids := createAuthors(t, m.Author, "Ann Lee")
+
+	// an existing author is found ignoring case, a new name creates the author
+	posts := store(t, m.Article,
+		models.Article{Title: "one", Content: "content", Author: "ANN LEE"},
+		models.Article{Title: "two", Content: "content", Author: "Jane Doe"},
+		models.Article{Title: "three", Content: "content", Author: "jane doe"},
+	)
+
+	one, err := m.Article.GetByID(ctx, posts[0], false)
+	require.NoError(t, err)
+	assert.Equal(t, one.AuthorID, ids[0])
+	assert.Equal(t, one.Author, "Ann Lee")
+
+	two, err := m.Article.GetByID(ctx, posts[1], false)
+	require.NoError(t, err)
+
+	three, err := m.Article.GetByID(ctx, posts[2], false)
+	require.NoError(t, err)
+	assert.Equal(t, three.AuthorID, two.AuthorID)
+	assert.Equal(t, three.Author, "Jane Doe")
+
+	total, err := m.Author.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, total, int64(2))
+
+	// updates and patches resolve the author as well
+	patched := "ann lee"
+	require.NoError(t, m.Article.Patch(ctx, posts[1], &models.ArticlePatch{Author: &patched}))
+
+	two, err = m.Article.GetByID(ctx, posts[1], false)
+	require.NoError(t, err)
+	assert.Equal(t, two.AuthorID, ids[0])
+	assert.Equal(t, two.Author, "Ann Lee")
+
+	require.NoError(t, m.Article.Update(ctx, &models.Article{ID: posts[2], Title: "three", Content: "content", Author: "New Author", CreateAuthor: true}))
+
+	three, err = m.Article.GetByID(ctx, posts[2], false)
+	require.NoError(t, err)
+	assert.Equal(t, three.Author, "New Author")
+	assert.NotEqual(t, three.AuthorID, ids[0])
+
+	// unknown authors are only created when asked for, failed writes leave no author behind
+	unknown := "Nobody"
+
+	_, err = m.Article.Store(ctx, &models.Article{Title: "four", Content: "content", Author: unknown})
+	assert.ErrorIs(t, err, models.ErrUnknownAuthor)
+
+	err = m.Article.Update(ctx, &models.Article{ID: posts[0], Title: "one", Content: "content", Author: unknown})
+	assert.ErrorIs(t, err, models.ErrUnknownAuthor)
+
+	err = m.Article.Patch(ctx, posts[0], &models.ArticlePatch{Title: &unknown, Author: &unknown})
+	assert.ErrorIs(t, err, models.ErrUnknownAuthor)
+
+	err = m.Article.Update(ctx, &models.Article{ID: posts[2] + 100, Title: "one", Content: "content", Author: unknown, CreateAuthor: true})
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	err = m.Article.Patch(ctx, posts[2]+100, &models.ArticlePatch{Author: &unknown, CreateAuthor: true})
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	one, err = m.Article.GetByID(ctx, posts[0], false)
+	require.NoError(t, err)
+	assert.Equal(t, one.Title, "one")
+	assert.Equal(t, one.Author, "Ann Lee")
+
+	total, err = m.Author.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, total, int64(3))
+}
+
+func testAuthorSubject(t *testing.T, m *models.Models) {
+	// the first article of a subject creates its author without asking for it
+	id, err := m.Article.Store(ctx, &models.Article{Title: "one", Content: "content", Author: "Jane", AuthorSubject: "u-1"})
+	require.NoError(t, err)
+
+	one, err := m.Article.GetByID(ctx, int(id), false)
+	require.NoError(t, err)
+
+	jane, err := m.Author.GetByID(ctx, one.AuthorID)
+	require.NoError(t, err)
+	assert.Equal(t, jane.Name, "Jane")
+	assert.Equal(t, jane.Subject, "u-1")
+
+	// the author of the subject is kept after editors rename it
+	require.NoError(t, m.Author.Update(ctx, &models.Author{ID: jane.ID, Name: "Jane Smith"}))
+	require.NoError(t, m.Article.Update(ctx, &models.Article{ID: int(id), Title: "one", Content: "changed", Author: "Jane", AuthorSubject: "u-1"}))
+
+	one, err = m.Article.GetByID(ctx, int(id), false)
+	require.NoError(t, err)
+	assert.Equal(t, one.AuthorID, jane.ID)
+	assert.Equal(t, one.Author, "Jane Smith")
+
+	// an author created by editors is linked to the first subject writing as it
+	ids := createAuthors(t, m.Author, "Ann Lee")
+
+	id, err = m.Article.Store(ctx, &models.Article{Title: "two", Content: "content", Author: "ann lee", AuthorSubject: "u-2"})
+	require.NoError(t, err)
+
+	two, err := m.Article.GetByID(ctx, int(id), false)
+	require.NoError(t, err)
+	assert.Equal(t, two.AuthorID, ids[0])
+
+	ann, err := m.Author.GetByID(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, ann.Subject, "u-2")
+
+	// other subjects cannot write as it
+	_, err = m.Article.Store(ctx, &models.Article{Title: "three", Content: "content", Author: "Ann Lee", AuthorSubject: "u-3"})
+	assert.ErrorIs(t, err, models.ErrAuthorTaken)
+
+	total, err := m.Author.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, total, int64(2))
+}
+
+func testAuthorList(t *testing.T, m *models.Models) {
+	ids := createAuthors(t, m.Author, "a", "b", "c")
+
+	all, err := m.Author.List(ctx, models.Page{})
+	require.NoError(t, err)
+	assert.Equal(t, authorIDs(all), ids)
+
+	first, err := m.Author.List(ctx, models.Page{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, authorIDs(first), ids[:2])
+
+	second, err := m.Author.List(ctx, models.Page{Limit: 2, After: models.NewAuthorCursor(first[1])})
+	require.NoError(t, err)
+	assert.Equal(t, authorIDs(second), ids[2:])
+
+	before, err := m.Author.List(ctx, models.Page{Limit: 1, Before: models.NewAuthorCursor(second[0])})
+	require.NoError(t, err)
+	assert.Equal(t, authorIDs(before), ids[1:2])
+
+	offset, err := m.Author.List(ctx, models.Page{Limit: 5, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, authorIDs(offset), ids[1:])
+
+	total, err := m.Author.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, total, int64(3))
+
+	// unknown ids are skipped
+	byIDs, err := m.Author.GetByIDs(ctx, []int{ids[2], ids[0], ids[2] + 100})
+	require.NoError(t, err)
+	assert.Equal(t, authorIDs(byIDs), []int{ids[0], ids[2]})
+
+	none, err := m.Author.GetByIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func testAuthorUpdate(t *testing.T, m *models.Models) {
+	ids := createAuthors(t, m.Author, "Jane", "John")
+	posts := store(t, m.Article, models.Article{Title: "title", Content: "content", Author: "jane"})
+
+	before, err := m.Article.GetByID(ctx, posts[0], false)
+	require.NoError(t, err)
+
+	err = m.Author.Update(ctx, &models.Author{ID: ids[0], Name: "Jane Doe", Bio: "bio"})
+	require.NoError(t, err)
+
+	got, err := m.Author.GetByID(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, got.Name, "Jane Doe")
+	assert.Equal(t, got.Bio, "bio")
+
+	// articles take the new name without counting as changed
+	article, err := m.Article.GetByID(ctx, posts[0], false)
+	require.NoError(t, err)
+	assert.Equal(t, article.Author, "Jane Doe")
+	assert.Equal(t, article.UpdatedAt, before.UpdatedAt)
+
+	// renaming to the name of another author clashes
+	err = m.Author.Update(ctx, &models.Author{ID: ids[0], Name: "john"})
+	assert.ErrorIs(t, err, models.ErrConflict)
+
+	err = m.Author.Update(ctx, &models.Author{ID: ids[1] + 100, Name: "Nobody"})
+	assert.ErrorIs(t, err, models.ErrAuthorNotFound)
+}
+
+func testAuthorDelete(t *testing.T, m *models.Models) {
+	ids := createAuthors(t, m.Author, "Jane", "John")
+	posts := store(t, m.Article, models.Article{Title: "title", Content: "content", Author: "Jane"})
+
+	require.NoError(t, m.Author.Delete(ctx, ids[1]))
+
+	_, err := m.Author.GetByID(ctx, ids[1])
+	assert.ErrorIs(t, err, models.ErrAuthorNotFound)
+
+	err = m.Author.Delete(ctx, ids[1])
+	assert.ErrorIs(t, err, models.ErrAuthorNotFound)
+
+	// soft deleted articles still belong to the author
+	require.NoError(t, m.Article.Delete(ctx, posts[0]))
+
+	err = m.Author.Delete(ctx, ids[0])
+	assert.ErrorIs(t, err, models.ErrAuthorHasArticles)
+
+	require.NoError(t, m.Article.Purge(ctx, posts[0]))
+	assert.NoError(t, m.Author.Delete(ctx, ids[0]))
+}
+
+func testArticlesOfAuthor(t *testing.T, m *models.Models) {
+	posts := store(t, m.Article,
+		models.Article{Title: "one", Content: "content", Author: "Jane"},
+		models.Article{Title: "two", Content: "content", Author: "John"},
+		models.Article{Title: "three", Content: "content", Author: "JANE"},
+	)
+
+	jane, err := m.Article.GetByID(ctx, posts[0], false)
+	require.NoError(t, err)
+
+	opts := models.ListOptions{Filter: models.ArticleFilter{AuthorID: jane.AuthorID}}
+
+	got, err := m.Article.List(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, articleIDs(got), []int{posts[0], posts[2]})
+
+	total, err := m.Article.Count(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, total, int64(2))
+}
@@ -0,0 +1,529 @@
+// Package storetest holds the conformance suite every models.ArticleStore implementation must pass
+package storetest
+
+import (
+	"article/internal/models"
+	"article/internal/search"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// NewStore returns an empty store, it is called once per test
+type NewStore func(t *testing.T) models.ArticleStore
+
+// Run runs the conformance suite against stores returned by newStore
+func Run(t *testing.T, newStore NewStore) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, store models.ArticleStore)
+	}{
+		{"store and get", testStoreAndGet},
+		{"store validation", testStoreValidation},
+		{"owner", testOwner},
+		{"ids are not reused", testIDsNotReused},
+		{"update", testUpdate},
+		{"patch", testPatch},
+		{"soft delete and restore", testSoftDelete},
+		{"purge", testPurge},
+		{"list order", testListOrder},
+		{"list filter", testListFilter},
+		{"list pagination", testListPagination},
+		{"search", testSearch},
+		{"search pagination", testSearchPagination},
+		{"search pagination tied scores", testSearchPaginationTies},
+		{"canceled context", testCanceledContext},
+		{"workflow", testWorkflow},
+		{"workflow validation", testWorkflowValidation},
+		{"history", testHistory},
+		{"status filter", testStatusFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStore(t))
+		})
+	}
+}
+
+var ctx = context.Background()
+
+// store stores articles and returns their ids
+func store(t *testing.T, s models.ArticleStore, articles ...models.Article) []int {
+	t.Helper()
+
+	var ids []int
+
+	for i := range articles {
+		// authors are created on first use
+		articles[i].CreateAuthor = true
+
+		id, err := s.Store(ctx, &articles[i])
+		require.NoError(t, err)
+
+		ids = append(ids, int(id))
+	}
+
+	return ids
+}
+
+// articleIDs returns ids of articles
+func articleIDs(articles []*models.Article) []int {
+	ids := []int{}
+	for _, a := range articles {
+		ids = append(ids, a.ID)
+	}
+
+	return ids
+}
+
+// resultIDs returns ids of search results
+func resultIDs(results []*models.SearchResult) []int {
+	ids := []int{}
+	for _, r := range results {
+		ids = append(ids, r.Article.ID)
+	}
+
+	return ids
+}
+
+func testStoreAndGet(t *testing.T, s models.ArticleStore) {
+	before := time.Now().Add(-time.Second)
+
+	ids := store(t, s,
+		models.Article{Title: "First title", Content: "First content", Author: "Jane"},
+		models.Article{Title: "Second title", Content: "Second content", Author: "John"},
+	)
+	require.Len(t, ids, 2)
+	assert.Greater(t, ids[1], ids[0])
+
+	got, err := s.GetByID(ctx, ids[0], false)
+	require.NoError(t, err)
+
+	assert.Equal(t, got.ID, ids[0])
+	assert.Equal(t, got.Title, "First title")
+	assert.Equal(t, got.Content, "First content")
+	assert.Equal(t, got.Author, "Jane")
+	assert.False(t, got.Deleted)
+
+	// timestamps are set on insert
+	assert.True(t, got.CreatedAt.After(before), "created_at %v is not after %v", got.CreatedAt, before)
+	assert.Equal(t, got.UpdatedAt, got.CreatedAt)
+	assert.Equal(t, got.CreatedAt.Location(), time.UTC)
+
+	_, err = s.GetByID(ctx, ids[1]+100, false)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+}
+
+func testOwner(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s,
+		models.Article{Title: "Owned title", Content: "Test content", Author: "Jane", OwnerID: "u-1"},
+		models.Article{Title: "Unowned title", Content: "Test content", Author: "John"},
+	)
+
+	// the owner is kept on updates and returned by every read
+	require.NoError(t, s.Update(ctx, &models.Article{ID: ids[0], Title: "New title", Content: "New content", Author: "Jane"}))
+
+	got, err := s.GetByID(ctx, ids[0], false)
+	require.NoError(t, err)
+	assert.Equal(t, got.OwnerID, "u-1")
+
+	articles, err := s.List(ctx, models.ListOptions{Page: models.Page{Limit: 10}})
+	require.NoError(t, err)
+	require.Len(t, articles, 2)
+	assert.Equal(t, articles[0].OwnerID, "u-1")
+	assert.Equal(t, articles[1].OwnerID, "")
+
+	results, err := s.Search(ctx, models.SearchOptions{Query: search.Parse("unowned"), Page: models.Page{Limit: 10}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, results[0].Article.OwnerID, "")
+}
+
+func testStoreValidation(t *testing.T, s models.ArticleStore) {
+	for _, article := range []models.Article{
+		{Content: "Test content", Author: "Jane"},
+		{Title: "Test title", Author: "Jane"},
+		{Title: "Test title", Content: "Test content"},
+		{Title: "Test title", Content: "Test content", Author: strings.Repeat("a", 256)},
+	} {
+		_, err := s.Store(ctx, &article)
+		assert.ErrorIs(t, err, models.ErrValidation)
+	}
+
+	total, err := s.Count(ctx, models.ListOptions{IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Equal(t, total, int64(0))
+}
+
+func testIDsNotReused(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s,
+		models.Article{Title: "First title", Content: "Test content", Author: "Jane"},
+		models.Article{Title: "Second title", Content: "Test content", Author: "Jane"},
+	)
+
+	require.NoError(t, s.Purge(ctx, ids[1]))
+
+	next := store(t, s, models.Article{Title: "Third title", Content: "Test content", Author: "Jane"})
+	assert.Greater(t, next[0], ids[1])
+}
+
+func testUpdate(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s, models.Article{Title: "Test title", Content: "Test content", Author: "Jane"})
+
+	err := s.Update(ctx, &models.Article{ID: ids[0], Title: "New title", Content: "New content", Author: "John", CreateAuthor: true})
+	require.NoError(t, err)
+
+	got, err := s.GetByID(ctx, ids[0], false)
+	require.NoError(t, err)
+
+	assert.Equal(t, got.Title, "New title")
+	assert.Equal(t, got.Content, "New content")
+	assert.Equal(t, got.Author, "John")
+	assert.False(t, got.UpdatedAt.Before(got.CreatedAt))
+
+	// unchanged values still match the article
+	err = s.Update(ctx, &models.Article{ID: ids[0], Title: "New title", Content: "New content", Author: "John", CreateAuthor: true})
+	assert.NoError(t, err)
+
+	err = s.Update(ctx, &models.Article{ID: ids[0], Title: "", Content: "New content", Author: "John", CreateAuthor: true})
+	assert.ErrorIs(t, err, models.ErrValidation)
+
+	err = s.Update(ctx, &models.Article{ID: ids[0] + 100, Title: "New title", Content: "New content", Author: "John", CreateAuthor: true})
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	// soft deleted articles cannot be updated
+	require.NoError(t, s.Delete(ctx, ids[0]))
+
+	err = s.Update(ctx, &models.Article{ID: ids[0], Title: "New title", Content: "New content", Author: "John", CreateAuthor: true})
+	assert.ErrorIs(t, err, models.ErrNotFound)
+}
+
+func testPatch(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s, models.Article{Title: "Test title", Content: "Test content", Author: "Jane"})
+
+	title := "New title"
+
+	err := s.Patch(ctx, ids[0], &models.ArticlePatch{Title: &title})
+	require.NoError(t, err)
+
+	got, err := s.GetByID(ctx, ids[0], false)
+	require.NoError(t, err)
+
+	assert.Equal(t, got.Title, "New title")
+	assert.Equal(t, got.Content, "Test content")
+	assert.Equal(t, got.Author, "Jane")
+
+	// empty patch only checks the article exists
+	assert.NoError(t, s.Patch(ctx, ids[0], &models.ArticlePatch{}))
+	assert.ErrorIs(t, s.Patch(ctx, ids[0]+100, &models.ArticlePatch{}), models.ErrNotFound)
+	assert.ErrorIs(t, s.Patch(ctx, ids[0]+100, &models.ArticlePatch{Title: &title}), models.ErrNotFound)
+
+	empty := ""
+	assert.ErrorIs(t, s.Patch(ctx, ids[0], &models.ArticlePatch{Content: &empty}), models.ErrValidation)
+}
+
+func testSoftDelete(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s, models.Article{Title: "Test title", Content: "Test content", Author: "Jane"})
+
+	before, err := s.GetByID(ctx, ids[0], false)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Delete(ctx, ids[0]))
+
+	_, err = s.GetByID(ctx, ids[0], false)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	got, err := s.GetByID(ctx, ids[0], true)
+	require.NoError(t, err)
+	assert.True(t, got.Deleted)
+
+	// updated_at tracks content changes only
+	assert.Equal(t, got.UpdatedAt, before.UpdatedAt)
+
+	assert.ErrorIs(t, s.Delete(ctx, ids[0]), models.ErrNotFound)
+
+	total, err := s.Count(ctx, models.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, total, int64(0))
+
+	total, err = s.Count(ctx, models.ListOptions{IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Equal(t, total, int64(1))
+
+	require.NoError(t, s.Restore(ctx, ids[0]))
+
+	got, err = s.GetByID(ctx, ids[0], false)
+	require.NoError(t, err)
+	assert.False(t, got.Deleted)
+
+	// only deleted articles are restored
+	assert.ErrorIs(t, s.Restore(ctx, ids[0]), models.ErrConflict)
+	assert.ErrorIs(t, s.Restore(ctx, ids[0]+100), models.ErrNotFound)
+}
+
+func testPurge(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s, models.Article{Title: "Test title", Content: "Test content", Author: "Jane"})
+
+	require.NoError(t, s.Delete(ctx, ids[0]))
+	require.NoError(t, s.Purge(ctx, ids[0]))
+
+	_, err := s.GetByID(ctx, ids[0], true)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	assert.ErrorIs(t, s.Purge(ctx, ids[0]), models.ErrNotFound)
+}
+
+func testListOrder(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s,
+		models.Article{Title: "bravo", Content: "Test content", Author: "jane"},
+		models.Article{Title: "alpha", Content: "Test content", Author: "john"},
+		models.Article{Title: "charlie", Content: "Test content", Author: "jane"},
+	)
+
+	tests := []struct {
+		name string
+		sort []models.SortField
+		want []int
+	}{
+		{"default", nil, []int{ids[0], ids[1], ids[2]}},
+		{"title", []models.SortField{{Field: "title"}}, []int{ids[1], ids[0], ids[2]}},
+		{"title desc", []models.SortField{{Field: "title", Desc: true}}, []int{ids[2], ids[0], ids[1]}},
+		{"author then id desc", []models.SortField{{Field: "author"}, {Field: "id", Desc: true}}, []int{ids[2], ids[0], ids[1]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.List(ctx, models.ListOptions{Sort: tt.sort})
+			require.NoError(t, err)
+			assert.Equal(t, articleIDs(got), tt.want)
+		})
+	}
+}
+
+func testListFilter(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s,
+		models.Article{Title: "golang basics", Content: "Test content", Author: "jane"},
+		models.Article{Title: "golang channels", Content: "Test content", Author: "john"},
+		models.Article{Title: "rust basics", Content: "Test content", Author: "jack"},
+		models.Article{Title: "go_lang escaped", Content: "Test content", Author: "jane"},
+	)
+
+	require.NoError(t, s.Delete(ctx, ids[1]))
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name string
+		opts models.ListOptions
+		want []int
+	}{
+		{"authors", models.ListOptions{Filter: models.ArticleFilter{Authors: []string{"jane", "jack"}}}, []int{ids[0], ids[2], ids[3]}},
+		{"title prefix", models.ListOptions{Filter: models.ArticleFilter{TitlePrefix: "golang"}}, []int{ids[0]}},
+		{"title prefix with wildcard", models.ListOptions{Filter: models.ArticleFilter{TitlePrefix: "go_"}}, []int{ids[3]}},
+		{"include deleted", models.ListOptions{IncludeDeleted: true, Filter: models.ArticleFilter{TitlePrefix: "golang"}}, []int{ids[0], ids[1]}},
+		{"created range", models.ListOptions{Filter: models.ArticleFilter{CreatedFrom: &past, CreatedTo: &future}}, []int{ids[0], ids[2], ids[3]}},
+		{"created in future", models.ListOptions{Filter: models.ArticleFilter{CreatedFrom: &future}}, []int{}},
+		{"updated in past", models.ListOptions{Filter: models.ArticleFilter{UpdatedTo: &past}}, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.List(ctx, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, articleIDs(got), tt.want)
+
+			total, err := s.Count(ctx, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, total, int64(len(tt.want)))
+		})
+	}
+}
+
+func testListPagination(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s,
+		models.Article{Title: "echo", Content: "Test content", Author: "jane"},
+		models.Article{Title: "alpha", Content: "Test content", Author: "jane"},
+		models.Article{Title: "delta", Content: "Test content", Author: "jane"},
+		models.Article{Title: "bravo", Content: "Test content", Author: "jane"},
+		models.Article{Title: "charlie", Content: "Test content", Author: "jane"},
+	)
+
+	// sorted by title: alpha, bravo, charlie, delta, echo
+	sorted := []int{ids[1], ids[3], ids[4], ids[2], ids[0]}
+	byTitle := []models.SortField{{Field: "title"}}
+
+	tests := []struct {
+		name string
+		opts models.ListOptions
+		want []int
+	}{
+		{"limit", models.ListOptions{Sort: byTitle, Page: models.Page{Limit: 2}}, sorted[:2]},
+		{"offset", models.ListOptions{Sort: byTitle, Page: models.Page{Limit: 2, Offset: 2}}, sorted[2:4]},
+		{"offset past end", models.ListOptions{Sort: byTitle, Page: models.Page{Limit: 2, Offset: 10}}, []int{}},
+		{"after", models.ListOptions{Sort: byTitle, Page: models.Page{Limit: 2, After: &models.Cursor{ID: sorted[1]}}}, sorted[2:4]},
+		{"after last", models.ListOptions{Sort: byTitle, Page: models.Page{Limit: 2, After: &models.Cursor{ID: sorted[4]}}}, []int{}},
+		{"before", models.ListOptions{Sort: byTitle, Page: models.Page{Limit: 2, Before: &models.Cursor{ID: sorted[3]}}}, sorted[1:3]},
+		{"before near start", models.ListOptions{Sort: byTitle, Page: models.Page{Limit: 2, Before: &models.Cursor{ID: sorted[1]}}}, sorted[:1]},
+		{"after desc", models.ListOptions{Sort: []models.SortField{{Field: "title", Desc: true}}, Page: models.Page{Limit: 2, After: &models.Cursor{ID: sorted[3]}}}, []int{sorted[2], sorted[1]}},
+		{"after by id", models.ListOptions{Page: models.Page{Limit: 2, After: &models.Cursor{ID: ids[2]}}}, ids[3:5]},
+		{"before by id", models.ListOptions{Page: models.Page{Limit: 2, Before: &models.Cursor{ID: ids[2]}}}, ids[0:2]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.List(ctx, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, articleIDs(got), tt.want)
+		})
+	}
+}
+
+func testSearch(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s,
+		models.Article{Title: "Concurrency patterns", Content: "Worker pools spread jobs across goroutines", Author: "jane"},
+		models.Article{Title: "Channels explained", Content: "Goroutines talk over channels, pools are optional", Author: "jane"},
+		models.Article{Title: "Cooking pasta", Content: "Boil water and salt generously", Author: "john"},
+		models.Article{Title: "Deleted worker pools", Content: "Worker pools spread jobs", Author: "john"},
+	)
+
+	require.NoError(t, s.Delete(ctx, ids[3]))
+
+	tests := []struct {
+		name  string
+		query string
+		want  []int
+	}{
+		{"term", "goroutines", []int{ids[0], ids[1]}},
+		{"all terms required", "goroutines pasta", []int{}},
+		{"phrase", `"worker pools"`, []int{ids[0]}},
+		{"phrase order matters", `"pools worker"`, []int{}},
+		{"title and content", "cooking water", []int{ids[2]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := models.SearchOptions{Query: search.Parse(tt.query)}
+
+			got, err := s.Search(ctx, opts)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, resultIDs(got), tt.want)
+
+			for _, r := range got {
+				assert.Greater(t, r.Score, 0.0)
+			}
+
+			total, err := s.CountSearch(ctx, opts)
+			require.NoError(t, err)
+			assert.Equal(t, total, int64(len(tt.want)))
+		})
+	}
+}
+
+func testSearchPagination(t *testing.T, s models.ArticleStore) {
+	for _, title := range []string{"Gardening tips", "Gardening tools", "Gardening soil", "Gardening seasons"} {
+		store(t, s, models.Article{Title: title, Content: "All about gardening outdoors", Author: "jane"})
+	}
+
+	query := search.Parse("gardening")
+
+	all, err := s.Search(ctx, models.SearchOptions{Query: query})
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+
+	// results are ordered by score then id
+	for i := 1; i < len(all); i++ {
+		prev, cur := all[i-1], all[i]
+		assert.True(t, prev.Score > cur.Score || (prev.Score == cur.Score && prev.Article.ID < cur.Article.ID))
+	}
+
+	first, err := s.Search(ctx, models.SearchOptions{Query: query, Page: models.Page{Limit: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, resultIDs(first), resultIDs(all[:2]))
+
+	next, err := s.Search(ctx, models.SearchOptions{Query: query, Page: models.Page{Limit: 2, After: models.NewSearchCursor(first[1])}})
+	require.NoError(t, err)
+	assert.Equal(t, resultIDs(next), resultIDs(all[2:]))
+
+	prev, err := s.Search(ctx, models.SearchOptions{Query: query, Page: models.Page{Limit: 2, Before: models.NewSearchCursor(next[0])}})
+	require.NoError(t, err)
+	assert.Equal(t, resultIDs(prev), resultIDs(all[:2]))
+
+	offset, err := s.Search(ctx, models.SearchOptions{Query: query, Page: models.Page{Limit: 2, Offset: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, resultIDs(offset), resultIDs(all[1:3]))
+
+	// list cursors carry no score
+	_, err = s.Search(ctx, models.SearchOptions{Query: query, Page: models.Page{After: &models.Cursor{ID: 1}}})
+	assert.ErrorIs(t, err, models.ErrInvalidInput)
+}
+
+func testSearchPaginationTies(t *testing.T, s models.ArticleStore) {
+	// equal articles have equal scores, they are paged by id
+	for i := 0; i < 5; i++ {
+		store(t, s, models.Article{Title: "Composting basics", Content: "Turning kitchen scraps into compost", Author: "jane"})
+	}
+
+	query := search.Parse("compost")
+
+	all, err := s.Search(ctx, models.SearchOptions{Query: query})
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+
+	for _, r := range all[1:] {
+		assert.Equal(t, r.Score, all[0].Score)
+	}
+
+	// cursors pass through their encoded form like between requests
+	roundTrip := func(c *models.Cursor) *models.Cursor {
+		decoded, err := models.DecodeCursor(c.Encode())
+		require.NoError(t, err)
+
+		return decoded
+	}
+
+	var forward []*models.SearchResult
+
+	page, err := s.Search(ctx, models.SearchOptions{Query: query, Page: models.Page{Limit: 2}})
+	require.NoError(t, err)
+
+	for len(page) > 0 {
+		forward = append(forward, page...)
+
+		page, err = s.Search(ctx, models.SearchOptions{Query: query, Page: models.Page{Limit: 2, After: roundTrip(models.NewSearchCursor(page[len(page)-1]))}})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, resultIDs(forward), resultIDs(all))
+
+	var backward []*models.SearchResult
+
+	page = all[len(all)-1:]
+	for len(page) > 0 {
+		backward = append(append([]*models.SearchResult{}, page...), backward...)
+
+		page, err = s.Search(ctx, models.SearchOptions{Query: query, Page: models.Page{Limit: 2, Before: roundTrip(models.NewSearchCursor(page[0]))}})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, resultIDs(backward), resultIDs(all))
+}
+
+func testCanceledContext(t *testing.T, s models.ArticleStore) {
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err := s.Store(canceled, &models.Article{Title: "Test title", Content: "Test content", Author: "Jane"})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = s.GetByID(canceled, 1, false)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = s.List(canceled, models.ListOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,225 @@
+package storetest
+
+import (
+	"article/internal/models"
+	"article/internal/search"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// change returns the status change of transition name made by actor
+func change(t *testing.T, name, actor, comment string) *models.StatusChange {
+	t.Helper()
+
+	transition, ok := models.FindTransition(name)
+	require.True(t, ok)
+
+	return &models.StatusChange{Transition: name, From: transition.From, To: transition.To, Actor: actor, Comment: comment}
+}
+
+// transition moves an article along transitions named names
+func transition(t *testing.T, s models.ArticleStore, articleID int, names ...string) {
+	t.Helper()
+
+	for _, name := range names {
+		require.NoError(t, s.Transition(ctx, articleID, change(t, name, "u-1", "")))
+	}
+}
+
+func testWorkflow(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s, models.Article{Title: "Test title", Content: "Test content", Author: "Jane"})
+
+	stored, err := s.GetByID(ctx, ids[0], false)
+	require.NoError(t, err)
+	assert.Equal(t, stored.Status, models.StatusDraft)
+	assert.Nil(t, stored.PublishedAt)
+
+	// transitions must start from the current status
+	err = s.Transition(ctx, ids[0], change(t, models.Publish, "u-9", ""))
+	assert.ErrorIs(t, err, models.ErrConflict)
+
+	submit := change(t, models.Submit, "u-1", "ready for review")
+	require.NoError(t, s.Transition(ctx, ids[0], submit))
+	assert.NotZero(t, submit.ID)
+	assert.Equal(t, submit.ArticleID, ids[0])
+
+	require.NoError(t, s.Transition(ctx, ids[0], change(t, models.Publish, "u-9", "")))
+
+	published, err := s.GetByID(ctx, ids[0], false)
+	require.NoError(t, err)
+	assert.Equal(t, published.Status, models.StatusPublished)
+	require.NotNil(t, published.PublishedAt)
+	assert.False(t, published.PublishedAt.Before(stored.CreatedAt))
+
+	// a status change is not an edit
+	assert.Equal(t, published.UpdatedAt, stored.UpdatedAt)
+
+	require.NoError(t, s.Transition(ctx, ids[0], change(t, models.Archive, "u-9", "outdated")))
+
+	archived, err := s.GetByID(ctx, ids[0], false)
+	require.NoError(t, err)
+	assert.Equal(t, archived.Status, models.StatusArchived)
+	assert.Equal(t, archived.PublishedAt, published.PublishedAt)
+
+	err = s.Transition(ctx, ids[0]+100, change(t, models.Submit, "u-1", ""))
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	// deleted articles cannot move
+	ids = store(t, s, models.Article{Title: "Deleted title", Content: "Test content", Author: "Jane"})
+	require.NoError(t, s.Delete(ctx, ids[0]))
+
+	err = s.Transition(ctx, ids[0], change(t, models.Submit, "u-1", ""))
+	assert.ErrorIs(t, err, models.ErrNotFound)
+}
+
+func testWorkflowValidation(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s, models.Article{Title: "Test title", Content: "Test content", Author: "Jane"})
+
+	tests := []struct {
+		name   string
+		change models.StatusChange
+	}{
+		{"unknown transition", models.StatusChange{Transition: "promote", From: models.StatusDraft, To: models.StatusPublished, Actor: "u-1"}},
+		{"statuses of another transition", models.StatusChange{Transition: models.Submit, From: models.StatusDraft, To: models.StatusPublished, Actor: "u-1"}},
+		{"missing actor", models.StatusChange{Transition: models.Submit, From: models.StatusDraft, To: models.StatusInReview}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Transition(ctx, ids[0], &tt.change)
+			assert.ErrorIs(t, err, models.ErrValidation)
+		})
+	}
+
+	got, err := s.GetByID(ctx, ids[0], false)
+	require.NoError(t, err)
+	assert.Equal(t, got.Status, models.StatusDraft)
+}
+
+func testHistory(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s,
+		models.Article{Title: "Test title", Content: "Test content", Author: "Jane"},
+		models.Article{Title: "Other title", Content: "Test content", Author: "Jane"},
+	)
+
+	empty, err := s.History(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	require.NoError(t, s.Transition(ctx, ids[0], change(t, models.Submit, "u-1", "")))
+	require.NoError(t, s.Transition(ctx, ids[0], change(t, models.Reject, "u-9", "needs sources")))
+	transition(t, s, ids[1], models.Submit)
+
+	history, err := s.History(ctx, ids[0])
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	assert.Equal(t, history[0].Transition, models.Submit)
+	assert.Equal(t, history[0].From, models.StatusDraft)
+	assert.Equal(t, history[0].To, models.StatusInReview)
+	assert.Equal(t, history[0].Actor, "u-1")
+	assert.Equal(t, history[0].Comment, "")
+	assert.False(t, history[0].CreatedAt.IsZero())
+
+	assert.Equal(t, history[1].Transition, models.Reject)
+	assert.Equal(t, history[1].ArticleID, ids[0])
+	assert.Equal(t, history[1].Actor, "u-9")
+	assert.Equal(t, history[1].Comment, "needs sources")
+	assert.Greater(t, history[1].ID, history[0].ID)
+
+	_, err = s.History(ctx, ids[1]+100)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	// the history goes with a purged article
+	require.NoError(t, s.Purge(ctx, ids[0]))
+
+	_, err = s.History(ctx, ids[0])
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	other, err := s.History(ctx, ids[1])
+	require.NoError(t, err)
+	assert.Len(t, other, 1)
+}
+
+func testStatusFilter(t *testing.T, s models.ArticleStore) {
+	ids := store(t, s,
+		models.Article{Title: "Draft gardening", Content: "Test content", Author: "Jane", OwnerID: "u-1"},
+		models.Article{Title: "Review gardening", Content: "Test content", Author: "Jane", OwnerID: "u-2"},
+		models.Article{Title: "Published gardening", Content: "Test content", Author: "Jane", OwnerID: "u-1"},
+		models.Article{Title: "Other gardening", Content: "Test content", Author: "Jane", OwnerID: "u-2"},
+	)
+
+	transition(t, s, ids[1], models.Submit)
+	transition(t, s, ids[2], models.Submit, models.Publish)
+	transition(t, s, ids[3], models.Submit, models.Publish)
+
+	tests := []struct {
+		name   string
+		filter models.ArticleFilter
+		want   []int
+	}{
+		{"all statuses", models.ArticleFilter{}, ids},
+		{"published", models.ArticleFilter{Statuses: []models.Status{models.StatusPublished}}, ids[2:]},
+		{"draft or in review", models.ArticleFilter{Statuses: []models.Status{models.StatusDraft, models.StatusInReview}}, ids[:2]},
+		// the owner limits unpublished articles only
+		{"owner", models.ArticleFilter{OwnerID: "u-1"}, []int{ids[0], ids[2], ids[3]}},
+		{"owner and status", models.ArticleFilter{OwnerID: "u-1", Statuses: []models.Status{models.StatusDraft}}, ids[:1]},
+		{"owner, draft and published", models.ArticleFilter{OwnerID: "u-1", Statuses: []models.Status{models.StatusDraft, models.StatusPublished}}, []int{ids[0], ids[2], ids[3]}},
+		{"owner and published", models.ArticleFilter{OwnerID: "u-1", Statuses: []models.Status{models.StatusPublished}}, ids[2:]},
+		{"owner and in review", models.ArticleFilter{OwnerID: "u-1", Statuses: []models.Status{models.StatusInReview}}, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := models.ListOptions{Filter: tt.filter}
+
+			got, err := s.List(ctx, opts)
+			require.NoError(t, err)
+			assert.Equal(t, articleIDs(got), tt.want)
+
+			total, err := s.Count(ctx, opts)
+			require.NoError(t, err)
+			assert.Equal(t, total, int64(len(tt.want)))
+		})
+	}
+
+	t.Run("search", func(t *testing.T) {
+		opts := models.SearchOptions{Query: search.Parse("gardening"), Statuses: []models.Status{models.StatusPublished}}
+
+		got, err := s.Search(ctx, opts)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, resultIDs(got), ids[2:])
+		assert.Equal(t, got[0].Article.Status, models.StatusPublished)
+
+		total, err := s.CountSearch(ctx, opts)
+		require.NoError(t, err)
+		assert.Equal(t, total, int64(2))
+
+		all, err := s.CountSearch(ctx, models.SearchOptions{Query: search.Parse("gardening")})
+		require.NoError(t, err)
+		assert.Equal(t, all, int64(4))
+
+		own := models.SearchOptions{Query: search.Parse("gardening"), OwnerID: "u-1", Statuses: []models.Status{models.StatusDraft}}
+
+		drafts, err := s.Search(ctx, own)
+		require.NoError(t, err)
+		assert.Equal(t, resultIDs(drafts), ids[:1])
+
+		total, err = s.CountSearch(ctx, own)
+		require.NoError(t, err)
+		assert.Equal(t, total, int64(1))
+
+		// published articles of others are kept when asking for own drafts as well
+		own.Statuses = []models.Status{models.StatusDraft, models.StatusPublished}
+
+		mixed, err := s.Search(ctx, own)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, resultIDs(mixed), []int{ids[0], ids[2], ids[3]})
+
+		total, err = s.CountSearch(ctx, own)
+		require.NoError(t, err)
+		assert.Equal(t, total, int64(3))
+	})
+}
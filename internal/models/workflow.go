@@ -0,0 +1,223 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"unicode/utf8"
+)
+
+// Status is the editorial state of an article, articles are stored as drafts and only published ones are public
+type Status string
+
+// statuses of the editorial workflow
+const (
+	StatusDraft     Status = "draft"
+	StatusInReview  Status = "in_review"
+	StatusPublished Status = "published"
+	StatusArchived  Status = "archived"
+)
+
+// Statuses lists all statuses in workflow order
+var Statuses = []Status{StatusDraft, StatusInReview, StatusPublished, StatusArchived}
+
+// Valid reports whether s is a status of the workflow
+func (s Status) Valid() bool {
+	for _, status := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Transition moves an article from one status to another
+type Transition struct {
+	Name string
+	From Status
+	To   Status
+}
+
+// names of transitions
+const (
+	Submit   = "submit"
+	Withdraw = "withdraw"
+	Reject   = "reject"
+	Publish  = "publish"
+	Archive  = "archive"
+)
+
+// Transitions are the only moves between statuses, withdraw and reject differ in who may perform them
+var Transitions = []Transition{
+	{Name: Submit, From: StatusDraft, To: StatusInReview},
+	{Name: Withdraw, From: StatusInReview, To: StatusDraft},
+	{Name: Reject, From: StatusInReview, To: StatusDraft},
+	{Name: Publish, From: StatusInReview, To: StatusPublished},
+	{Name: Archive, From: StatusPublished, To: StatusArchived},
+}
+
+// FindTransition returns the transition named name
+func FindTransition(name string) (Transition, bool) {
+	for _, t := range Transitions {
+		if t.Name == name {
+			return t, true
+		}
+	}
+
+	return Transition{}, false
+}
+
+// StatusChange records a transition of an article, the changes of an article are its review history
+type StatusChange struct {
+	ID         int       `db:"id"`
+	ArticleID  int       `db:"article_id"`
+	Transition string    `db:"transition"`
+	From       Status    `db:"from_status"`
+	To         Status    `db:"to_status"`
+	Actor      string    `db:"actor"`
+	Comment    string    `db:"comment"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// maxActorLength is the size of actor column
+const maxActorLength = 255
+
+// validate checks change is a transition of the workflow made by an actor
+func (change *StatusChange) validate() error {
+	t, ok := FindTransition(change.Transition)
+	if !ok || t.From != change.From || t.To != change.To {
+		return newError(ErrValidation, "%s is not a transition from %s to %s", change.Transition, change.From, change.To)
+	}
+
+	if change.Actor == "" || utf8.RuneCountInString(change.Actor) > maxActorLength {
+		return newError(ErrValidation, "actor is required and must be at most %d characters", maxActorLength)
+	}
+
+	return nil
+}
+
+// statusConflict is returned when an article is not in the status a transition starts from
+func statusConflict(change *StatusChange, current Status) error {
+	return newError(ErrConflict, "cannot %s an article which is %s", change.Transition, current)
+}
+
+// statusChangeColumns are selected by History
+const statusChangeColumns = `id, article_id, transition, from_status, to_status, actor, COALESCE(comment, '') AS comment, created_at`
+
+// Transition moves an article along change and records change in its history, published_at is set on publishing.
+// The article must be in change.From, updated_at is left as is
+func (a *article) Transition(ctx context.Context, articleID int, change *StatusChange) error {
+	err := change.validate()
+	if err != nil {
+		return err
+	}
+
+	tx, err := a.app.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	query := `UPDATE article SET status=?, updated_at=updated_at`
+	if change.To == StatusPublished {
+		query += `, published_at=CURRENT_TIMESTAMP`
+	}
+
+	query += ` WHERE id=? AND status=? AND deleted_at IS NULL`
+
+	res, err := tx.ExecContext(ctx, a.dialect.statement(ctx, query), change.To, articleID, change.From)
+	if err != nil {
+		return storeError(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// the article is missing or in another status
+	if affected == 0 {
+		var current Status
+
+		err = tx.QueryRowContext(ctx, a.dialect.statement(ctx, `SELECT status FROM article WHERE id=? AND deleted_at IS NULL`), articleID).
+			Scan(&current)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrArticleNotFound
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return statusConflict(change, current)
+	}
+
+	query = `INSERT INTO article_status_change (article_id, transition, from_status, to_status, actor, comment) VALUES(?, ?, ?, ?, ?, ?)`
+	args := []interface{}{articleID, change.Transition, change.From, change.To, change.Actor, nullString(change.Comment)}
+
+	var id int64
+
+	if a.dialect.returningID {
+		err = tx.QueryRowContext(ctx, a.dialect.statement(ctx, query+" RETURNING id"), args...).Scan(&id)
+		if err != nil {
+			return storeError(err)
+		}
+	} else {
+		res, err = tx.ExecContext(ctx, a.dialect.statement(ctx, query), args...)
+		if err != nil {
+			return storeError(err)
+		}
+
+		id, err = res.LastInsertId()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	change.ID = int(id)
+	change.ArticleID = articleID
+
+	return nil
+}
+
+// History fetches status changes of an article oldest first, ErrArticleNotFound is returned when no article matches
+func (a *article) History(ctx context.Context, articleID int) ([]*StatusChange, error) {
+	_, err := a.GetByID(ctx, articleID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + statusChangeColumns + ` FROM article_status_change WHERE article_id=? ORDER BY id`
+
+	rows, err := a.app.db.QueryContext(ctx, a.dialect.statement(ctx, query), articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	changes := []*StatusChange{}
+
+	for rows.Next() {
+		var change StatusChange
+
+		err = rows.Scan(&change.ID, &change.ArticleID, &change.Transition, &change.From, &change.To, &change.Actor,
+			&change.Comment, &change.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		change.CreatedAt = change.CreatedAt.UTC()
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
+}
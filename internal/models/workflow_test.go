@@ -0,0 +1,116 @@
+package models_test
+
+import (
+	"article/internal/models"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Transition(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  models.StatusChange
+		mockDB  func() *sql.DB
+		wantErr error
+		wantID  int
+	}{
+		{
+			name:   "success",
+			change: models.StatusChange{Transition: models.Publish, From: models.StatusInReview, To: models.StatusPublished, Actor: "u-9", Comment: "looks good"},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				// the status change is recorded in the same transaction
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE article SET status=\\?, updated_at=updated_at, published_at=CURRENT_TIMESTAMP WHERE id=\\? AND status=\\? AND deleted_at IS NULL").
+					WithArgs(models.StatusPublished, 1, models.StatusInReview).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO article_status_change").
+					WithArgs(1, models.Publish, models.StatusInReview, models.StatusPublished, "u-9", "looks good").
+					WillReturnResult(sqlmock.NewResult(4, 1))
+				mock.ExpectCommit()
+
+				return db
+			},
+			wantID: 4,
+		},
+		{
+			name:   "error : article in another status",
+			change: models.StatusChange{Transition: models.Submit, From: models.StatusDraft, To: models.StatusInReview, Actor: "u-9"},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE article SET status=\\?, updated_at=updated_at WHERE").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT status FROM article WHERE id=\\?").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("published"))
+				mock.ExpectRollback()
+
+				return db
+			},
+			wantErr: models.ErrConflict,
+		},
+		{
+			name:   "error : not found",
+			change: models.StatusChange{Transition: models.Submit, From: models.StatusDraft, To: models.StatusInReview, Actor: "u-9"},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE article").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT status FROM article").WillReturnRows(sqlmock.NewRows([]string{"status"}))
+				mock.ExpectRollback()
+
+				return db
+			},
+			wantErr: models.ErrArticleNotFound,
+		},
+		{
+			name:   "error : not a transition",
+			change: models.StatusChange{Transition: models.Publish, From: models.StatusDraft, To: models.StatusPublished, Actor: "u-9"},
+			mockDB: func() *sql.DB {
+				// create sql mock database connection
+				db, _, err := sqlmock.New()
+				if err != nil {
+					t.Fatalf("error opening a stub database connection %v", err)
+				}
+
+				return db
+			},
+			wantErr: models.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := models.NewModels(tt.mockDB())
+
+			change := tt.change
+
+			err := a.Article.Transition(context.Background(), 1, &change)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, change.ID, tt.wantID)
+				assert.Equal(t, change.ArticleID, 1)
+			}
+		})
+	}
+}
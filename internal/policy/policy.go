@@ -0,0 +1,196 @@
+// Package policy decides which roles may perform which actions, rules are data so they can be read and tested
+// without serving requests
+package policy
+
+import (
+	"article/internal/auth"
+	"errors"
+	"fmt"
+)
+
+// Action is an operation guarded by the policy
+type Action string
+
+// actions of handlers
+const (
+	ReadArticle    Action = "read articles"
+	ReadDeleted    Action = "read deleted articles"
+	CreateArticle  Action = "create articles"
+	UpdateArticle  Action = "update articles"
+	DeleteArticle  Action = "delete articles"
+	RestoreArticle Action = "restore articles"
+	PurgeArticle   Action = "purge articles"
+	ManageAPIKeys  Action = "manage api keys"
+	ReadAuthor     Action = "read authors"
+	ManageAuthors  Action = "manage authors"
+
+	// actions of the editorial workflow, submitting covers withdrawing and reviewing covers rejecting and publishing
+	ReadUnpublished Action = "read unpublished articles"
+	ReadHistory     Action = "read history of articles"
+	SubmitArticle   Action = "submit articles"
+	ReviewArticle   Action = "review articles"
+	ArchiveArticle  Action = "archive articles"
+)
+
+// Anonymous is the role of requests without credentials
+const Anonymous = "anonymous"
+
+// ErrForbidden is wrapped by every denial
+var ErrForbidden = errors.New("forbidden")
+
+// Rule grants roles an action, Own limits it to resources owned by the caller
+type Rule struct {
+	Action Action
+	Roles  []string
+	Own    bool
+}
+
+// Policy holds rules, actions no rule grants are denied
+type Policy []Rule
+
+// roles are shorthands of the default rules
+var (
+	everyone = []string{Anonymous, auth.RoleReader, auth.RoleAuthor, auth.RoleEditor, auth.RoleAdmin}
+	writers  = []string{auth.RoleAuthor, auth.RoleEditor, auth.RoleAdmin}
+	editors  = []string{auth.RoleEditor, auth.RoleAdmin}
+	admins   = []string{auth.RoleAdmin}
+)
+
+// Default lets everyone read, authors write and change their own articles, editors change any article
+// and manage authors, and admins purge articles, see deleted ones and manage API keys.
+// Only published articles are public, authors see and submit their own drafts and editors review them
+var Default = Policy{
+	{Action: ReadArticle, Roles: everyone},
+	{Action: CreateArticle, Roles: writers},
+	{Action: UpdateArticle, Roles: editors},
+	{Action: UpdateArticle, Roles: []string{auth.RoleAuthor}, Own: true},
+	{Action: DeleteArticle, Roles: editors},
+	{Action: DeleteArticle, Roles: []string{auth.RoleAuthor}, Own: true},
+	{Action: RestoreArticle, Roles: editors},
+	{Action: ReadDeleted, Roles: admins},
+	{Action: PurgeArticle, Roles: admins},
+	{Action: ManageAPIKeys, Roles: admins},
+	{Action: ReadAuthor, Roles: everyone},
+	{Action: ManageAuthors, Roles: editors},
+	{Action: ReadUnpublished, Roles: editors},
+	{Action: ReadUnpublished, Roles: []string{auth.RoleAuthor}, Own: true},
+	{Action: ReadHistory, Roles: editors},
+	{Action: ReadHistory, Roles: []string{auth.RoleAuthor}, Own: true},
+	{Action: SubmitArticle, Roles: editors},
+	{Action: SubmitArticle, Roles: []string{auth.RoleAuthor}, Own: true},
+	{Action: ReviewArticle, Roles: editors},
+	{Action: ArchiveArticle, Roles: editors},
+}
+
+// Resource is what an action is performed on, OwnerID is empty for resources without owner
+type Resource struct {
+	OwnerID string
+}
+
+// Denied is returned when the policy refuses an action, its message is safe to show the caller
+type Denied struct {
+	Role   string
+	Action Action
+
+	// Own is set when the role may perform the action on its own resources only
+	Own bool
+
+	// AdminOnly is set when only admins may perform the action
+	AdminOnly bool
+}
+
+func (d *Denied) Error() string {
+	switch {
+	case d.AdminOnly:
+		return "admin access required"
+	case d.Own:
+		return fmt.Sprintf("%s may only %s they own", d.Role, d.Action)
+	default:
+		return fmt.Sprintf("%s may not %s", d.Role, d.Action)
+	}
+}
+
+// Unwrap makes denials match ErrForbidden
+func (d *Denied) Unwrap() error {
+	return ErrForbidden
+}
+
+// role returns role of p, nil is anonymous
+func role(p *auth.Principal) string {
+	if p == nil {
+		return Anonymous
+	}
+
+	return p.Role
+}
+
+// OwnOnly reports whether p may perform action only on resources it owns,
+// callers load the resource to check its owner only then
+func (pol Policy) OwnOnly(p *auth.Principal, action Action) bool {
+	all, own := pol.grants(role(p), action)
+
+	return !all && own
+}
+
+// Check returns a *Denied unless p, nil for anonymous requests, may perform action on r
+func (pol Policy) Check(p *auth.Principal, action Action, r Resource) error {
+	all, own := pol.grants(role(p), action)
+	if all {
+		return nil
+	}
+
+	if own && p != nil && r.OwnerID != "" && r.OwnerID == p.Subject {
+		return nil
+	}
+
+	return &Denied{Role: role(p), Action: action, Own: own, AdminOnly: pol.adminOnly(action)}
+}
+
+// grants reports whether rules grant role action on any resource or on its own ones
+func (pol Policy) grants(role string, action Action) (all, own bool) {
+	for _, rule := range pol {
+		if rule.Action != action || !contains(rule.Roles, role) {
+			continue
+		}
+
+		if rule.Own {
+			own = true
+		} else {
+			all = true
+		}
+	}
+
+	return all, own
+}
+
+// adminOnly reports whether only admins are granted action
+func (pol Policy) adminOnly(action Action) bool {
+	granted := false
+
+	for _, rule := range pol {
+		if rule.Action != action {
+			continue
+		}
+
+		for _, r := range rule.Roles {
+			if r != auth.RoleAdmin {
+				return false
+			}
+
+			granted = true
+		}
+	}
+
+	return granted
+}
+
+// contains reports whether roles holds role
+func contains(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
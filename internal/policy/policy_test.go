@@ -0,0 +1,151 @@
+package policy_test
+
+import (
+	"article/internal/auth"
+	"article/internal/policy"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Check(t *testing.T) {
+	principal := func(role string) *auth.Principal {
+		return &auth.Principal{Subject: "u-1", Role: role}
+	}
+
+	own := policy.Resource{OwnerID: "u-1"}
+	other := policy.Resource{OwnerID: "u-2"}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		action    policy.Action
+		resource  policy.Resource
+		wantErr   string
+	}{
+		{name: "anonymous reads", action: policy.ReadArticle},
+		{name: "reader reads", principal: principal(auth.RoleReader), action: policy.ReadArticle},
+		{name: "author creates", principal: principal(auth.RoleAuthor), action: policy.CreateArticle},
+		{name: "author updates own article", principal: principal(auth.RoleAuthor), action: policy.UpdateArticle, resource: own},
+		{name: "author deletes own article", principal: principal(auth.RoleAuthor), action: policy.DeleteArticle, resource: own},
+		{name: "editor updates any article", principal: principal(auth.RoleEditor), action: policy.UpdateArticle, resource: other},
+		{name: "editor updates article without owner", principal: principal(auth.RoleEditor), action: policy.UpdateArticle},
+		{name: "editor restores", principal: principal(auth.RoleEditor), action: policy.RestoreArticle},
+		{name: "admin purges", principal: principal(auth.RoleAdmin), action: policy.PurgeArticle},
+		{name: "admin manages keys", principal: principal(auth.RoleAdmin), action: policy.ManageAPIKeys},
+		{name: "anonymous reads authors", action: policy.ReadAuthor},
+		{name: "editor manages authors", principal: principal(auth.RoleEditor), action: policy.ManageAuthors},
+		{name: "author submits own article", principal: principal(auth.RoleAuthor), action: policy.SubmitArticle, resource: own},
+		{name: "author reads own draft", principal: principal(auth.RoleAuthor), action: policy.ReadUnpublished, resource: own},
+		{name: "editor reviews", principal: principal(auth.RoleEditor), action: policy.ReviewArticle, resource: other},
+		{name: "editor reads history of any article", principal: principal(auth.RoleEditor), action: policy.ReadHistory, resource: other},
+		{
+			name:      "author cannot review own article",
+			principal: principal(auth.RoleAuthor),
+			action:    policy.ReviewArticle,
+			resource:  own,
+			wantErr:   "author may not review articles",
+		},
+		{
+			name:      "author cannot submit others' article",
+			principal: principal(auth.RoleAuthor),
+			action:    policy.SubmitArticle,
+			resource:  other,
+			wantErr:   "author may only submit articles they own",
+		},
+		{
+			name:    "anonymous cannot read drafts",
+			action:  policy.ReadUnpublished,
+			wantErr: "anonymous may not read unpublished articles",
+		},
+		{
+			name:      "reader cannot archive",
+			principal: principal(auth.RoleReader),
+			action:    policy.ArchiveArticle,
+			wantErr:   "reader may not archive articles",
+		},
+		{
+			name:      "author cannot manage authors",
+			principal: principal(auth.RoleAuthor),
+			action:    policy.ManageAuthors,
+			wantErr:   "author may not manage authors",
+		},
+		{
+			name:    "anonymous cannot create",
+			action:  policy.CreateArticle,
+			wantErr: "anonymous may not create articles",
+		},
+		{
+			name:      "reader cannot create",
+			principal: principal(auth.RoleReader),
+			action:    policy.CreateArticle,
+			wantErr:   "reader may not create articles",
+		},
+		{
+			name:      "author cannot update others' article",
+			principal: principal(auth.RoleAuthor),
+			action:    policy.UpdateArticle,
+			resource:  other,
+			wantErr:   "author may only update articles they own",
+		},
+		{
+			name:      "author cannot update article without owner",
+			principal: &auth.Principal{Role: auth.RoleAuthor},
+			action:    policy.UpdateArticle,
+			wantErr:   "author may only update articles they own",
+		},
+		{
+			name:      "author cannot restore",
+			principal: principal(auth.RoleAuthor),
+			action:    policy.RestoreArticle,
+			resource:  own,
+			wantErr:   "author may not restore articles",
+		},
+		{
+			name:      "editor cannot purge",
+			principal: principal(auth.RoleEditor),
+			action:    policy.PurgeArticle,
+			wantErr:   "admin access required",
+		},
+		{
+			name:      "principal without role",
+			principal: principal(""),
+			action:    policy.ReadArticle,
+			wantErr:   " may not read articles",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Default.Check(tt.principal, tt.action, tt.resource)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, policy.ErrForbidden)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_OwnOnly(t *testing.T) {
+	author := &auth.Principal{Subject: "u-1", Role: auth.RoleAuthor}
+	editor := &auth.Principal{Subject: "u-1", Role: auth.RoleEditor}
+
+	assert.True(t, policy.Default.OwnOnly(author, policy.UpdateArticle))
+	assert.True(t, policy.Default.OwnOnly(author, policy.DeleteArticle))
+	assert.False(t, policy.Default.OwnOnly(author, policy.CreateArticle))
+	assert.False(t, policy.Default.OwnOnly(editor, policy.UpdateArticle))
+	assert.False(t, policy.Default.OwnOnly(nil, policy.UpdateArticle))
+}
+
+func Test_CustomPolicy(t *testing.T) {
+	// rules are data, a policy letting readers update their own articles needs no code
+	p := policy.Policy{{Action: policy.UpdateArticle, Roles: []string{auth.RoleReader}, Own: true}}
+	reader := &auth.Principal{Subject: "u-1", Role: auth.RoleReader}
+
+	assert.NoError(t, p.Check(reader, policy.UpdateArticle, policy.Resource{OwnerID: "u-1"}))
+	assert.ErrorIs(t, p.Check(reader, policy.DeleteArticle, policy.Resource{OwnerID: "u-1"}), policy.ErrForbidden)
+}
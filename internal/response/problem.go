@@ -0,0 +1,177 @@
+package response
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ProblemContentType is the media type of problem details (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// problem codes are stable identifiers of error responses, clients should match these instead of messages
+const (
+	CodeInvalidRequest      = "invalid_request"
+	CodeValidationFailed    = "validation_failed"
+	CodeUnauthorized        = "unauthorized"
+	CodeForbidden           = "forbidden"
+	CodeNotFound            = "not_found"
+	CodeMethodNotAllowed    = "method_not_allowed"
+	CodeConflict            = "conflict"
+	CodeClientClosedRequest = "client_closed_request"
+	CodeTimeout             = "timeout"
+	CodeUnavailable         = "unavailable"
+	CodeInternalError       = "internal_error"
+)
+
+// statusCodes maps error status to its problem code
+var statusCodes = map[int]string{
+	http.StatusBadRequest:          CodeInvalidRequest,
+	http.StatusUnauthorized:        CodeUnauthorized,
+	http.StatusForbidden:           CodeForbidden,
+	http.StatusNotFound:            CodeNotFound,
+	http.StatusMethodNotAllowed:    CodeMethodNotAllowed,
+	http.StatusConflict:            CodeConflict,
+	http.StatusUnprocessableEntity: CodeValidationFailed,
+	StatusClientClosedRequest:      CodeClientClosedRequest,
+	http.StatusGatewayTimeout:      CodeTimeout,
+	http.StatusServiceUnavailable:  CodeUnavailable,
+	http.StatusInternalServerError: CodeInternalError,
+}
+
+// Problem is an RFC 7807 problem details body
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes an invalid request field, rule and param name the failed check e.g. max and 255
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// problemWriter marks a response writer of a request which accepts problem details
+type problemWriter struct {
+	http.ResponseWriter
+
+	instance string
+}
+
+// Unwrap returns the wrapped response writer
+func (w *problemWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Negotiate is a middleware sending error responses as problem details to clients
+// which prefer application/problem+json, others keep receiving the Body envelope
+func Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+
+		if AcceptsProblem(r.Header.Get("Accept")) {
+			w = &problemWriter{ResponseWriter: w, instance: r.URL.Path}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AcceptsProblem reports whether accept header prefers application/problem+json over application/json,
+// wildcards are ignored so clients have to ask for problem details explicitly
+func AcceptsProblem(accept string) bool {
+	var problem, plain float64
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if val, ok := params["q"]; ok {
+			q, err = strconv.ParseFloat(val, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		switch mediaType {
+		case ProblemContentType:
+			problem = q
+		case "application/json":
+			plain = q
+		}
+	}
+
+	return problem > 0 && problem >= plain
+}
+
+// problemWriterOf finds the problem writer in a chain of wrapped response writers
+func problemWriterOf(w http.ResponseWriter) *problemWriter {
+	for {
+		switch t := w.(type) {
+		case *problemWriter:
+			return t
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = t.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// NewProblem prepares problem details from an error body, field errors in data are listed in errors
+func NewProblem(b *Body, data interface{}) *Problem {
+	p := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(b.Status),
+		Status: b.Status,
+		Detail: b.Message,
+		Code:   statusCodes[b.Status],
+	}
+
+	if b.Status == StatusClientClosedRequest {
+		p.Title = "Client Closed Request"
+	}
+
+	if p.Code == "" {
+		p.Code = CodeInternalError
+	}
+
+	switch items := data.(type) {
+	case []FieldError:
+		p.Errors = items
+	case []interface{}:
+		for _, item := range items {
+			if fieldErr, ok := item.(FieldError); ok {
+				p.Errors = append(p.Errors, fieldErr)
+			}
+		}
+	}
+
+	// a bad request with field errors failed validation
+	if len(p.Errors) > 0 && b.Status == http.StatusBadRequest {
+		p.Code = CodeValidationFailed
+	}
+
+	return &p
+}
+
+// sendProblem writes problem details
+func sendProblem(w http.ResponseWriter, p *Problem) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+
+	// write json encoded to writer
+	json.NewEncoder(w).Encode(p)
+}
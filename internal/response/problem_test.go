@@ -0,0 +1,155 @@
+package response_test
+
+import (
+	"article/internal/response"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AcceptsProblem(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   bool
+	}{
+		{name: "empty", accept: "", want: false},
+		{name: "wildcard", accept: "*/*", want: false},
+		{name: "json", accept: "application/json", want: false},
+		{name: "problem", accept: "application/problem+json", want: true},
+		{name: "problem preferred", accept: "application/json;q=0.5, application/problem+json", want: true},
+		{name: "json preferred", accept: "application/problem+json;q=0.5, application/json", want: false},
+		{name: "problem refused", accept: "application/problem+json;q=0", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, response.AcceptsProblem(tt.accept), tt.want)
+		})
+	}
+}
+
+func Test_Negotiate(t *testing.T) {
+	tests := []struct {
+		name            string
+		accept          string
+		send            func(w http.ResponseWriter)
+		wantStatus      int
+		wantContentType string
+		wantProblem     *response.Problem
+		wantRespBody    *response.Body
+	}{
+		{
+			name:   "problem : validation failed",
+			accept: response.ProblemContentType,
+			send: func(w http.ResponseWriter) {
+				response.New().ValidationFailed(w, "invalid title", []response.FieldError{{Field: "title", Rule: "required", Message: "must not be empty"}})
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: response.ProblemContentType,
+			wantProblem: &response.Problem{
+				Type:     "about:blank",
+				Title:    "Bad Request",
+				Status:   http.StatusBadRequest,
+				Detail:   "invalid title",
+				Instance: "/articles",
+				Code:     response.CodeValidationFailed,
+				Errors:   []response.FieldError{{Field: "title", Rule: "required", Message: "must not be empty"}},
+			},
+		},
+		{
+			name:   "problem : query params",
+			accept: response.ProblemContentType,
+			send: func(w http.ResponseWriter) {
+				response.New().BadRequest(w, "invalid query parameters", response.FieldError{Field: "sort", Rule: "unique", Message: "'title' is repeated"})
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: response.ProblemContentType,
+			wantProblem: &response.Problem{
+				Type:     "about:blank",
+				Title:    "Bad Request",
+				Status:   http.StatusBadRequest,
+				Detail:   "invalid query parameters",
+				Instance: "/articles",
+				Code:     response.CodeValidationFailed,
+				Errors:   []response.FieldError{{Field: "sort", Rule: "unique", Message: "'title' is repeated"}},
+			},
+		},
+		{
+			name:   "problem : not found",
+			accept: response.ProblemContentType,
+			send: func(w http.ResponseWriter) {
+				response.New().NotFound(w, "article not found")
+			},
+			wantStatus:      http.StatusNotFound,
+			wantContentType: response.ProblemContentType,
+			wantProblem: &response.Problem{
+				Type:     "about:blank",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "article not found",
+				Instance: "/articles",
+				Code:     response.CodeNotFound,
+			},
+		},
+		{
+			name:   "problem : success keeps envelope",
+			accept: response.ProblemContentType,
+			send: func(w http.ResponseWriter) {
+				response.New().Success(w, nil)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json",
+			wantRespBody:    &response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
+		},
+		{
+			name:   "envelope : validation failed",
+			accept: "application/json",
+			send: func(w http.ResponseWriter) {
+				response.New().ValidationFailed(w, "invalid title", []response.FieldError{{Field: "title", Rule: "required", Message: "must not be empty"}})
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: "application/json",
+			wantRespBody:    &response.Body{Status: http.StatusBadRequest, Message: "invalid title"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/articles", nil)
+			r.Header.Set("Accept", tt.accept)
+
+			response.Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.send(w)
+			})).ServeHTTP(w, r)
+
+			assert.Equal(t, w.Code, tt.wantStatus)
+			assert.Equal(t, w.Header().Get("Content-Type"), tt.wantContentType)
+			assert.Equal(t, w.Header().Get("Vary"), "Accept")
+
+			if tt.wantProblem != nil {
+				var gotProblem response.Problem
+				err := json.Unmarshal(w.Body.Bytes(), &gotProblem)
+				if err != nil {
+					t.Errorf("error unmarshalling response : %v", err)
+				}
+
+				assert.Equal(t, &gotProblem, tt.wantProblem)
+			}
+
+			if tt.wantRespBody != nil {
+				var gotRespBody response.Body
+				err := json.Unmarshal(w.Body.Bytes(), &gotRespBody)
+				if err != nil {
+					t.Errorf("error unmarshalling response : %v", err)
+				}
+
+				assert.Equal(t, &gotRespBody, tt.wantRespBody)
+			}
+		})
+	}
+}
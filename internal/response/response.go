@@ -1,7 +1,9 @@
 package response
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
@@ -10,6 +12,9 @@ const (
 	StatusError   = "error"
 )
 
+// StatusClientClosedRequest is the non standard status used when client goes away before response is sent
+const StatusClientClosedRequest = 499
+
 type Response struct{}
 
 // Body response body structure for API
@@ -17,6 +22,14 @@ type Body struct {
 	Status  int         `json:"status"`
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
+	*Pagination
+}
+
+// Pagination holds pagination details of a list response
+type Pagination struct {
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 // New retuns response obj
@@ -39,6 +52,11 @@ func (b *Body) SetData(data interface{}) {
 	b.Data = data
 }
 
+// SetPagination sets pagination details
+func (b *Body) SetPagination(p *Pagination) {
+	b.Pagination = p
+}
+
 // GetStatus gets response status
 func (b *Body) GetStatus() int {
 	return b.Status
@@ -62,6 +80,16 @@ func (r *Response) Success(w http.ResponseWriter, data interface{}) {
 	SendResponse(w, &b, data)
 }
 
+// Paginated handles 200 success response for a page of list
+func (r *Response) Paginated(w http.ResponseWriter, data interface{}, p Pagination) {
+	b := Body{}
+	b.SetStatus(http.StatusOK)
+	b.SetMessage(StatusSuccess)
+	b.SetPagination(&p)
+
+	SendResponse(w, &b, data)
+}
+
 // BadRequest handles 400 error response
 func (r *Response) BadRequest(w http.ResponseWriter, msg string, data ...interface{}) {
 	b := Body{}
@@ -71,6 +99,22 @@ func (r *Response) BadRequest(w http.ResponseWriter, msg string, data ...interfa
 	SendResponse(w, &b, data)
 }
 
+// ValidationFailed handles 400 error response of an invalid request body, field errors
+// are listed in problem details only so the envelope stays as older clients expect it
+func (r *Response) ValidationFailed(w http.ResponseWriter, msg string, errs []FieldError) {
+	b := Body{}
+	b.SetStatus(http.StatusBadRequest)
+	b.SetMessage(msg)
+
+	if problemWriterOf(w) == nil {
+		SendResponse(w, &b, nil)
+
+		return
+	}
+
+	SendResponse(w, &b, errs)
+}
+
 // InternalServerError handles 500 error response
 func (r *Response) InternalServerError(w http.ResponseWriter, msg string, data ...interface{}) {
 	b := Body{}
@@ -80,6 +124,24 @@ func (r *Response) InternalServerError(w http.ResponseWriter, msg string, data .
 	SendResponse(w, &b, data)
 }
 
+// Unauthorized handles 401 error response, callers set the WWW-Authenticate header
+func (r *Response) Unauthorized(w http.ResponseWriter, msg string, data ...interface{}) {
+	b := Body{}
+	b.SetStatus(http.StatusUnauthorized)
+	b.SetMessage(msg)
+
+	SendResponse(w, &b, data)
+}
+
+// Forbidden handles 403 error response
+func (r *Response) Forbidden(w http.ResponseWriter, msg string, data ...interface{}) {
+	b := Body{}
+	b.SetStatus(http.StatusForbidden)
+	b.SetMessage(msg)
+
+	SendResponse(w, &b, data)
+}
+
 // NotFound handles 404 error response
 func (r *Response) NotFound(w http.ResponseWriter, msg string, data ...interface{}) {
 	b := Body{}
@@ -89,6 +151,64 @@ func (r *Response) NotFound(w http.ResponseWriter, msg string, data ...interface
 	SendResponse(w, &b, data)
 }
 
+// Conflict handles 409 error response
+func (r *Response) Conflict(w http.ResponseWriter, msg string, data ...interface{}) {
+	b := Body{}
+	b.SetStatus(http.StatusConflict)
+	b.SetMessage(msg)
+
+	SendResponse(w, &b, data)
+}
+
+// UnprocessableEntity handles 422 error response
+func (r *Response) UnprocessableEntity(w http.ResponseWriter, msg string, data ...interface{}) {
+	b := Body{}
+	b.SetStatus(http.StatusUnprocessableEntity)
+	b.SetMessage(msg)
+
+	SendResponse(w, &b, data)
+}
+
+// ServiceUnavailable handles 503 error response, data e.g. failing checks is sent as is
+func (r *Response) ServiceUnavailable(w http.ResponseWriter, msg string, data interface{}) {
+	b := Body{}
+	b.SetStatus(http.StatusServiceUnavailable)
+	b.SetMessage(msg)
+
+	SendResponse(w, &b, data)
+}
+
+// GatewayTimeout handles 504 error response
+func (r *Response) GatewayTimeout(w http.ResponseWriter, msg string, data ...interface{}) {
+	b := Body{}
+	b.SetStatus(http.StatusGatewayTimeout)
+	b.SetMessage(msg)
+
+	SendResponse(w, &b, data)
+}
+
+// ClientClosedRequest handles 499 error response
+func (r *Response) ClientClosedRequest(w http.ResponseWriter, msg string, data ...interface{}) {
+	b := Body{}
+	b.SetStatus(StatusClientClosedRequest)
+	b.SetMessage(msg)
+
+	SendResponse(w, &b, data)
+}
+
+// ServerError handles error response of a failed operation, deadline exceeded is reported as 504,
+// canceled as 499 and any other error as 500 with msg
+func (r *Response) ServerError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		r.GatewayTimeout(w, "request timed out")
+	case errors.Is(err, context.Canceled):
+		r.ClientClosedRequest(w, "client closed request")
+	default:
+		r.InternalServerError(w, msg)
+	}
+}
+
 // NotAllowed handles 405 error response
 func (r *Response) NotAllowed(w http.ResponseWriter, msg string, data ...interface{}) {
 	b := Body{}
@@ -98,8 +218,17 @@ func (r *Response) NotAllowed(w http.ResponseWriter, msg string, data ...interfa
 	SendResponse(w, &b, data)
 }
 
-// SendResponse
+// SendResponse writes body as json, errors are sent as problem details when negotiated
 func SendResponse(w http.ResponseWriter, b *Body, data interface{}) {
+	if pw := problemWriterOf(w); pw != nil && b.GetStatus() >= http.StatusBadRequest {
+		p := NewProblem(b, data)
+		p.Instance = pw.instance
+
+		sendProblem(w, p)
+
+		return
+	}
+
 	b.SetData(data)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(b.GetStatus())
@@ -3,7 +3,10 @@ package response_test
 import (
 	"article/internal/handler"
 	"article/internal/response"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -23,26 +26,39 @@ func Test_Created(t *testing.T) {
 			mockResp: func() *httptest.ResponseRecorder {
 				resp := response.New()
 				w := httptest.NewRecorder()
-				data := handler.ArticleRequest{Title: "Test title", Content: "Test content", Author: "Test Author"}
+				data := handler.ArticleResponse{Title: "Test title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test Author"}}
 				resp.Created(w, data)
 
 				return w
 			},
 			wantRespBody: response.Body{Status: http.StatusCreated, Message: response.StatusSuccess},
-			wantResp:     handler.ArticleResponse{Title: "Test title", Content: "Test content", Author: "Test Author"},
+			wantResp:     handler.ArticleResponse{Title: "Test title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test Author"}},
 		},
 		{
 			name: "response success",
 			mockResp: func() *httptest.ResponseRecorder {
 				resp := response.New()
 				w := httptest.NewRecorder()
-				data := handler.ArticleRequest{Title: "Test title", Content: "Test content", Author: "Test Author"}
+				data := handler.ArticleResponse{Title: "Test title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test Author"}}
 				resp.Success(w, data)
 
 				return w
 			},
 			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess},
-			wantResp:     handler.ArticleResponse{Title: "Test title", Content: "Test content", Author: "Test Author"},
+			wantResp:     handler.ArticleResponse{Title: "Test title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test Author"}},
+		},
+		{
+			name: "response paginated",
+			mockResp: func() *httptest.ResponseRecorder {
+				resp := response.New()
+				w := httptest.NewRecorder()
+				data := handler.ArticleResponse{Title: "Test title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test Author"}}
+				resp.Paginated(w, data, response.Pagination{Total: 10, NextCursor: "next"})
+
+				return w
+			},
+			wantRespBody: response.Body{Status: http.StatusOK, Message: response.StatusSuccess, Pagination: &response.Pagination{Total: 10, NextCursor: "next"}},
+			wantResp:     handler.ArticleResponse{Title: "Test title", Content: "Test content", Author: &handler.AuthorResponse{Name: "Test Author"}},
 		},
 		{
 			name: "error bad request",
@@ -66,6 +82,28 @@ func Test_Created(t *testing.T) {
 			},
 			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error message - internal server error"},
 		},
+		{
+			name: "error unauthorized",
+			mockResp: func() *httptest.ResponseRecorder {
+				resp := response.New()
+				w := httptest.NewRecorder()
+				resp.Unauthorized(w, "error message - unauthorized")
+
+				return w
+			},
+			wantRespBody: response.Body{Status: http.StatusUnauthorized, Message: "error message - unauthorized"},
+		},
+		{
+			name: "error forbidden",
+			mockResp: func() *httptest.ResponseRecorder {
+				resp := response.New()
+				w := httptest.NewRecorder()
+				resp.Forbidden(w, "error message - forbidden")
+
+				return w
+			},
+			wantRespBody: response.Body{Status: http.StatusForbidden, Message: "error message - forbidden"},
+		},
 		{
 			name: "error not found",
 			mockResp: func() *httptest.ResponseRecorder {
@@ -88,6 +126,61 @@ func Test_Created(t *testing.T) {
 			},
 			wantRespBody: response.Body{Status: http.StatusMethodNotAllowed, Message: "error message - method not allowed"},
 		},
+		{
+			name: "error conflict",
+			mockResp: func() *httptest.ResponseRecorder {
+				resp := response.New()
+				w := httptest.NewRecorder()
+				resp.Conflict(w, "error message - conflict")
+
+				return w
+			},
+			wantRespBody: response.Body{Status: http.StatusConflict, Message: "error message - conflict"},
+		},
+		{
+			name: "error unprocessable entity",
+			mockResp: func() *httptest.ResponseRecorder {
+				resp := response.New()
+				w := httptest.NewRecorder()
+				resp.UnprocessableEntity(w, "error message - unprocessable entity")
+
+				return w
+			},
+			wantRespBody: response.Body{Status: http.StatusUnprocessableEntity, Message: "error message - unprocessable entity"},
+		},
+		{
+			name: "server error deadline exceeded",
+			mockResp: func() *httptest.ResponseRecorder {
+				resp := response.New()
+				w := httptest.NewRecorder()
+				resp.ServerError(w, fmt.Errorf("query : %w", context.DeadlineExceeded), "error message - internal server error")
+
+				return w
+			},
+			wantRespBody: response.Body{Status: http.StatusGatewayTimeout, Message: "request timed out"},
+		},
+		{
+			name: "server error canceled",
+			mockResp: func() *httptest.ResponseRecorder {
+				resp := response.New()
+				w := httptest.NewRecorder()
+				resp.ServerError(w, context.Canceled, "error message - internal server error")
+
+				return w
+			},
+			wantRespBody: response.Body{Status: response.StatusClientClosedRequest, Message: "client closed request"},
+		},
+		{
+			name: "server error",
+			mockResp: func() *httptest.ResponseRecorder {
+				resp := response.New()
+				w := httptest.NewRecorder()
+				resp.ServerError(w, errors.New("db error"), "error message - internal server error")
+
+				return w
+			},
+			wantRespBody: response.Body{Status: http.StatusInternalServerError, Message: "error message - internal server error"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -116,6 +209,7 @@ func Test_Created(t *testing.T) {
 			assert.Equal(t, gotResp.ID, tt.wantResp.ID)
 			assert.Equal(t, resp.Status, tt.wantRespBody.Status)
 			assert.Equal(t, resp.Message, tt.wantRespBody.Message)
+			assert.Equal(t, resp.Pagination, tt.wantRespBody.Pagination)
 		})
 	}
 
@@ -0,0 +1,358 @@
+package routes_test
+
+import (
+	"article/internal/auth"
+	"article/internal/config"
+	"article/internal/handler"
+	"article/internal/models"
+	"article/internal/response"
+	"article/internal/routes"
+	"article/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// secret signs test tokens
+const secret = "test-secret"
+
+// schemes returns schemes verifying bearer tokens signed by token
+func schemes(t *testing.T) auth.Schemes {
+	v, err := auth.NewVerifier(auth.Config{HS256Secret: secret})
+	require.NoError(t, err)
+
+	return auth.Schemes{auth.SchemeBearer: v}
+}
+
+// token returns HS256 token of claims expiring in an hour unless claims set exp
+func token(t *testing.T, claims jwt.MapClaims) string {
+	if _, ok := claims["exp"]; !ok {
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	return signed
+}
+
+func Test_Auth(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		body          string
+		authorization string
+		mockDB        func() *models.Models
+		wantStatus    int
+		wantChallenge string
+
+		// wantChallenges are all challenges when set
+		wantChallenges []string
+	}{
+		{
+			name:          "success - author is taken from the token",
+			method:        http.MethodPost,
+			path:          "/articles",
+			body:          `{"title":"title","content":"content","author":"mallory"}`,
+			authorization: "Bearer " + token(t, jwt.MapClaims{"sub": "u-1", "name": "Jane", "scope": "articles:read articles:write"}),
+			mockDB: func() *models.Models {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().Store(mock.Anything, &models.Article{Title: "title", Content: "content", Author: "Jane", OwnerID: "u-1", AuthorSubject: "u-1"}).Return(1, nil)
+
+				return &models.Models{Article: articleMock}
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:          "error - token without scope may only read",
+			method:        http.MethodPost,
+			path:          "/articles",
+			body:          `{"title":"title","content":"content"}`,
+			authorization: "Bearer " + token(t, jwt.MapClaims{"sub": "u-1", "name": "Jane", "role": "admin"}),
+			mockDB:        func() *models.Models { return &models.Models{} },
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:          "error - admin role without admin scope cannot manage keys",
+			method:        http.MethodPost,
+			path:          "/admin/keys",
+			body:          `{"name":"ops","scopes":["admin"]}`,
+			authorization: "Bearer " + token(t, jwt.MapClaims{"sub": "u-1", "name": "Jane", "role": "admin", "scope": "articles:read"}),
+			mockDB:        func() *models.Models { return &models.Models{} },
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:   "success - reads need no token",
+			method: http.MethodGet,
+			path:   "/articles/1",
+			mockDB: func() *models.Models {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, Status: models.StatusPublished}, nil)
+
+				return &models.Models{Article: articleMock}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "error - drafts are hidden without token",
+			method: http.MethodGet,
+			path:   "/articles/1",
+			mockDB: func() *models.Models {
+				articleMock := mocks.NewArticleStore(t)
+				articleMock.EXPECT().GetByID(mock.Anything, 1, false).Return(&models.Article{ID: 1, Status: models.StatusDraft, OwnerID: "u-1"}, nil)
+
+				return &models.Models{Article: articleMock}
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "success - authors are public",
+			method: http.MethodGet,
+			path:   "/authors/1",
+			mockDB: func() *models.Models {
+				authorMock := mocks.NewAuthorStore(t)
+				authorMock.EXPECT().GetByID(mock.Anything, 1).Return(&models.Author{ID: 1, Name: "Jane"}, nil)
+
+				return &models.Models{Author: authorMock}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:          "error - author manages authors",
+			method:        http.MethodPost,
+			path:          "/authors",
+			body:          `{"name":"Jane"}`,
+			authorization: "Bearer " + token(t, jwt.MapClaims{"sub": "u-1", "name": "Jane", "scope": "articles:read articles:write"}),
+			mockDB:        func() *models.Models { return &models.Models{} },
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:           "error - write without token",
+			method:         http.MethodDelete,
+			path:           "/articles/1",
+			mockDB:         func() *models.Models { return &models.Models{} },
+			wantStatus:     http.StatusUnauthorized,
+			wantChallenge:  `Bearer realm="article"`,
+			wantChallenges: []string{`Bearer realm="article"`, `ApiKey realm="article"`},
+		},
+		{
+			name:          "error - expired token",
+			method:        http.MethodPost,
+			path:          "/articles",
+			body:          `{"title":"title","content":"content"}`,
+			authorization: "Bearer " + token(t, jwt.MapClaims{"sub": "u-1", "exp": time.Now().Add(-time.Hour).Unix()}),
+			mockDB:        func() *models.Models { return &models.Models{} },
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Bearer realm="article", error="invalid_token"`,
+		},
+		{
+			name:          "error - unsupported scheme",
+			method:        http.MethodGet,
+			path:          "/articles/1",
+			authorization: "Basic amFuZTpzZWNyZXQ=",
+			mockDB:        func() *models.Models { return &models.Models{} },
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Bearer realm="article", error="invalid_token"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := routes.InitRoutes(handler.New(tt.mockDB()), config.CORS{}, nil, discard(), schemes(t))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			var body response.Body
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+			assert.Equal(t, w.Code, tt.wantStatus)
+			assert.Equal(t, body.Status, tt.wantStatus)
+			assert.Equal(t, w.Header().Get("WWW-Authenticate"), tt.wantChallenge)
+
+			if tt.wantChallenges != nil {
+				assert.Equal(t, w.Header().Values("WWW-Authenticate"), tt.wantChallenges)
+			}
+		})
+	}
+}
+
+func Test_APIKeyAuth(t *testing.T) {
+	m, err := models.NewMemoryModels("")
+	require.NoError(t, err)
+
+	keys := auth.NewAPIKeys(m.APIKey)
+
+	readKey, _, err := keys.Create(context.Background(), "reader", []string{auth.ScopeRead}, 0)
+	require.NoError(t, err)
+
+	writeKey, _, err := keys.Create(context.Background(), "importer", []string{auth.ScopeRead, auth.ScopeWrite}, 0)
+	require.NoError(t, err)
+
+	adminKey, _, err := keys.Create(context.Background(), "ops", []string{auth.ScopeAdmin}, 0)
+	require.NoError(t, err)
+
+	reviewKey, _, err := keys.Create(context.Background(), "reviewer", []string{auth.ScopeRead, auth.ScopeWrite, auth.ScopeAdmin}, 0)
+	require.NoError(t, err)
+
+	s := schemes(t)
+	s[auth.SchemeAPIKey] = keys
+
+	mux := routes.InitRoutes(handler.New(m), config.CORS{}, nil, discard(), s)
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		body          string
+		authorization string
+		wantStatus    int
+	}{
+		{
+			name:          "success - author is the key name",
+			method:        http.MethodPost,
+			path:          "/articles",
+			body:          `{"title":"title","content":"content"}`,
+			authorization: "ApiKey " + writeKey,
+			wantStatus:    http.StatusCreated,
+		},
+		{
+			name:          "error - read scope cannot read drafts",
+			method:        http.MethodGet,
+			path:          "/articles/1",
+			authorization: "ApiKey " + readKey,
+			wantStatus:    http.StatusNotFound,
+		},
+		{
+			name:          "success - write scope submits own article",
+			method:        http.MethodPost,
+			path:          "/articles/1/submit",
+			body:          `{"comment":"ready"}`,
+			authorization: "ApiKey " + writeKey,
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "error - write scope cannot publish",
+			method:        http.MethodPost,
+			path:          "/articles/1/publish",
+			authorization: "ApiKey " + writeKey,
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:          "success - admin publishes",
+			method:        http.MethodPost,
+			path:          "/articles/1/publish",
+			authorization: "ApiKey " + reviewKey,
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "success - read scope reads",
+			method:        http.MethodGet,
+			path:          "/articles/1",
+			authorization: "apikey " + readKey,
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "success - admin scope manages keys",
+			method:        http.MethodGet,
+			path:          "/admin/keys",
+			authorization: "ApiKey " + adminKey,
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "error - read scope cannot write",
+			method:        http.MethodDelete,
+			path:          "/articles/1",
+			authorization: "ApiKey " + readKey,
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:          "error - admin scope alone cannot read",
+			method:        http.MethodGet,
+			path:          "/articles/1",
+			authorization: "ApiKey " + adminKey,
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:          "error - write scope cannot manage keys",
+			method:        http.MethodGet,
+			path:          "/admin/keys",
+			authorization: "ApiKey " + writeKey,
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:          "error - unknown key",
+			method:        http.MethodGet,
+			path:          "/articles/1",
+			authorization: "ApiKey ak_0000000000000000.secret",
+			wantStatus:    http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", tt.authorization)
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, w.Code, tt.wantStatus)
+		})
+	}
+
+	// the first article was created by the key
+	article, err := m.Article.GetByID(context.Background(), 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, article.Author, "importer")
+	assert.Equal(t, article.Status, models.StatusPublished)
+}
+
+func Test_APIKeyStoreError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "error - store fails", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+		{name: "error - store times out", err: context.DeadlineExceeded, wantStatus: http.StatusGatewayTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := mocks.NewAPIKeyStore(t)
+			store.EXPECT().GetByPrefix(mock.Anything, "0000000000000000").Return(nil, tt.err)
+
+			s := schemes(t)
+			s[auth.SchemeAPIKey] = auth.NewAPIKeysWithTimeout(store, time.Second)
+
+			mux := routes.InitRoutes(handler.New(&models.Models{}), config.CORS{}, nil, discard(), s)
+
+			req := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+			req.Header.Set("Authorization", "ApiKey ak_0000000000000000.secret")
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			// a server fault is not reported as bad credentials
+			assert.Equal(t, w.Code, tt.wantStatus)
+			assert.Empty(t, w.Header().Values("WWW-Authenticate"))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package routes_test
+
+import (
+	"article/internal/config"
+	"article/internal/handler"
+	"article/internal/models"
+	"article/internal/routes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CORS(t *testing.T) {
+	allowed := config.Default().CORS
+	allowed.AllowedOrigins = []string{"https://example.com"}
+
+	tests := []struct {
+		name        string
+		cors        func() config.CORS
+		method      string
+		origin      string
+		wantStatus  int
+		wantOrigin  string
+		wantMethods string
+		wantMaxAge  string
+	}{
+		{
+			name:        "success - preflight from allowed origin",
+			cors:        func() config.CORS { return allowed },
+			method:      http.MethodOptions,
+			origin:      "https://example.com",
+			wantStatus:  http.StatusNoContent,
+			wantOrigin:  "https://example.com",
+			wantMethods: "GET, POST, PUT, PATCH, DELETE",
+			wantMaxAge:  "600",
+		},
+		{
+			name: "success - any origin",
+			cors: func() config.CORS {
+				cfg := allowed
+				cfg.AllowedOrigins = []string{"*"}
+				cfg.MaxAge = time.Minute
+
+				return cfg
+			},
+			method:      http.MethodOptions,
+			origin:      "https://other.com",
+			wantStatus:  http.StatusNoContent,
+			wantOrigin:  "*",
+			wantMethods: "GET, POST, PUT, PATCH, DELETE",
+			wantMaxAge:  "60",
+		},
+		{
+			name:       "success - simple request from allowed origin",
+			cors:       func() config.CORS { return allowed },
+			method:     http.MethodGet,
+			origin:     "https://example.com",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantOrigin: "https://example.com",
+		},
+		{
+			name:       "error - origin not allowed",
+			cors:       func() config.CORS { return allowed },
+			method:     http.MethodOptions,
+			origin:     "https://other.com",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "error - cors disabled",
+			cors:       func() config.CORS { return config.Default().CORS },
+			method:     http.MethodOptions,
+			origin:     "https://example.com",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := routes.InitRoutes(handler.New(&models.Models{}), tt.cors(), nil, discard(), schemes(t))
+
+			r := httptest.NewRequest(tt.method, "/articles/1/restore", nil)
+			r.Header.Set("Origin", tt.origin)
+
+			if tt.method == http.MethodOptions {
+				r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, w.Code, tt.wantStatus)
+			assert.Equal(t, w.Header().Get("Access-Control-Allow-Origin"), tt.wantOrigin)
+			assert.Equal(t, w.Header().Get("Access-Control-Allow-Methods"), tt.wantMethods)
+			assert.Equal(t, w.Header().Get("Access-Control-Max-Age"), tt.wantMaxAge)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package routes_test
+
+import (
+	"article/internal/config"
+	"article/internal/handler"
+	"article/internal/logging"
+	"article/internal/models"
+	"article/internal/routes"
+	"article/internal/tracing"
+	"article/mocks"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// discard returns logger dropping every record
+func discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// records decodes JSON log lines of buf
+func records(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var recs []map[string]interface{}
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		rec := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+
+		recs = append(recs, rec)
+	}
+
+	return recs
+}
+
+func Test_Logging(t *testing.T) {
+	articleMock := mocks.NewArticleStore(t)
+	articleMock.EXPECT().Store(mock.Anything, mock.Anything).Return(int64(0), errors.New("db error"))
+
+	var buf bytes.Buffer
+
+	logger, err := logging.New(&buf, logging.DefaultConfig())
+	require.NoError(t, err)
+
+	mux := routes.InitRoutes(handler.New(&models.Models{Article: articleMock}), config.CORS{}, nil, logger, schemes(t))
+
+	req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{"title":"title","content":"private"}`))
+	req.Header.Set("Authorization", "Bearer "+token(t, jwt.MapClaims{"sub": "jane", "scope": "articles:read articles:write"}))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, w.Code, http.StatusInternalServerError)
+
+	recs := records(t, &buf)
+	require.Len(t, recs, 2)
+
+	// handler logs carry the request id, route and user
+	failed := recs[0]
+	assert.Equal(t, failed["level"], "ERROR")
+	assert.Equal(t, failed["msg"], "error storing article")
+	assert.Equal(t, failed["error"], "db error")
+	assert.Equal(t, failed["request_id"], w.Header().Get(tracing.TraceIDHeader))
+	assert.Equal(t, failed["route"], "/articles")
+	assert.Equal(t, failed["user"], "jane")
+
+	served := recs[1]
+	assert.Equal(t, served["level"], "ERROR")
+	assert.Equal(t, served["msg"], "request served")
+	assert.Equal(t, served["request_id"], w.Header().Get(tracing.TraceIDHeader))
+	assert.Equal(t, served["method"], http.MethodPost)
+	assert.Equal(t, served["route"], "/articles")
+	assert.Equal(t, served["status"], float64(http.StatusInternalServerError))
+}
@@ -0,0 +1,40 @@
+package routes_test
+
+import (
+	"article/internal/config"
+	"article/internal/handler"
+	"article/internal/metrics"
+	"article/internal/models"
+	"article/internal/routes"
+	"article/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Metrics(t *testing.T) {
+	articleMock := mocks.NewArticleStore(t)
+	articleMock.EXPECT().GetByID(mock.Anything, mock.Anything, false).Return(nil, models.ErrNotFound)
+
+	reg := metrics.NewRegistry()
+	mux := routes.InitRoutes(handler.New(&models.Models{Article: metrics.InstrumentArticleStore(reg, articleMock)}), config.CORS{}, reg, discard(), schemes(t))
+
+	// paths of the same route share series
+	for _, path := range []string{"/articles/1", "/articles/2", "/unknown"} {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	out := w.Body.String()
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Contains(t, out, `http_requests_total{method="GET",route="/articles/{article_id}",status="404"} 2`)
+	assert.Contains(t, out, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, out, `http_request_duration_seconds_count{method="GET",route="/articles/{article_id}",status="404"} 2`)
+	assert.Contains(t, out, `article_store_operation_errors_total{operation="get_by_id",kind="not_found"} 2`)
+	assert.NotContains(t, out, "/articles/1")
+}
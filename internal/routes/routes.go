@@ -1,21 +1,28 @@
 package routes
 
 import (
+	"log/slog"
 	"net/http"
 
+	"article/internal/auth"
+	"article/internal/config"
 	"article/internal/handler"
+	"article/internal/logging"
+	"article/internal/metrics"
+	"article/internal/models"
 	"article/internal/response"
+	"article/internal/tracing"
 
 	"github.com/go-chi/chi"
-	"github.com/go-chi/chi/middleware"
 )
 
-// InitRoutes initialises routes
-func InitRoutes(app *handler.Application) *chi.Mux {
+// InitRoutes initialises routes, requests are logged to logger and credentials are authenticated by schemes.
+// CORS is disabled when no origins are allowed and metrics when reg is nil
+func InitRoutes(app *handler.Application, corsConfig config.CORS, reg *metrics.Registry, logger *slog.Logger, schemes auth.Schemes) *chi.Mux {
 	r := chi.NewRouter()
 
-	r.Use(middleware.Logger)
-
+	// chi wraps these handlers in middlewares registered before them, they are set first
+	// so middlewares run once for unmatched requests too
 	// handling 404 page not found error
 	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
 		response.New().NotFound(w, http.StatusText(http.StatusNotFound))
@@ -26,10 +33,77 @@ func InitRoutes(app *handler.Application) *chi.Mux {
 		response.New().NotAllowed(w, http.StatusText(http.StatusMethodNotAllowed))
 	})
 
-	// route to handle article request
-	r.Post("/articles", app.CreateArticle())
-	r.Get("/articles/{article_id}", app.GetArticle())
-	r.Get("/articles", app.GetArticles())
+	// traces requests and sets the trace id as request id before the logger reads it
+	r.Use(tracing.Middleware)
+	r.Use(logging.Middleware(logger))
+
+	if reg != nil {
+		r.Use(metrics.HTTP(reg))
+	}
+
+	if len(corsConfig.AllowedOrigins) > 0 {
+		r.Use(cors(corsConfig))
+	}
+
+	// errors are sent as problem details to clients asking for application/problem+json
+	r.Use(response.Negotiate)
+
+	// requests with a bearer token or API key carry its principal, writes require one
+	r.Use(auth.Middleware(schemes))
+
+	// liveness and readiness probes, readiness fails while dependencies are down or the server drains on shutdown
+	r.Get("/healthz", app.Live())
+	r.Get("/readyz", app.Ready())
+
+	if reg != nil {
+		r.Method(http.MethodGet, "/metrics", reg.Handler())
+	}
+
+	// route to handle article and author requests, reads are public but credentials must grant reading
+	r.Group(func(r chi.Router) {
+		r.Use(auth.Restrict(auth.ScopeRead))
+
+		r.Get("/articles/{article_id}", app.GetArticle())
+		r.Get("/articles", app.GetArticles())
+		r.Get("/articles/search", app.SearchArticles())
+		r.Get("/articles/{article_id}/history", app.GetArticleHistory())
+
+		r.Get("/authors", app.GetAuthors())
+		r.Get("/authors/{author_id}", app.GetAuthor())
+		r.Get("/authors/{author_id}/articles", app.GetAuthorArticles())
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(auth.Require(auth.ScopeWrite))
+
+		r.Post("/articles", app.CreateArticle())
+		r.Put("/articles/{article_id}", app.UpdateArticle())
+		r.Patch("/articles/{article_id}", app.PatchArticle())
+		r.Delete("/articles/{article_id}", app.DeleteArticle())
+		r.Post("/articles/{article_id}/restore", app.RestoreArticle())
+		r.Post("/articles/{article_id}/purge", app.PurgeArticle())
+
+		// editorial workflow
+		for _, t := range models.Transitions {
+			r.Post("/articles/{article_id}/"+t.Name, app.TransitionArticle(t.Name))
+		}
+
+		r.Post("/authors", app.CreateAuthor())
+		r.Put("/authors/{author_id}", app.UpdateAuthor())
+		r.Delete("/authors/{author_id}", app.DeleteAuthor())
+	})
+
+	// API key management needs the admin scope as keys grant scopes, the admin token stands in for
+	// requests without other credentials so it can issue the first key. Handlers check the admin role
+	r.Group(func(r chi.Router) {
+		r.Use(app.AdminToken)
+		r.Use(auth.Require(auth.ScopeAdmin))
+
+		r.Post("/admin/keys", app.CreateAPIKey())
+		r.Get("/admin/keys", app.GetAPIKeys())
+		r.Delete("/admin/keys/{key_id}", app.RevokeAPIKey())
+		r.Post("/admin/keys/{key_id}/rotate", app.RotateAPIKey())
+	})
 
 	return r
 }
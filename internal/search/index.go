@@ -0,0 +1,224 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+const (
+	// bm25 tuning parameters
+	k1 = 1.2
+	b  = 0.75
+
+	// titleBoost weights matches in title over matches in content
+	titleBoost = 2.0
+)
+
+// field names indexed for a document
+const (
+	fieldTitle = iota
+	fieldContent
+	fieldCount
+)
+
+// Hit holds a matching document and its relevance score
+type Hit struct {
+	ID    int
+	Score float64
+}
+
+// Index is a concurrency safe in-memory inverted index over article title and content
+type Index struct {
+	mu sync.RWMutex
+
+	// postings maps token to document id to token positions per field
+	postings map[string]map[int]*[fieldCount][]int
+
+	// lengths holds token count per field of each document
+	lengths map[int][fieldCount]int
+
+	// totalLength holds token count per field of all documents
+	totalLength [fieldCount]int
+}
+
+// NewIndex returns an empty index
+func NewIndex() *Index {
+	return &Index{
+		postings: map[string]map[int]*[fieldCount][]int{},
+		lengths:  map[int][fieldCount]int{},
+	}
+}
+
+// Add indexes a document, an existing document with same id is replaced
+func (ix *Index) Add(id int, title, content string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+
+	var lengths [fieldCount]int
+
+	for field, text := range [fieldCount]string{title, content} {
+		tokens := Tokenize(text)
+		lengths[field] = len(tokens)
+		ix.totalLength[field] += len(tokens)
+
+		for pos, token := range tokens {
+			docs, ok := ix.postings[token]
+			if !ok {
+				docs = map[int]*[fieldCount][]int{}
+				ix.postings[token] = docs
+			}
+
+			positions, ok := docs[id]
+			if !ok {
+				positions = &[fieldCount][]int{}
+				docs[id] = positions
+			}
+
+			positions[field] = append(positions[field], pos)
+		}
+	}
+
+	ix.lengths[id] = lengths
+}
+
+// Remove removes a document from index
+func (ix *Index) Remove(id int) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+}
+
+// remove removes a document, caller must hold the write lock
+func (ix *Index) remove(id int) {
+	lengths, ok := ix.lengths[id]
+	if !ok {
+		return
+	}
+
+	for token, docs := range ix.postings {
+		delete(docs, id)
+
+		if len(docs) == 0 {
+			delete(ix.postings, token)
+		}
+	}
+
+	for field := range lengths {
+		ix.totalLength[field] -= lengths[field]
+	}
+
+	delete(ix.lengths, id)
+}
+
+// Search returns documents matching all terms and phrases of query, ordered by score descending then id
+func (ix *Index) Search(q Query) []Hit {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	tokens := q.Tokens()
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	// candidates contain every token
+	var candidates []int
+
+	for id := range ix.postings[tokens[0]] {
+		matchesAll := true
+
+		for _, token := range tokens[1:] {
+			if _, ok := ix.postings[token][id]; !ok {
+				matchesAll = false
+
+				break
+			}
+		}
+
+		if matchesAll && ix.hasPhrases(id, q.Phrases) {
+			candidates = append(candidates, id)
+		}
+	}
+
+	hits := make([]Hit, 0, len(candidates))
+
+	for _, id := range candidates {
+		hits = append(hits, Hit{ID: id, Score: ix.score(id, tokens)})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+
+		return hits[i].ID < hits[j].ID
+	})
+
+	return hits
+}
+
+// hasPhrases reports whether document contains every phrase in one of its fields
+func (ix *Index) hasPhrases(id int, phrases [][]string) bool {
+	for _, phrase := range phrases {
+		found := false
+
+		for field := 0; field < fieldCount && !found; field++ {
+			for _, start := range ix.postings[phrase[0]][id][field] {
+				if ix.phraseAt(id, field, phrase, start) {
+					found = true
+
+					break
+				}
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// phraseAt reports whether phrase tokens follow each other from start position
+func (ix *Index) phraseAt(id, field int, phrase []string, start int) bool {
+	for offset, token := range phrase[1:] {
+		positions := ix.postings[token][id][field]
+
+		i := sort.SearchInts(positions, start+offset+1)
+		if i == len(positions) || positions[i] != start+offset+1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// score returns bm25 relevance of document for tokens
+func (ix *Index) score(id int, tokens []string) float64 {
+	docs := float64(len(ix.lengths))
+
+	var score float64
+
+	for _, token := range tokens {
+		postings := ix.postings[token]
+		idf := math.Log(1 + (docs-float64(len(postings))+0.5)/(float64(len(postings))+0.5))
+
+		for field, boost := range [fieldCount]float64{titleBoost, 1} {
+			freq := float64(len(postings[id][field]))
+			if freq == 0 {
+				continue
+			}
+
+			avgLength := float64(ix.totalLength[field]) / docs
+			norm := 1 - b + b*float64(ix.lengths[id][field])/avgLength
+
+			score += boost * idf * freq * (k1 + 1) / (freq + k1*norm)
+		}
+	}
+
+	return score
+}
@@ -0,0 +1,60 @@
+package search_test
+
+import (
+	"article/internal/search"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Index(t *testing.T) {
+	ix := search.NewIndex()
+	ix.Add(1, "Go concurrency", "Worker pools and channels in Go")
+	ix.Add(2, "Cooking pasta", "Boil water and add pasta")
+	ix.Add(3, "Channels", "A pool of workers reads from channels")
+	ix.Add(4, "Removed", "Go channels")
+	ix.Remove(4)
+
+	tests := []struct {
+		name    string
+		query   string
+		wantIDs []int
+	}{
+		{
+			name:    "title match ranks higher",
+			query:   "channels",
+			wantIDs: []int{3, 1},
+		},
+		{
+			name:    "all terms must match",
+			query:   "go channels",
+			wantIDs: []int{1},
+		},
+		{
+			name:    "phrase",
+			query:   `"worker pools"`,
+			wantIDs: []int{1},
+		},
+		{
+			name:  "phrase words out of order",
+			query: `"pool worker"`,
+		},
+		{
+			name:  "no match",
+			query: "rust",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIDs []int
+
+			for _, hit := range ix.Search(search.Parse(tt.query)) {
+				assert.Greater(t, hit.Score, 0.0)
+				gotIDs = append(gotIDs, hit.ID)
+			}
+
+			assert.Equal(t, gotIDs, tt.wantIDs)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Query holds a parsed search query, every term and phrase must match
+type Query struct {
+	Terms   []string
+	Phrases [][]string
+}
+
+// Parse parses a search query, quoted text is matched as a phrase
+func Parse(s string) Query {
+	var q Query
+
+	parts := strings.Split(s, `"`)
+
+	for i, part := range parts {
+		tokens := Tokenize(part)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		// odd parts are enclosed in quotes, an unterminated quote is treated as a phrase as well
+		if i%2 == 1 && len(tokens) > 1 {
+			q.Phrases = append(q.Phrases, tokens)
+
+			continue
+		}
+
+		q.Terms = append(q.Terms, tokens...)
+	}
+
+	return q
+}
+
+// Empty reports whether query has nothing to match
+func (q Query) Empty() bool {
+	return len(q.Terms) == 0 && len(q.Phrases) == 0
+}
+
+// Tokens returns all distinct tokens of terms and phrases
+func (q Query) Tokens() []string {
+	var (
+		tokens []string
+		seen   = map[string]bool{}
+	)
+
+	add := func(token string) {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, term := range q.Terms {
+		add(term)
+	}
+
+	for _, phrase := range q.Phrases {
+		for _, token := range phrase {
+			add(token)
+		}
+	}
+
+	return tokens
+}
+
+// Tokenize splits text into lower cased words of letters and digits
+func Tokenize(text string) []string {
+	var tokens []string
+
+	for _, span := range spans(text) {
+		tokens = append(tokens, strings.ToLower(text[span.start:span.end]))
+	}
+
+	return tokens
+}
+
+// span holds byte offsets of a word in text
+type span struct {
+	start, end int
+}
+
+// spans returns byte offsets of words in text
+func spans(text string) []span {
+	var (
+		result []span
+		start  = -1
+	)
+
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+
+		if isWord && start < 0 {
+			start = i
+		}
+
+		if !isWord && start >= 0 {
+			result = append(result, span{start, i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		result = append(result, span{start, len(text)})
+	}
+
+	return result
+}
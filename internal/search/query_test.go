@@ -0,0 +1,84 @@
+package search_test
+
+import (
+	"article/internal/search"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantQuery  search.Query
+		wantTokens []string
+	}{
+		{
+			name:       "terms",
+			query:      "Go  Concurrency, patterns!",
+			wantQuery:  search.Query{Terms: []string{"go", "concurrency", "patterns"}},
+			wantTokens: []string{"go", "concurrency", "patterns"},
+		},
+		{
+			name:       "phrase and terms",
+			query:      `"worker pool" in go`,
+			wantQuery:  search.Query{Terms: []string{"in", "go"}, Phrases: [][]string{{"worker", "pool"}}},
+			wantTokens: []string{"in", "go", "worker", "pool"},
+		},
+		{
+			name:       "single word phrase is a term",
+			query:      `"go" "go routines`,
+			wantQuery:  search.Query{Terms: []string{"go"}, Phrases: [][]string{{"go", "routines"}}},
+			wantTokens: []string{"go", "routines"},
+		},
+		{
+			name:  "operators only",
+			query: `+-*" ()`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery := search.Parse(tt.query)
+
+			assert.Equal(t, gotQuery, tt.wantQuery)
+			assert.Equal(t, gotQuery.Tokens(), tt.wantTokens)
+			assert.Equal(t, gotQuery.Empty(), tt.wantTokens == nil)
+		})
+	}
+}
+
+func Test_Snippet(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		query       string
+		wantSnippet string
+	}{
+		{
+			name:        "short text",
+			text:        "Learn <Go> channels.",
+			query:       "go",
+			wantSnippet: "Learn &lt;<mark>Go</mark>&gt; channels.",
+		},
+		{
+			name:        "long text",
+			text:        "one two three four five six seven eight nine ten eleven twelve thirteen fourteen fifteen sixteen seventeen eighteen nineteen twenty twentyone twentytwo twentythree twentyfour twentyfive twentysix twentyseven twentyeight twentynine thirty thirtyone thirtytwo thirtythree thirtyfour thirtyfive thirtysix thirtyseven target",
+			query:       "target",
+			wantSnippet: "…thirtythree thirtyfour thirtyfive thirtysix thirtyseven <mark>target</mark>",
+		},
+		{
+			name:        "no match",
+			text:        "Nothing here",
+			query:       "missing",
+			wantSnippet: "Nothing here",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, search.Snippet(tt.text, search.Parse(tt.query)), tt.wantSnippet)
+		})
+	}
+}
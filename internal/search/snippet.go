@@ -0,0 +1,76 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	// snippetWords is the number of words shown in a snippet
+	snippetWords = 30
+
+	// snippetLead is the number of words shown before first match
+	snippetLead = 5
+)
+
+// Snippet returns an HTML escaped excerpt of text around first match of query,
+// matched words are wrapped in <mark> tags
+func Snippet(text string, q Query) string {
+	words := spans(text)
+	if len(words) == 0 {
+		return ""
+	}
+
+	match := map[string]bool{}
+	for _, token := range q.Tokens() {
+		match[token] = true
+	}
+
+	// start a little before first match
+	first := 0
+	for i, w := range words {
+		if match[strings.ToLower(text[w.start:w.end])] {
+			first = i
+
+			break
+		}
+	}
+
+	from := first - snippetLead
+	if from < 0 {
+		from = 0
+	}
+
+	to := from + snippetWords
+	if to > len(words) {
+		to = len(words)
+	}
+
+	var sb strings.Builder
+
+	if from > 0 {
+		sb.WriteString("…")
+	}
+
+	pos := words[from].start
+
+	for _, w := range words[from:to] {
+		sb.WriteString(html.EscapeString(text[pos:w.start]))
+
+		word := html.EscapeString(text[w.start:w.end])
+		if match[strings.ToLower(text[w.start:w.end])] {
+			word = "<mark>" + word + "</mark>"
+		}
+
+		sb.WriteString(word)
+		pos = w.end
+	}
+
+	if to < len(words) {
+		sb.WriteString("…")
+	} else {
+		sb.WriteString(html.EscapeString(text[pos:]))
+	}
+
+	return sb.String()
+}
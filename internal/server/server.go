@@ -0,0 +1,70 @@
+// Package server runs the HTTP server and shuts it down without dropping in-flight requests
+package server
+
+import (
+	"article/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// New returns server of h with timeouts and header limit of cfg, errors of the server are logged at error level
+func New(cfg config.Server, h http.Handler, logger *slog.Logger) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           h,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelError),
+	}
+}
+
+// Serve serves srv on ln until ctx is done. It then calls drain so readiness fails, waits the drain period
+// for load balancers to stop routing requests and shuts srv down, in-flight requests get the shutdown timeout to finish
+func Serve(ctx context.Context, srv *http.Server, ln net.Listener, cfg config.Server, drain func(), logger *slog.Logger) error {
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down", slog.Duration("drain_period", cfg.DrainPeriod))
+	drain()
+
+	// clients reconnect, hopefully to another instance, instead of reusing connections of this one
+	srv.SetKeepAlivesEnabled(false)
+	time.Sleep(cfg.DrainPeriod)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	err := srv.Shutdown(shutdownCtx)
+	if err != nil {
+		srv.Close()
+
+		return fmt.Errorf("error shutting down server : %w", err)
+	}
+
+	// Serve returns ErrServerClosed once shutdown starts
+	err = <-errs
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	logger.Info("server stopped")
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package server_test
+
+import (
+	"article/internal/config"
+	"article/internal/server"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Serve(t *testing.T) {
+	tests := []struct {
+		name            string
+		shutdownTimeout time.Duration
+		wantStatus      int
+		wantErr         bool
+	}{
+		{
+			name:            "success - in-flight request finishes",
+			shutdownTimeout: 5 * time.Second,
+			wantStatus:      http.StatusOK,
+		},
+		{
+			name:            "error - shutdown timeout exceeded",
+			shutdownTimeout: 50 * time.Millisecond,
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default().Server
+			cfg.DrainPeriod = 50 * time.Millisecond
+			cfg.ShutdownTimeout = tt.shutdownTimeout
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			defer close(release)
+
+			// request blocks until released or shutdown gives up on it
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+
+				select {
+				case <-release:
+				case <-time.After(500 * time.Millisecond):
+				}
+
+				w.WriteHeader(http.StatusOK)
+			})
+
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("error listening : %v", err)
+			}
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			srv := server.New(cfg, h, logger)
+
+			var drained atomic.Bool
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+
+			go func() {
+				done <- server.Serve(ctx, srv, ln, cfg, func() { drained.Store(true) }, logger)
+			}()
+
+			statuses := make(chan int, 1)
+
+			go func() {
+				resp, err := http.Get("http://" + ln.Addr().String())
+				if err != nil {
+					statuses <- 0
+
+					return
+				}
+
+				resp.Body.Close()
+				statuses <- resp.StatusCode
+			}()
+
+			<-started
+			cancel()
+
+			err = <-done
+			assert.True(t, drained.Load())
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, <-statuses, tt.wantStatus)
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	cfg := config.Default().Server
+
+	srv := server.New(cfg, http.NotFoundHandler(), slog.Default())
+
+	assert.Equal(t, srv.Addr, ":8080")
+	assert.Equal(t, srv.ReadHeaderTimeout, 5*time.Second)
+	assert.Equal(t, srv.WriteTimeout, 30*time.Second)
+	assert.Equal(t, srv.IdleTimeout, time.Minute)
+	assert.Equal(t, srv.MaxHeaderBytes, 1<<20)
+}
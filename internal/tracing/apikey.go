@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"article/internal/models"
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// apiKeyStore runs calls to the wrapped store in client spans, key prefixes and hashes are kept out of spans
+type apiKeyStore struct {
+	next   models.APIKeyStore
+	tracer trace.Tracer
+}
+
+// InstrumentAPIKeyStore returns store tracing every operation in a child span of the caller
+func InstrumentAPIKeyStore(next models.APIKeyStore) models.APIKeyStore {
+	return &apiKeyStore{next: next, tracer: otel.Tracer(instrumentation)}
+}
+
+// start starts span of operation
+func (s *apiKeyStore) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return startSpan(ctx, s.tracer, "APIKeyStore", operation)
+}
+
+func (s *apiKeyStore) Create(ctx context.Context, key *models.APIKey) (int64, error) {
+	ctx, span := s.start(ctx, "Create")
+	id, err := s.next.Create(ctx, key)
+	end(span, err)
+
+	return id, err
+}
+
+func (s *apiKeyStore) GetByID(ctx context.Context, keyID int) (*models.APIKey, error) {
+	ctx, span := s.start(ctx, "GetByID")
+	span.SetAttributes(attribute.Int("api_key.id", keyID))
+	key, err := s.next.GetByID(ctx, keyID)
+	end(span, err)
+
+	return key, err
+}
+
+func (s *apiKeyStore) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	ctx, span := s.start(ctx, "GetByPrefix")
+	key, err := s.next.GetByPrefix(ctx, prefix)
+	end(span, err)
+
+	return key, err
+}
+
+func (s *apiKeyStore) List(ctx context.Context) ([]*models.APIKey, error) {
+	ctx, span := s.start(ctx, "List")
+	keys, err := s.next.List(ctx)
+	end(span, err)
+
+	return keys, err
+}
+
+func (s *apiKeyStore) Revoke(ctx context.Context, keyID int, at time.Time) error {
+	ctx, span := s.start(ctx, "Revoke")
+	span.SetAttributes(attribute.Int("api_key.id", keyID))
+	err := s.next.Revoke(ctx, keyID, at)
+	end(span, err)
+
+	return err
+}
+
+func (s *apiKeyStore) SetExpiry(ctx context.Context, keyID int, at time.Time) error {
+	ctx, span := s.start(ctx, "SetExpiry")
+	span.SetAttributes(attribute.Int("api_key.id", keyID))
+	err := s.next.SetExpiry(ctx, keyID, at)
+	end(span, err)
+
+	return err
+}
+
+func (s *apiKeyStore) Touch(ctx context.Context, keyID int, at time.Time) error {
+	ctx, span := s.start(ctx, "Touch")
+	span.SetAttributes(attribute.Int("api_key.id", keyID))
+	err := s.next.Touch(ctx, keyID, at)
+	end(span, err)
+
+	return err
+}
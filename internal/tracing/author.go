@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"article/internal/models"
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// authorStore runs calls to the wrapped store in client spans
+type authorStore struct {
+	next   models.AuthorStore
+	tracer trace.Tracer
+}
+
+// InstrumentAuthorStore returns store tracing every operation in a child span of the caller
+func InstrumentAuthorStore(next models.AuthorStore) models.AuthorStore {
+	return &authorStore{next: next, tracer: otel.Tracer(instrumentation)}
+}
+
+// start starts span of operation
+func (s *authorStore) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return startSpan(ctx, s.tracer, "AuthorStore", operation)
+}
+
+func (s *authorStore) Create(ctx context.Context, author *models.Author) (int64, error) {
+	ctx, span := s.start(ctx, "Create")
+	id, err := s.next.Create(ctx, author)
+	end(span, err)
+
+	return id, err
+}
+
+func (s *authorStore) GetByID(ctx context.Context, authorID int) (*models.Author, error) {
+	ctx, span := s.start(ctx, "GetByID")
+	span.SetAttributes(attribute.Int("author.id", authorID))
+	author, err := s.next.GetByID(ctx, authorID)
+	end(span, err)
+
+	return author, err
+}
+
+func (s *authorStore) GetByIDs(ctx context.Context, authorIDs []int) ([]*models.Author, error) {
+	ctx, span := s.start(ctx, "GetByIDs")
+	span.SetAttributes(attribute.IntSlice("author.ids", authorIDs))
+	authors, err := s.next.GetByIDs(ctx, authorIDs)
+	end(span, err)
+
+	return authors, err
+}
+
+func (s *authorStore) List(ctx context.Context, page models.Page) ([]*models.Author, error) {
+	ctx, span := s.start(ctx, "List")
+	authors, err := s.next.List(ctx, page)
+	end(span, err)
+
+	return authors, err
+}
+
+func (s *authorStore) Count(ctx context.Context) (int64, error) {
+	ctx, span := s.start(ctx, "Count")
+	total, err := s.next.Count(ctx)
+	end(span, err)
+
+	return total, err
+}
+
+func (s *authorStore) Update(ctx context.Context, author *models.Author) error {
+	ctx, span := s.start(ctx, "Update")
+	span.SetAttributes(attribute.Int("author.id", author.ID))
+	err := s.next.Update(ctx, author)
+	end(span, err)
+
+	return err
+}
+
+func (s *authorStore) Delete(ctx context.Context, authorID int) error {
+	ctx, span := s.start(ctx, "Delete")
+	span.SetAttributes(attribute.Int("author.id", authorID))
+	err := s.next.Delete(ctx, authorID)
+	end(span, err)
+
+	return err
+}
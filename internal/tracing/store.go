@@ -0,0 +1,165 @@
+package tracing
+
+import (
+	"article/internal/models"
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// articleStore runs calls to the wrapped store in client spans, SQL stores add the statement to the span
+type articleStore struct {
+	next   models.ArticleStore
+	tracer trace.Tracer
+}
+
+// InstrumentArticleStore returns store tracing every operation in a child span of the caller
+func InstrumentArticleStore(next models.ArticleStore) models.ArticleStore {
+	return &articleStore{next: next, tracer: otel.Tracer(instrumentation)}
+}
+
+// start starts span of operation
+func (s *articleStore) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return startSpan(ctx, s.tracer, "ArticleStore", operation)
+}
+
+// startSpan starts client span of operation of store
+func startSpan(ctx context.Context, tracer trace.Tracer, store, operation string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, store+"."+operation, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.operation", operation)))
+}
+
+// end records err on span and ends it, expected outcomes such as not found are not marked as errors
+func end(span trace.Span, err error) {
+	defer span.End()
+
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+
+	switch {
+	case errors.Is(err, models.ErrNotFound), errors.Is(err, models.ErrConflict),
+		errors.Is(err, models.ErrValidation), errors.Is(err, models.ErrInvalidInput):
+		return
+	}
+
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *articleStore) Store(ctx context.Context, article *models.Article) (int64, error) {
+	ctx, span := s.start(ctx, "Store")
+	id, err := s.next.Store(ctx, article)
+	end(span, err)
+
+	return id, err
+}
+
+func (s *articleStore) GetByID(ctx context.Context, articleID int, includeDeleted bool) (*models.Article, error) {
+	ctx, span := s.start(ctx, "GetByID")
+	span.SetAttributes(attribute.Int("article.id", articleID))
+	article, err := s.next.GetByID(ctx, articleID, includeDeleted)
+	end(span, err)
+
+	return article, err
+}
+
+func (s *articleStore) List(ctx context.Context, opts models.ListOptions) ([]*models.Article, error) {
+	ctx, span := s.start(ctx, "List")
+	articles, err := s.next.List(ctx, opts)
+	end(span, err)
+
+	return articles, err
+}
+
+func (s *articleStore) Count(ctx context.Context, opts models.ListOptions) (int64, error) {
+	ctx, span := s.start(ctx, "Count")
+	total, err := s.next.Count(ctx, opts)
+	end(span, err)
+
+	return total, err
+}
+
+func (s *articleStore) Search(ctx context.Context, opts models.SearchOptions) ([]*models.SearchResult, error) {
+	ctx, span := s.start(ctx, "Search")
+	results, err := s.next.Search(ctx, opts)
+	end(span, err)
+
+	return results, err
+}
+
+func (s *articleStore) CountSearch(ctx context.Context, opts models.SearchOptions) (int64, error) {
+	ctx, span := s.start(ctx, "CountSearch")
+	total, err := s.next.CountSearch(ctx, opts)
+	end(span, err)
+
+	return total, err
+}
+
+func (s *articleStore) Update(ctx context.Context, article *models.Article) error {
+	ctx, span := s.start(ctx, "Update")
+	span.SetAttributes(attribute.Int("article.id", article.ID))
+	err := s.next.Update(ctx, article)
+	end(span, err)
+
+	return err
+}
+
+func (s *articleStore) Patch(ctx context.Context, articleID int, patch *models.ArticlePatch) error {
+	ctx, span := s.start(ctx, "Patch")
+	span.SetAttributes(attribute.Int("article.id", articleID))
+	err := s.next.Patch(ctx, articleID, patch)
+	end(span, err)
+
+	return err
+}
+
+func (s *articleStore) Delete(ctx context.Context, articleID int) error {
+	ctx, span := s.start(ctx, "Delete")
+	span.SetAttributes(attribute.Int("article.id", articleID))
+	err := s.next.Delete(ctx, articleID)
+	end(span, err)
+
+	return err
+}
+
+func (s *articleStore) Restore(ctx context.Context, articleID int) error {
+	ctx, span := s.start(ctx, "Restore")
+	span.SetAttributes(attribute.Int("article.id", articleID))
+	err := s.next.Restore(ctx, articleID)
+	end(span, err)
+
+	return err
+}
+
+func (s *articleStore) Purge(ctx context.Context, articleID int) error {
+	ctx, span := s.start(ctx, "Purge")
+	span.SetAttributes(attribute.Int("article.id", articleID))
+	err := s.next.Purge(ctx, articleID)
+	end(span, err)
+
+	return err
+}
+
+func (s *articleStore) Transition(ctx context.Context, articleID int, change *models.StatusChange) error {
+	ctx, span := s.start(ctx, "Transition")
+	span.SetAttributes(attribute.Int("article.id", articleID), attribute.String("article.transition", change.Transition))
+	err := s.next.Transition(ctx, articleID, change)
+	end(span, err)
+
+	return err
+}
+
+func (s *articleStore) History(ctx context.Context, articleID int) ([]*models.StatusChange, error) {
+	ctx, span := s.start(ctx, "History")
+	span.SetAttributes(attribute.Int("article.id", articleID))
+	changes, err := s.next.History(ctx, articleID)
+	end(span, err)
+
+	return changes, err
+}
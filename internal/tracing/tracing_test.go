@@ -0,0 +1,235 @@
+package tracing_test
+
+import (
+	"article/internal/config"
+	"article/internal/migrations"
+	"article/internal/models"
+	"article/internal/tracing"
+	"article/mocks"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	_ "modernc.org/sqlite"
+)
+
+// record installs a provider recording ended spans for the duration of the test
+func record(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+// attributes returns attributes of span by key
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return attrs
+}
+
+func Test_Middleware(t *testing.T) {
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name        string
+		path        string
+		traceparent string
+		status      int
+		wantName    string
+		wantStatus  codes.Code
+	}{
+		{
+			name:        "continues trace of traceparent",
+			path:        "/articles/1",
+			traceparent: "00-" + traceID + "-" + parentID + "-01",
+			status:      http.StatusOK,
+			wantName:    "GET /articles/{article_id}",
+		},
+		{
+			name:       "starts new trace",
+			path:       "/articles/1",
+			status:     http.StatusOK,
+			wantName:   "GET /articles/{article_id}",
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "server error",
+			path:       "/articles/1",
+			status:     http.StatusInternalServerError,
+			wantName:   "GET /articles/{article_id}",
+			wantStatus: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record(t)
+
+			var handlerTraceID string
+
+			r := chi.NewRouter()
+			r.Use(tracing.Middleware)
+			r.Get("/articles/{article_id}", func(w http.ResponseWriter, r *http.Request) {
+				handlerTraceID = tracing.TraceID(r.Context())
+				w.WriteHeader(tt.status)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+
+			span := spans[0]
+			assert.Equal(t, span.Name(), tt.wantName)
+			assert.Equal(t, span.Status().Code, tt.wantStatus)
+			assert.Equal(t, attributes(span)["http.status_code"].AsInt64(), int64(tt.status))
+
+			// trace id is echoed and handlers see the span of the request
+			assert.Equal(t, w.Header().Get(tracing.TraceIDHeader), span.SpanContext().TraceID().String())
+			assert.Equal(t, handlerTraceID, span.SpanContext().TraceID().String())
+
+			if tt.traceparent != "" {
+				assert.Equal(t, span.SpanContext().TraceID().String(), traceID)
+				assert.Equal(t, span.Parent().SpanID().String(), parentID)
+			}
+		})
+	}
+}
+
+func Test_InstrumentArticleStore(t *testing.T) {
+	recorder := record(t)
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "article.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { db.Close() })
+
+	_, err = migrations.NewSQLite(db).Up(context.Background())
+	require.NoError(t, err)
+
+	store := tracing.InstrumentArticleStore(models.NewSQLiteModels(db).Article)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+
+	id, err := store.Store(ctx, &models.Article{Title: "secret title", Content: "content", Author: "author", CreateAuthor: true})
+	require.NoError(t, err)
+
+	_, err = store.GetByID(ctx, 100, false)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	// store spans are children of the caller
+	for _, span := range spans[:2] {
+		assert.Equal(t, span.Parent().SpanID(), parent.SpanContext().SpanID())
+		assert.Equal(t, attributes(span)["db.system"].AsString(), "sqlite")
+	}
+
+	stored := spans[0]
+	assert.Equal(t, stored.Name(), "ArticleStore.Store")
+	assert.Equal(t, stored.Status().Code, codes.Unset)
+
+	// statements are on one line and values are not part of them
+	statement := attributes(stored)["db.statement"].AsString()
+	assert.True(t, strings.HasPrefix(statement, "INSERT INTO article"), statement)
+	assert.NotContains(t, statement, "\n")
+	assert.NotContains(t, statement, "secret")
+
+	// not found is an expected outcome, it is recorded without marking the span failed
+	notFound := spans[1]
+	assert.Equal(t, notFound.Name(), "ArticleStore.GetByID")
+	assert.Equal(t, attributes(notFound)["article.id"].AsInt64(), int64(100))
+	assert.Equal(t, notFound.Status().Code, codes.Unset)
+	assert.Len(t, notFound.Events(), 1)
+
+	assert.Greater(t, id, int64(0))
+}
+
+func Test_SetupFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.json")
+
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	cfg := config.Default().Tracing
+	cfg.Exporter = config.ExporterFile
+	cfg.File = file
+
+	shutdown, err := tracing.Setup(context.Background(), cfg)
+	require.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "exported")
+	span.End()
+
+	// shutdown flushes batched spans to the file
+	require.NoError(t, shutdown(context.Background()))
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"exported"`)
+	assert.Contains(t, string(data), `"Value":"article"`)
+}
+
+func Test_InstrumentAuthorAndAPIKeyStores(t *testing.T) {
+	recorder := record(t)
+
+	authorMock := mocks.NewAuthorStore(t)
+	authorMock.EXPECT().GetByID(mock.Anything, 1).Return(nil, models.ErrAuthorNotFound)
+
+	keyMock := mocks.NewAPIKeyStore(t)
+	keyMock.EXPECT().Revoke(mock.Anything, 2, mock.Anything).Return(errors.New("db error"))
+
+	_, err := tracing.InstrumentAuthorStore(authorMock).GetByID(context.Background(), 1)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	err = tracing.InstrumentAPIKeyStore(keyMock).Revoke(context.Background(), 2, time.Now())
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, spans[0].Name(), "AuthorStore.GetByID")
+	assert.Equal(t, attributes(spans[0])["author.id"].AsInt64(), int64(1))
+	assert.Equal(t, spans[0].Status().Code, codes.Unset)
+
+	assert.Equal(t, spans[1].Name(), "APIKeyStore.Revoke")
+	assert.Equal(t, attributes(spans[1])["api_key.id"].AsInt64(), int64(2))
+	assert.Equal(t, spans[1].Status().Code, codes.Error)
+}
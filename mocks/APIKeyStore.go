@@ -0,0 +1,389 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "article/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// APIKeyStore is an autogenerated mock type for the APIKeyStore type
+type APIKeyStore struct {
+	mock.Mock
+}
+
+type APIKeyStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *APIKeyStore) EXPECT() *APIKeyStore_Expecter {
+	return &APIKeyStore_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, key
+func (_m *APIKeyStore) Create(ctx context.Context, key *models.APIKey) (int64, error) {
+	ret := _m.Called(ctx, key)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.APIKey) (int64, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.APIKey) int64); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.APIKey) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// APIKeyStore_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type APIKeyStore_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//  - ctx context.Context
+//  - key *models.APIKey
+func (_e *APIKeyStore_Expecter) Create(ctx interface{}, key interface{}) *APIKeyStore_Create_Call {
+	return &APIKeyStore_Create_Call{Call: _e.mock.On("Create", ctx, key)}
+}
+
+func (_c *APIKeyStore_Create_Call) Run(run func(ctx context.Context, key *models.APIKey)) *APIKeyStore_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.APIKey))
+	})
+	return _c
+}
+
+func (_c *APIKeyStore_Create_Call) Return(_a0 int64, _a1 error) *APIKeyStore_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *APIKeyStore_Create_Call) RunAndReturn(run func(context.Context, *models.APIKey) (int64, error)) *APIKeyStore_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: ctx, keyID
+func (_m *APIKeyStore) GetByID(ctx context.Context, keyID int) (*models.APIKey, error) {
+	ret := _m.Called(ctx, keyID)
+
+	var r0 *models.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.APIKey, error)); ok {
+		return rf(ctx, keyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.APIKey); ok {
+		r0 = rf(ctx, keyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, keyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// APIKeyStore_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type APIKeyStore_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//  - ctx context.Context
+//  - keyID int
+func (_e *APIKeyStore_Expecter) GetByID(ctx interface{}, keyID interface{}) *APIKeyStore_GetByID_Call {
+	return &APIKeyStore_GetByID_Call{Call: _e.mock.On("GetByID", ctx, keyID)}
+}
+
+func (_c *APIKeyStore_GetByID_Call) Run(run func(ctx context.Context, keyID int)) *APIKeyStore_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *APIKeyStore_GetByID_Call) Return(_a0 *models.APIKey, _a1 error) *APIKeyStore_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *APIKeyStore_GetByID_Call) RunAndReturn(run func(context.Context, int) (*models.APIKey, error)) *APIKeyStore_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByPrefix provides a mock function with given fields: ctx, prefix
+func (_m *APIKeyStore) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	ret := _m.Called(ctx, prefix)
+
+	var r0 *models.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.APIKey, error)); ok {
+		return rf(ctx, prefix)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.APIKey); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// APIKeyStore_GetByPrefix_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByPrefix'
+type APIKeyStore_GetByPrefix_Call struct {
+	*mock.Call
+}
+
+// GetByPrefix is a helper method to define mock.On call
+//  - ctx context.Context
+//  - prefix string
+func (_e *APIKeyStore_Expecter) GetByPrefix(ctx interface{}, prefix interface{}) *APIKeyStore_GetByPrefix_Call {
+	return &APIKeyStore_GetByPrefix_Call{Call: _e.mock.On("GetByPrefix", ctx, prefix)}
+}
+
+func (_c *APIKeyStore_GetByPrefix_Call) Run(run func(ctx context.Context, prefix string)) *APIKeyStore_GetByPrefix_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *APIKeyStore_GetByPrefix_Call) Return(_a0 *models.APIKey, _a1 error) *APIKeyStore_GetByPrefix_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *APIKeyStore_GetByPrefix_Call) RunAndReturn(run func(context.Context, string) (*models.APIKey, error)) *APIKeyStore_GetByPrefix_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *APIKeyStore) List(ctx context.Context) ([]*models.APIKey, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*models.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*models.APIKey, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.APIKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// APIKeyStore_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type APIKeyStore_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//  - ctx context.Context
+func (_e *APIKeyStore_Expecter) List(ctx interface{}) *APIKeyStore_List_Call {
+	return &APIKeyStore_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *APIKeyStore_List_Call) Run(run func(ctx context.Context)) *APIKeyStore_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *APIKeyStore_List_Call) Return(_a0 []*models.APIKey, _a1 error) *APIKeyStore_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *APIKeyStore_List_Call) RunAndReturn(run func(context.Context) ([]*models.APIKey, error)) *APIKeyStore_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revoke provides a mock function with given fields: ctx, keyID, at
+func (_m *APIKeyStore) Revoke(ctx context.Context, keyID int, at time.Time) error {
+	ret := _m.Called(ctx, keyID, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) error); ok {
+		r0 = rf(ctx, keyID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// APIKeyStore_Revoke_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revoke'
+type APIKeyStore_Revoke_Call struct {
+	*mock.Call
+}
+
+// Revoke is a helper method to define mock.On call
+//  - ctx context.Context
+//  - keyID int
+//  - at time.Time
+func (_e *APIKeyStore_Expecter) Revoke(ctx interface{}, keyID interface{}, at interface{}) *APIKeyStore_Revoke_Call {
+	return &APIKeyStore_Revoke_Call{Call: _e.mock.On("Revoke", ctx, keyID, at)}
+}
+
+func (_c *APIKeyStore_Revoke_Call) Run(run func(ctx context.Context, keyID int, at time.Time)) *APIKeyStore_Revoke_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *APIKeyStore_Revoke_Call) Return(_a0 error) *APIKeyStore_Revoke_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *APIKeyStore_Revoke_Call) RunAndReturn(run func(context.Context, int, time.Time) error) *APIKeyStore_Revoke_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetExpiry provides a mock function with given fields: ctx, keyID, at
+func (_m *APIKeyStore) SetExpiry(ctx context.Context, keyID int, at time.Time) error {
+	ret := _m.Called(ctx, keyID, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) error); ok {
+		r0 = rf(ctx, keyID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// APIKeyStore_SetExpiry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetExpiry'
+type APIKeyStore_SetExpiry_Call struct {
+	*mock.Call
+}
+
+// SetExpiry is a helper method to define mock.On call
+//  - ctx context.Context
+//  - keyID int
+//  - at time.Time
+func (_e *APIKeyStore_Expecter) SetExpiry(ctx interface{}, keyID interface{}, at interface{}) *APIKeyStore_SetExpiry_Call {
+	return &APIKeyStore_SetExpiry_Call{Call: _e.mock.On("SetExpiry", ctx, keyID, at)}
+}
+
+func (_c *APIKeyStore_SetExpiry_Call) Run(run func(ctx context.Context, keyID int, at time.Time)) *APIKeyStore_SetExpiry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *APIKeyStore_SetExpiry_Call) Return(_a0 error) *APIKeyStore_SetExpiry_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *APIKeyStore_SetExpiry_Call) RunAndReturn(run func(context.Context, int, time.Time) error) *APIKeyStore_SetExpiry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Touch provides a mock function with given fields: ctx, keyID, at
+func (_m *APIKeyStore) Touch(ctx context.Context, keyID int, at time.Time) error {
+	ret := _m.Called(ctx, keyID, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) error); ok {
+		r0 = rf(ctx, keyID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// APIKeyStore_Touch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Touch'
+type APIKeyStore_Touch_Call struct {
+	*mock.Call
+}
+
+// Touch is a helper method to define mock.On call
+//  - ctx context.Context
+//  - keyID int
+//  - at time.Time
+func (_e *APIKeyStore_Expecter) Touch(ctx interface{}, keyID interface{}, at interface{}) *APIKeyStore_Touch_Call {
+	return &APIKeyStore_Touch_Call{Call: _e.mock.On("Touch", ctx, keyID, at)}
+}
+
+func (_c *APIKeyStore_Touch_Call) Run(run func(ctx context.Context, keyID int, at time.Time)) *APIKeyStore_Touch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *APIKeyStore_Touch_Call) Return(_a0 error) *APIKeyStore_Touch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *APIKeyStore_Touch_Call) RunAndReturn(run func(context.Context, int, time.Time) error) *APIKeyStore_Touch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewAPIKeyStore interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAPIKeyStore creates a new instance of APIKeyStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAPIKeyStore(t mockConstructorTestingTNewAPIKeyStore) *APIKeyStore {
+	mock := &APIKeyStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	models "article/internal/models"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -21,25 +22,76 @@ func (_m *ArticleStore) EXPECT() *ArticleStore_Expecter {
 	return &ArticleStore_Expecter{mock: &_m.Mock}
 }
 
-// GetAll provides a mock function with given fields:
-func (_m *ArticleStore) GetAll() ([]*models.Article, error) {
-	ret := _m.Called()
+// Count provides a mock function with given fields: ctx, opts
+func (_m *ArticleStore) Count(ctx context.Context, opts models.ListOptions) (int64, error) {
+	ret := _m.Called(ctx, opts)
 
-	var r0 []*models.Article
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]*models.Article, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, models.ListOptions) (int64, error)); ok {
+		return rf(ctx, opts)
 	}
-	if rf, ok := ret.Get(0).(func() []*models.Article); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, models.ListOptions) int64); ok {
+		r0 = rf(ctx, opts)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Article)
-		}
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.ListOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ArticleStore_Count_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Count'
+type ArticleStore_Count_Call struct {
+	*mock.Call
+}
+
+// Count is a helper method to define mock.On call
+//  - ctx context.Context
+//  - opts models.ListOptions
+func (_e *ArticleStore_Expecter) Count(ctx interface{}, opts interface{}) *ArticleStore_Count_Call {
+	return &ArticleStore_Count_Call{Call: _e.mock.On("Count", ctx, opts)}
+}
+
+func (_c *ArticleStore_Count_Call) Run(run func(ctx context.Context, opts models.ListOptions)) *ArticleStore_Count_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.ListOptions))
+	})
+	return _c
+}
+
+func (_c *ArticleStore_Count_Call) Return(_a0 int64, _a1 error) *ArticleStore_Count_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ArticleStore_Count_Call) RunAndReturn(run func(context.Context, models.ListOptions) (int64, error)) *ArticleStore_Count_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountSearch provides a mock function with given fields: ctx, opts
+func (_m *ArticleStore) CountSearch(ctx context.Context, opts models.SearchOptions) (int64, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.SearchOptions) (int64, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.SearchOptions) int64); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, models.SearchOptions) error); ok {
+		r1 = rf(ctx, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -47,52 +99,97 @@ func (_m *ArticleStore) GetAll() ([]*models.Article, error) {
 	return r0, r1
 }
 
-// ArticleStore_GetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAll'
-type ArticleStore_GetAll_Call struct {
+// ArticleStore_CountSearch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountSearch'
+type ArticleStore_CountSearch_Call struct {
 	*mock.Call
 }
 
-// GetAll is a helper method to define mock.On call
-func (_e *ArticleStore_Expecter) GetAll() *ArticleStore_GetAll_Call {
-	return &ArticleStore_GetAll_Call{Call: _e.mock.On("GetAll")}
+// CountSearch is a helper method to define mock.On call
+//  - ctx context.Context
+//  - opts models.SearchOptions
+func (_e *ArticleStore_Expecter) CountSearch(ctx interface{}, opts interface{}) *ArticleStore_CountSearch_Call {
+	return &ArticleStore_CountSearch_Call{Call: _e.mock.On("CountSearch", ctx, opts)}
 }
 
-func (_c *ArticleStore_GetAll_Call) Run(run func()) *ArticleStore_GetAll_Call {
+func (_c *ArticleStore_CountSearch_Call) Run(run func(ctx context.Context, opts models.SearchOptions)) *ArticleStore_CountSearch_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context), args[1].(models.SearchOptions))
 	})
 	return _c
 }
 
-func (_c *ArticleStore_GetAll_Call) Return(_a0 []*models.Article, _a1 error) *ArticleStore_GetAll_Call {
+func (_c *ArticleStore_CountSearch_Call) Return(_a0 int64, _a1 error) *ArticleStore_CountSearch_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *ArticleStore_GetAll_Call) RunAndReturn(run func() ([]*models.Article, error)) *ArticleStore_GetAll_Call {
+func (_c *ArticleStore_CountSearch_Call) RunAndReturn(run func(context.Context, models.SearchOptions) (int64, error)) *ArticleStore_CountSearch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, articleID
+func (_m *ArticleStore) Delete(ctx context.Context, articleID int) error {
+	ret := _m.Called(ctx, articleID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, articleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ArticleStore_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type ArticleStore_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//  - ctx context.Context
+//  - articleID int
+func (_e *ArticleStore_Expecter) Delete(ctx interface{}, articleID interface{}) *ArticleStore_Delete_Call {
+	return &ArticleStore_Delete_Call{Call: _e.mock.On("Delete", ctx, articleID)}
+}
+
+func (_c *ArticleStore_Delete_Call) Run(run func(ctx context.Context, articleID int)) *ArticleStore_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *ArticleStore_Delete_Call) Return(_a0 error) *ArticleStore_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ArticleStore_Delete_Call) RunAndReturn(run func(context.Context, int) error) *ArticleStore_Delete_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetByID provides a mock function with given fields: articleID
-func (_m *ArticleStore) GetByID(articleID int) (*models.Article, error) {
-	ret := _m.Called(articleID)
+// GetByID provides a mock function with given fields: ctx, articleID, includeDeleted
+func (_m *ArticleStore) GetByID(ctx context.Context, articleID int, includeDeleted bool) (*models.Article, error) {
+	ret := _m.Called(ctx, articleID, includeDeleted)
 
 	var r0 *models.Article
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*models.Article, error)); ok {
-		return rf(articleID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) (*models.Article, error)); ok {
+		return rf(ctx, articleID, includeDeleted)
 	}
-	if rf, ok := ret.Get(0).(func(int) *models.Article); ok {
-		r0 = rf(articleID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) *models.Article); ok {
+		r0 = rf(ctx, articleID, includeDeleted)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.Article)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(articleID)
+	if rf, ok := ret.Get(1).(func(context.Context, int, bool) error); ok {
+		r1 = rf(ctx, articleID, includeDeleted)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -106,14 +203,16 @@ type ArticleStore_GetByID_Call struct {
 }
 
 // GetByID is a helper method to define mock.On call
+//  - ctx context.Context
 //  - articleID int
-func (_e *ArticleStore_Expecter) GetByID(articleID interface{}) *ArticleStore_GetByID_Call {
-	return &ArticleStore_GetByID_Call{Call: _e.mock.On("GetByID", articleID)}
+//  - includeDeleted bool
+func (_e *ArticleStore_Expecter) GetByID(ctx interface{}, articleID interface{}, includeDeleted interface{}) *ArticleStore_GetByID_Call {
+	return &ArticleStore_GetByID_Call{Call: _e.mock.On("GetByID", ctx, articleID, includeDeleted)}
 }
 
-func (_c *ArticleStore_GetByID_Call) Run(run func(articleID int)) *ArticleStore_GetByID_Call {
+func (_c *ArticleStore_GetByID_Call) Run(run func(ctx context.Context, articleID int, includeDeleted bool)) *ArticleStore_GetByID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(bool))
 	})
 	return _c
 }
@@ -123,28 +222,323 @@ func (_c *ArticleStore_GetByID_Call) Return(_a0 *models.Article, _a1 error) *Art
 	return _c
 }
 
-func (_c *ArticleStore_GetByID_Call) RunAndReturn(run func(int) (*models.Article, error)) *ArticleStore_GetByID_Call {
+func (_c *ArticleStore_GetByID_Call) RunAndReturn(run func(context.Context, int, bool) (*models.Article, error)) *ArticleStore_GetByID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Store provides a mock function with given fields: article
-func (_m *ArticleStore) Store(article *models.Article) (int64, error) {
-	ret := _m.Called(article)
+// History provides a mock function with given fields: ctx, articleID
+func (_m *ArticleStore) History(ctx context.Context, articleID int) ([]*models.StatusChange, error) {
+	ret := _m.Called(ctx, articleID)
+
+	var r0 []*models.StatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*models.StatusChange, error)); ok {
+		return rf(ctx, articleID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*models.StatusChange); ok {
+		r0 = rf(ctx, articleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.StatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, articleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ArticleStore_History_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'History'
+type ArticleStore_History_Call struct {
+	*mock.Call
+}
+
+// History is a helper method to define mock.On call
+//  - ctx context.Context
+//  - articleID int
+func (_e *ArticleStore_Expecter) History(ctx interface{}, articleID interface{}) *ArticleStore_History_Call {
+	return &ArticleStore_History_Call{Call: _e.mock.On("History", ctx, articleID)}
+}
+
+func (_c *ArticleStore_History_Call) Run(run func(ctx context.Context, articleID int)) *ArticleStore_History_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *ArticleStore_History_Call) Return(_a0 []*models.StatusChange, _a1 error) *ArticleStore_History_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ArticleStore_History_Call) RunAndReturn(run func(context.Context, int) ([]*models.StatusChange, error)) *ArticleStore_History_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, opts
+func (_m *ArticleStore) List(ctx context.Context, opts models.ListOptions) ([]*models.Article, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 []*models.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ListOptions) ([]*models.Article, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.ListOptions) []*models.Article); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.ListOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ArticleStore_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type ArticleStore_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//  - ctx context.Context
+//  - opts models.ListOptions
+func (_e *ArticleStore_Expecter) List(ctx interface{}, opts interface{}) *ArticleStore_List_Call {
+	return &ArticleStore_List_Call{Call: _e.mock.On("List", ctx, opts)}
+}
+
+func (_c *ArticleStore_List_Call) Run(run func(ctx context.Context, opts models.ListOptions)) *ArticleStore_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.ListOptions))
+	})
+	return _c
+}
+
+func (_c *ArticleStore_List_Call) Return(_a0 []*models.Article, _a1 error) *ArticleStore_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ArticleStore_List_Call) RunAndReturn(run func(context.Context, models.ListOptions) ([]*models.Article, error)) *ArticleStore_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Patch provides a mock function with given fields: ctx, articleID, patch
+func (_m *ArticleStore) Patch(ctx context.Context, articleID int, patch *models.ArticlePatch) error {
+	ret := _m.Called(ctx, articleID, patch)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.ArticlePatch) error); ok {
+		r0 = rf(ctx, articleID, patch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ArticleStore_Patch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Patch'
+type ArticleStore_Patch_Call struct {
+	*mock.Call
+}
+
+// Patch is a helper method to define mock.On call
+//  - ctx context.Context
+//  - articleID int
+//  - patch *models.ArticlePatch
+func (_e *ArticleStore_Expecter) Patch(ctx interface{}, articleID interface{}, patch interface{}) *ArticleStore_Patch_Call {
+	return &ArticleStore_Patch_Call{Call: _e.mock.On("Patch", ctx, articleID, patch)}
+}
+
+func (_c *ArticleStore_Patch_Call) Run(run func(ctx context.Context, articleID int, patch *models.ArticlePatch)) *ArticleStore_Patch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.ArticlePatch))
+	})
+	return _c
+}
+
+func (_c *ArticleStore_Patch_Call) Return(_a0 error) *ArticleStore_Patch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ArticleStore_Patch_Call) RunAndReturn(run func(context.Context, int, *models.ArticlePatch) error) *ArticleStore_Patch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Purge provides a mock function with given fields: ctx, articleID
+func (_m *ArticleStore) Purge(ctx context.Context, articleID int) error {
+	ret := _m.Called(ctx, articleID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, articleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ArticleStore_Purge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Purge'
+type ArticleStore_Purge_Call struct {
+	*mock.Call
+}
+
+// Purge is a helper method to define mock.On call
+//  - ctx context.Context
+//  - articleID int
+func (_e *ArticleStore_Expecter) Purge(ctx interface{}, articleID interface{}) *ArticleStore_Purge_Call {
+	return &ArticleStore_Purge_Call{Call: _e.mock.On("Purge", ctx, articleID)}
+}
+
+func (_c *ArticleStore_Purge_Call) Run(run func(ctx context.Context, articleID int)) *ArticleStore_Purge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *ArticleStore_Purge_Call) Return(_a0 error) *ArticleStore_Purge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ArticleStore_Purge_Call) RunAndReturn(run func(context.Context, int) error) *ArticleStore_Purge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Restore provides a mock function with given fields: ctx, articleID
+func (_m *ArticleStore) Restore(ctx context.Context, articleID int) error {
+	ret := _m.Called(ctx, articleID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, articleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ArticleStore_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type ArticleStore_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//  - ctx context.Context
+//  - articleID int
+func (_e *ArticleStore_Expecter) Restore(ctx interface{}, articleID interface{}) *ArticleStore_Restore_Call {
+	return &ArticleStore_Restore_Call{Call: _e.mock.On("Restore", ctx, articleID)}
+}
+
+func (_c *ArticleStore_Restore_Call) Run(run func(ctx context.Context, articleID int)) *ArticleStore_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *ArticleStore_Restore_Call) Return(_a0 error) *ArticleStore_Restore_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ArticleStore_Restore_Call) RunAndReturn(run func(context.Context, int) error) *ArticleStore_Restore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Search provides a mock function with given fields: ctx, opts
+func (_m *ArticleStore) Search(ctx context.Context, opts models.SearchOptions) ([]*models.SearchResult, error) {
+	ret := _m.Called(ctx, opts)
+
+	var r0 []*models.SearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.SearchOptions) ([]*models.SearchResult, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.SearchOptions) []*models.SearchResult); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.SearchOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ArticleStore_Search_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Search'
+type ArticleStore_Search_Call struct {
+	*mock.Call
+}
+
+// Search is a helper method to define mock.On call
+//  - ctx context.Context
+//  - opts models.SearchOptions
+func (_e *ArticleStore_Expecter) Search(ctx interface{}, opts interface{}) *ArticleStore_Search_Call {
+	return &ArticleStore_Search_Call{Call: _e.mock.On("Search", ctx, opts)}
+}
+
+func (_c *ArticleStore_Search_Call) Run(run func(ctx context.Context, opts models.SearchOptions)) *ArticleStore_Search_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.SearchOptions))
+	})
+	return _c
+}
+
+func (_c *ArticleStore_Search_Call) Return(_a0 []*models.SearchResult, _a1 error) *ArticleStore_Search_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ArticleStore_Search_Call) RunAndReturn(run func(context.Context, models.SearchOptions) ([]*models.SearchResult, error)) *ArticleStore_Search_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Store provides a mock function with given fields: ctx, article
+func (_m *ArticleStore) Store(ctx context.Context, article *models.Article) (int64, error) {
+	ret := _m.Called(ctx, article)
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*models.Article) (int64, error)); ok {
-		return rf(article)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Article) (int64, error)); ok {
+		return rf(ctx, article)
 	}
-	if rf, ok := ret.Get(0).(func(*models.Article) int64); ok {
-		r0 = rf(article)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Article) int64); ok {
+		r0 = rf(ctx, article)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(*models.Article) error); ok {
-		r1 = rf(article)
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Article) error); ok {
+		r1 = rf(ctx, article)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -158,14 +552,15 @@ type ArticleStore_Store_Call struct {
 }
 
 // Store is a helper method to define mock.On call
+//  - ctx context.Context
 //  - article *models.Article
-func (_e *ArticleStore_Expecter) Store(article interface{}) *ArticleStore_Store_Call {
-	return &ArticleStore_Store_Call{Call: _e.mock.On("Store", article)}
+func (_e *ArticleStore_Expecter) Store(ctx interface{}, article interface{}) *ArticleStore_Store_Call {
+	return &ArticleStore_Store_Call{Call: _e.mock.On("Store", ctx, article)}
 }
 
-func (_c *ArticleStore_Store_Call) Run(run func(article *models.Article)) *ArticleStore_Store_Call {
+func (_c *ArticleStore_Store_Call) Run(run func(ctx context.Context, article *models.Article)) *ArticleStore_Store_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*models.Article))
+		run(args[0].(context.Context), args[1].(*models.Article))
 	})
 	return _c
 }
@@ -175,7 +570,94 @@ func (_c *ArticleStore_Store_Call) Return(_a0 int64, _a1 error) *ArticleStore_St
 	return _c
 }
 
-func (_c *ArticleStore_Store_Call) RunAndReturn(run func(*models.Article) (int64, error)) *ArticleStore_Store_Call {
+func (_c *ArticleStore_Store_Call) RunAndReturn(run func(context.Context, *models.Article) (int64, error)) *ArticleStore_Store_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Transition provides a mock function with given fields: ctx, articleID, change
+func (_m *ArticleStore) Transition(ctx context.Context, articleID int, change *models.StatusChange) error {
+	ret := _m.Called(ctx, articleID, change)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.StatusChange) error); ok {
+		r0 = rf(ctx, articleID, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ArticleStore_Transition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Transition'
+type ArticleStore_Transition_Call struct {
+	*mock.Call
+}
+
+// Transition is a helper method to define mock.On call
+//  - ctx context.Context
+//  - articleID int
+//  - change *models.StatusChange
+func (_e *ArticleStore_Expecter) Transition(ctx interface{}, articleID interface{}, change interface{}) *ArticleStore_Transition_Call {
+	return &ArticleStore_Transition_Call{Call: _e.mock.On("Transition", ctx, articleID, change)}
+}
+
+func (_c *ArticleStore_Transition_Call) Run(run func(ctx context.Context, articleID int, change *models.StatusChange)) *ArticleStore_Transition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.StatusChange))
+	})
+	return _c
+}
+
+func (_c *ArticleStore_Transition_Call) Return(_a0 error) *ArticleStore_Transition_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ArticleStore_Transition_Call) RunAndReturn(run func(context.Context, int, *models.StatusChange) error) *ArticleStore_Transition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, article
+func (_m *ArticleStore) Update(ctx context.Context, article *models.Article) error {
+	ret := _m.Called(ctx, article)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Article) error); ok {
+		r0 = rf(ctx, article)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ArticleStore_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type ArticleStore_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//  - ctx context.Context
+//  - article *models.Article
+func (_e *ArticleStore_Expecter) Update(ctx interface{}, article interface{}) *ArticleStore_Update_Call {
+	return &ArticleStore_Update_Call{Call: _e.mock.On("Update", ctx, article)}
+}
+
+func (_c *ArticleStore_Update_Call) Run(run func(ctx context.Context, article *models.Article)) *ArticleStore_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Article))
+	})
+	return _c
+}
+
+func (_c *ArticleStore_Update_Call) Return(_a0 error) *ArticleStore_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ArticleStore_Update_Call) RunAndReturn(run func(context.Context, *models.Article) error) *ArticleStore_Update_Call {
 	_c.Call.Return(run)
 	return _c
 }
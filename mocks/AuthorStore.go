@@ -0,0 +1,394 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "article/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuthorStore is an autogenerated mock type for the AuthorStore type
+type AuthorStore struct {
+	mock.Mock
+}
+
+type AuthorStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AuthorStore) EXPECT() *AuthorStore_Expecter {
+	return &AuthorStore_Expecter{mock: &_m.Mock}
+}
+
+// Count provides a mock function with given fields: ctx
+func (_m *AuthorStore) Count(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthorStore_Count_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Count'
+type AuthorStore_Count_Call struct {
+	*mock.Call
+}
+
+// Count is a helper method to define mock.On call
+//  - ctx context.Context
+func (_e *AuthorStore_Expecter) Count(ctx interface{}) *AuthorStore_Count_Call {
+	return &AuthorStore_Count_Call{Call: _e.mock.On("Count", ctx)}
+}
+
+func (_c *AuthorStore_Count_Call) Run(run func(ctx context.Context)) *AuthorStore_Count_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *AuthorStore_Count_Call) Return(_a0 int64, _a1 error) *AuthorStore_Count_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuthorStore_Count_Call) RunAndReturn(run func(context.Context) (int64, error)) *AuthorStore_Count_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function with given fields: ctx, author
+func (_m *AuthorStore) Create(ctx context.Context, author *models.Author) (int64, error) {
+	ret := _m.Called(ctx, author)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Author) (int64, error)); ok {
+		return rf(ctx, author)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Author) int64); ok {
+		r0 = rf(ctx, author)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Author) error); ok {
+		r1 = rf(ctx, author)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthorStore_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type AuthorStore_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//  - ctx context.Context
+//  - author *models.Author
+func (_e *AuthorStore_Expecter) Create(ctx interface{}, author interface{}) *AuthorStore_Create_Call {
+	return &AuthorStore_Create_Call{Call: _e.mock.On("Create", ctx, author)}
+}
+
+func (_c *AuthorStore_Create_Call) Run(run func(ctx context.Context, author *models.Author)) *AuthorStore_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Author))
+	})
+	return _c
+}
+
+func (_c *AuthorStore_Create_Call) Return(_a0 int64, _a1 error) *AuthorStore_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuthorStore_Create_Call) RunAndReturn(run func(context.Context, *models.Author) (int64, error)) *AuthorStore_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, authorID
+func (_m *AuthorStore) Delete(ctx context.Context, authorID int) error {
+	ret := _m.Called(ctx, authorID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, authorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AuthorStore_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type AuthorStore_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//  - ctx context.Context
+//  - authorID int
+func (_e *AuthorStore_Expecter) Delete(ctx interface{}, authorID interface{}) *AuthorStore_Delete_Call {
+	return &AuthorStore_Delete_Call{Call: _e.mock.On("Delete", ctx, authorID)}
+}
+
+func (_c *AuthorStore_Delete_Call) Run(run func(ctx context.Context, authorID int)) *AuthorStore_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *AuthorStore_Delete_Call) Return(_a0 error) *AuthorStore_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AuthorStore_Delete_Call) RunAndReturn(run func(context.Context, int) error) *AuthorStore_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: ctx, authorID
+func (_m *AuthorStore) GetByID(ctx context.Context, authorID int) (*models.Author, error) {
+	ret := _m.Called(ctx, authorID)
+
+	var r0 *models.Author
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.Author, error)); ok {
+		return rf(ctx, authorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.Author); ok {
+		r0 = rf(ctx, authorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Author)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthorStore_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type AuthorStore_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//  - ctx context.Context
+//  - authorID int
+func (_e *AuthorStore_Expecter) GetByID(ctx interface{}, authorID interface{}) *AuthorStore_GetByID_Call {
+	return &AuthorStore_GetByID_Call{Call: _e.mock.On("GetByID", ctx, authorID)}
+}
+
+func (_c *AuthorStore_GetByID_Call) Run(run func(ctx context.Context, authorID int)) *AuthorStore_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *AuthorStore_GetByID_Call) Return(_a0 *models.Author, _a1 error) *AuthorStore_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuthorStore_GetByID_Call) RunAndReturn(run func(context.Context, int) (*models.Author, error)) *AuthorStore_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByIDs provides a mock function with given fields: ctx, authorIDs
+func (_m *AuthorStore) GetByIDs(ctx context.Context, authorIDs []int) ([]*models.Author, error) {
+	ret := _m.Called(ctx, authorIDs)
+
+	var r0 []*models.Author
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) ([]*models.Author, error)); ok {
+		return rf(ctx, authorIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) []*models.Author); ok {
+		r0 = rf(ctx, authorIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Author)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, authorIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthorStore_GetByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByIDs'
+type AuthorStore_GetByIDs_Call struct {
+	*mock.Call
+}
+
+// GetByIDs is a helper method to define mock.On call
+//  - ctx context.Context
+//  - authorIDs []int
+func (_e *AuthorStore_Expecter) GetByIDs(ctx interface{}, authorIDs interface{}) *AuthorStore_GetByIDs_Call {
+	return &AuthorStore_GetByIDs_Call{Call: _e.mock.On("GetByIDs", ctx, authorIDs)}
+}
+
+func (_c *AuthorStore_GetByIDs_Call) Run(run func(ctx context.Context, authorIDs []int)) *AuthorStore_GetByIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int))
+	})
+	return _c
+}
+
+func (_c *AuthorStore_GetByIDs_Call) Return(_a0 []*models.Author, _a1 error) *AuthorStore_GetByIDs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuthorStore_GetByIDs_Call) RunAndReturn(run func(context.Context, []int) ([]*models.Author, error)) *AuthorStore_GetByIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, page
+func (_m *AuthorStore) List(ctx context.Context, page models.Page) ([]*models.Author, error) {
+	ret := _m.Called(ctx, page)
+
+	var r0 []*models.Author
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Page) ([]*models.Author, error)); ok {
+		return rf(ctx, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Page) []*models.Author); ok {
+		r0 = rf(ctx, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Author)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Page) error); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthorStore_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type AuthorStore_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//  - ctx context.Context
+//  - page models.Page
+func (_e *AuthorStore_Expecter) List(ctx interface{}, page interface{}) *AuthorStore_List_Call {
+	return &AuthorStore_List_Call{Call: _e.mock.On("List", ctx, page)}
+}
+
+func (_c *AuthorStore_List_Call) Run(run func(ctx context.Context, page models.Page)) *AuthorStore_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Page))
+	})
+	return _c
+}
+
+func (_c *AuthorStore_List_Call) Return(_a0 []*models.Author, _a1 error) *AuthorStore_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuthorStore_List_Call) RunAndReturn(run func(context.Context, models.Page) ([]*models.Author, error)) *AuthorStore_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, author
+func (_m *AuthorStore) Update(ctx context.Context, author *models.Author) error {
+	ret := _m.Called(ctx, author)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Author) error); ok {
+		r0 = rf(ctx, author)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AuthorStore_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type AuthorStore_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//  - ctx context.Context
+//  - author *models.Author
+func (_e *AuthorStore_Expecter) Update(ctx interface{}, author interface{}) *AuthorStore_Update_Call {
+	return &AuthorStore_Update_Call{Call: _e.mock.On("Update", ctx, author)}
+}
+
+func (_c *AuthorStore_Update_Call) Run(run func(ctx context.Context, author *models.Author)) *AuthorStore_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Author))
+	})
+	return _c
+}
+
+func (_c *AuthorStore_Update_Call) Return(_a0 error) *AuthorStore_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AuthorStore_Update_Call) RunAndReturn(run func(context.Context, *models.Author) error) *AuthorStore_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewAuthorStore interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuthorStore creates a new instance of AuthorStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuthorStore(t mockConstructorTestingTNewAuthorStore) *AuthorStore {
+	mock := &AuthorStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}